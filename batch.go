@@ -0,0 +1,474 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"context"
+	"io"
+	"reflect"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// VectorKind is the kind of the values of a Vector.
+type VectorKind uint8
+
+const (
+	// VectorAny values are in Vector.Values, boxed as in Rset.Row.
+	VectorAny VectorKind = iota
+	// VectorInt64 values are in Vector.Int64s.
+	VectorInt64
+	// VectorFloat64 values are in Vector.Float64s.
+	VectorFloat64
+	// VectorString values are UTF-8 text in Vector.Data, delimited by
+	// Vector.Offsets.
+	VectorString
+	// VectorBytes values are in Vector.Data, delimited by Vector.Offsets.
+	VectorBytes
+	// VectorTime values are in Vector.Int64s, as microseconds since the Unix
+	// epoch, in UTC.
+	VectorTime
+)
+
+var vectorKindNames = [...]string{"Any", "Int64", "Float64", "String", "Bytes", "Time"}
+
+func (k VectorKind) String() string {
+	if int(k) < len(vectorKindNames) {
+		return vectorKindNames[k]
+	}
+	return "VectorKind(?)"
+}
+
+// Vector holds the values of a column for the rows of a Batch.
+//
+// The buffers follow the Apache Arrow columnar layout, so they can be handed
+// to Arrow without conversion: Validity is the validity bitmap; Int64s and
+// Float64s are the buffers of the int64, float64 and timestamp[us, UTC]
+// arrays; Offsets and Data are the buffers of the utf8 and binary arrays.
+// The values of NULL rows are zero (or empty).
+type Vector struct {
+	Name string
+	Kind VectorKind
+
+	// Validity has the bit i (in least significant bit order) set when the
+	// value of the row i is not NULL.
+	Validity  []byte
+	NullCount int
+
+	Int64s   []int64
+	Float64s []float64
+
+	// Offsets has the Len+1 offsets in Data of the values.
+	Offsets []int32
+	Data    []byte
+
+	Values []interface{}
+}
+
+// IsNull reports whether the value of the row i is NULL.
+func (v *Vector) IsNull(i int) bool { return v.Validity[i>>3]&(1<<uint(i&7)) == 0 }
+
+// Bytes returns the value of the row i of a VectorString or VectorBytes,
+// which is valid until the next Rset.NextBatch.
+func (v *Vector) Bytes(i int) []byte { return v.Data[v.Offsets[i]:v.Offsets[i+1]] }
+
+// String returns the value of the row i of a VectorString or VectorBytes.
+func (v *Vector) String(i int) string { return string(v.Bytes(i)) }
+
+// Time returns the value of the row i of a VectorTime, in UTC.
+func (v *Vector) Time(i int) time.Time {
+	us := v.Int64s[i]
+	return time.Unix(us/1e6, us%1e6*1e3).UTC()
+}
+
+// Value returns the value of the row i boxed: nil for NULL, otherwise
+// int64, float64, string, []byte, time.Time or the value of a VectorAny.
+func (v *Vector) Value(i int) interface{} {
+	if v.Kind != VectorAny && v.IsNull(i) {
+		return nil
+	}
+	switch v.Kind {
+	case VectorInt64:
+		return v.Int64s[i]
+	case VectorFloat64:
+		return v.Float64s[i]
+	case VectorString:
+		return v.String(i)
+	case VectorBytes:
+		return append([]byte(nil), v.Bytes(i)...)
+	case VectorTime:
+		return v.Time(i)
+	}
+	return v.Values[i]
+}
+
+// reset empties the vector, keeping its buffers.
+func (v *Vector) reset() {
+	v.Validity, v.NullCount = v.Validity[:0], 0
+	v.Int64s, v.Float64s = v.Int64s[:0], v.Float64s[:0]
+	v.Data, v.Values = v.Data[:0], v.Values[:0]
+	v.Offsets = append(v.Offsets[:0], 0)
+}
+
+// appendValidity appends the validity of the row i.
+func (v *Vector) appendValidity(i int, isNull bool) {
+	if i&7 == 0 {
+		v.Validity = append(v.Validity, 0)
+	}
+	if isNull {
+		v.NullCount++
+		return
+	}
+	v.Validity[i>>3] |= 1 << uint(i&7)
+}
+
+// appendData appends p to Data, and its end to Offsets.
+func (v *Vector) appendData(p []byte) {
+	v.Data = append(v.Data, p...)
+	v.Offsets = append(v.Offsets, int32(len(v.Data)))
+}
+
+// Batch holds rows of an Rset in columns, see Rset.NextBatch.
+type Batch struct {
+	Len     int // the number of rows
+	Columns []Vector
+}
+
+// NextBatch fetches the next at most n rows (fetch length rows if n <= 0) into
+// a Batch, converting the values straight from the define buffers into the
+// typed column vectors, without boxing them into Rset.Row.
+//
+// NUMBER columns defined as integers and floats, CHAR, VARCHAR2, LONG, RAW,
+// LONG RAW, DATE and TIMESTAMP columns have typed vectors; the values of the
+// other columns (LOBs, intervals, OCINum, Bool, etc.) are boxed into
+// Vector.Values.
+//
+// The returned Batch, and its buffers, are reused by the next call of
+// NextBatch. After the last rows, NextBatch returns a nil Batch and io.EOF,
+// and closes the Rset.
+func (rset *Rset) NextBatch(n int) (*Batch, error) {
+	return rset.nextBatch(rset.ctx, n)
+}
+
+// NextBatchContext is like NextBatch, but a fetch is broken when ctx is done.
+func (rset *Rset) NextBatchContext(ctx context.Context, n int) (*Batch, error) {
+	return rset.nextBatch(ctx, n)
+}
+
+func (rset *Rset) nextBatch(ctx context.Context, n int) (*Batch, error) {
+	rset.log(_drv.Cfg().Log.Rset.Next)
+	if !rset.IsOpen() {
+		if err := rset.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	rset.Lock()
+	if n <= 0 {
+		n = rset.fetchLen
+	}
+	b := rset.batch
+	if b == nil {
+		b = &Batch{Columns: make([]Vector, len(rset.defs))}
+		for i, d := range rset.defs {
+			b.Columns[i].Name = rset.Columns[i].Name
+			b.Columns[i].Kind = vectorKind(d)
+		}
+		rset.batch = b
+	}
+	rset.Row = nil
+	rset.noRow = false
+	rset.Unlock()
+
+	b.Len = 0
+	for i := range b.Columns {
+		b.Columns[i].reset()
+	}
+	for b.Len < n {
+		err := rset.beginRowC(ctx)
+		if err != nil {
+			rset.endRow()
+			if err == io.EOF && b.Len > 0 {
+				// the Rset is closed by the next call
+				break
+			}
+			if err == io.EOF {
+				err = nil
+			}
+			rset.erase(err)
+			if err == nil {
+				err = io.EOF
+			}
+			return nil, err
+		}
+		rset.Lock()
+		offset, k := int(rset.offset), n-b.Len
+		if avail := int(rset.fetched - rset.offset); k > avail {
+			k = avail
+		}
+		// endRow steps over the last row
+		rset.offset += int64(k - 1)
+		rset.pos += int64(k - 1)
+		atomic.AddInt32(&rset.index, int32(k-1))
+		defs := rset.defs
+		rset.Unlock()
+		for i, d := range defs {
+			if err = b.Columns[i].appendDef(d, b.Len, offset, k); err != nil {
+				break
+			}
+		}
+		rset.endRow()
+		if err != nil {
+			rset.erase(err)
+			return nil, err
+		}
+		b.Len += k
+	}
+	return b, nil
+}
+
+// vectorKind returns the kind of Vector for the values of d.
+func vectorKind(d def) VectorKind {
+	switch d.(type) {
+	case *defInt64, *defInt32, *defInt16, *defInt8, *defUint32, *defUint16, *defUint8:
+		return VectorInt64
+	case *defFloat64, *defFloat32:
+		return VectorFloat64
+	case *defString, *defNumString, *defLong:
+		return VectorString
+	case *defRaw, *defLongRaw:
+		return VectorBytes
+	case *defDate, *defTime:
+		return VectorTime
+	}
+	return VectorAny
+}
+
+// appendDef appends the values of the k rows of d from offset; row is the
+// index of the first in the Batch.
+func (v *Vector) appendDef(d def, row, offset, k int) error {
+	switch d := d.(type) {
+	case *defInt64:
+		return v.appendInts(d.ociNumber, d.nullInds, d.rset.env, row, offset, k)
+	case *defInt32:
+		return v.appendInts(d.ociNumber, d.nullInds, d.rset.env, row, offset, k)
+	case *defInt16:
+		return v.appendInts(d.ociNumber, d.nullInds, d.rset.env, row, offset, k)
+	case *defInt8:
+		return v.appendInts(d.ociNumber, d.nullInds, d.rset.env, row, offset, k)
+	case *defUint32:
+		return v.appendInts(d.ociNumber, d.nullInds, d.rset.env, row, offset, k)
+	case *defUint16:
+		return v.appendInts(d.ociNumber, d.nullInds, d.rset.env, row, offset, k)
+	case *defUint8:
+		return v.appendInts(d.ociNumber, d.nullInds, d.rset.env, row, offset, k)
+	case *defFloat64:
+		return v.appendFloats(d.ociNumber, d.nullInds, d.rset.env, row, offset, k)
+	case *defFloat32:
+		return v.appendFloats(d.ociNumber, d.nullInds, d.rset.env, row, offset, k)
+	case *defNumString:
+		return v.appendStrings(&d.defString, row, offset, k)
+	case *defString:
+		return v.appendStrings(d, row, offset, k)
+	case *defLong:
+		env := d.rset.env
+		for i := offset; i < offset+k; i++ {
+			isNull := d.isNull(i)
+			v.appendValidity(row+i-offset, isNull)
+			if isNull || env.charset.isUTF8() {
+				if isNull {
+					v.appendData(nil)
+				} else {
+					v.appendData(d.values[i])
+				}
+				continue
+			}
+			s, err := env.charset.decode(d.values[i])
+			if err != nil {
+				return err
+			}
+			v.appendData([]byte(s))
+		}
+		return nil
+	case *defLongRaw:
+		for i := offset; i < offset+k; i++ {
+			isNull := d.isNull(i)
+			v.appendValidity(row+i-offset, isNull)
+			if isNull {
+				v.appendData(nil)
+			} else {
+				v.appendData(d.values[i])
+			}
+		}
+		return nil
+	case *defRaw:
+		for i := offset; i < offset+k; i++ {
+			isNull := d.nullInds[i] < 0
+			v.appendValidity(row+i-offset, isNull)
+			if isNull {
+				v.appendData(nil)
+				continue
+			}
+			off := i * d.columnSize
+			v.appendData(d.buf[off : off+int(d.alen[i])])
+		}
+		return nil
+	case *defDate:
+		for i := offset; i < offset+k; i++ {
+			isNull := d.nullInds[i] < 0
+			v.appendValidity(row+i-offset, isNull)
+			var us int64
+			if !isNull {
+				us = unixMicro(d.ociDate[i].GetIn(d.timezone))
+			}
+			v.Int64s = append(v.Int64s, us)
+		}
+		return nil
+	case *defTime:
+		env := d.rset.env
+		for i := offset; i < offset+k; i++ {
+			isNull := d.nullInds[i] < 0
+			v.appendValidity(row+i-offset, isNull)
+			var us int64
+			if !isNull {
+				t, err := getTime(env, d.dates[i])
+				if err != nil {
+					return err
+				}
+				us = unixMicro(t)
+			}
+			v.Int64s = append(v.Int64s, us)
+		}
+		return nil
+	}
+
+	// box the others
+	for i := offset; i < offset+k; i++ {
+		value, err := d.value(i)
+		if err != nil {
+			return err
+		}
+		v.appendValidity(row+i-offset, isNullValue(value))
+		v.Values = append(v.Values, value)
+	}
+	return nil
+}
+
+// appendInts appends the OCINumbers as int64s.
+func (v *Vector) appendInts(nums []C.OCINumber, nullInds []C.sb2, env *Env, row, offset, k int) error {
+	for i := offset; i < offset+k; i++ {
+		isNull := nullInds[i] < 0
+		v.appendValidity(row+i-offset, isNull)
+		var i64 int64
+		if !isNull {
+			var err error
+			if i64, err = numberInt64(env, &nums[i]); err != nil {
+				return err
+			}
+		}
+		v.Int64s = append(v.Int64s, i64)
+	}
+	return nil
+}
+
+// appendFloats appends the OCINumbers as float64s.
+func (v *Vector) appendFloats(nums []C.OCINumber, nullInds []C.sb2, env *Env, row, offset, k int) error {
+	for i := offset; i < offset+k; i++ {
+		isNull := nullInds[i] < 0
+		v.appendValidity(row+i-offset, isNull)
+		var f64 float64
+		if !isNull {
+			var err error
+			if f64, err = numberFloat64(env, &nums[i]); err != nil {
+				return err
+			}
+		}
+		v.Float64s = append(v.Float64s, f64)
+	}
+	return nil
+}
+
+// appendStrings appends the strings of d, decoded to UTF-8.
+func (v *Vector) appendStrings(d *defString, row, offset, k int) error {
+	d.RLock()
+	defer d.RUnlock()
+	for i := offset; i < offset+k; i++ {
+		isNull := d.nullInds[i] < 0
+		v.appendValidity(row+i-offset, isNull)
+		if isNull {
+			v.appendData(nil)
+			continue
+		}
+		p, err := d.bytes(i)
+		if err != nil {
+			return err
+		}
+		v.appendData(p)
+	}
+	return nil
+}
+
+// numberInt64 converts the OCINumber to an int64.
+func numberInt64(env *Env, num *C.OCINumber) (i64 int64, err error) {
+	if C.OCINumberToInt(
+		env.ocierr,           //OCIError              *err,
+		num,                  //const OCINumber       *number,
+		byteWidth64,          //uword                 rsl_length,
+		C.OCI_NUMBER_SIGNED,  //uword                 rsl_flag,
+		unsafe.Pointer(&i64), //void                  *rsl );
+	) == C.OCI_ERROR {
+		return 0, env.ociError()
+	}
+	return i64, nil
+}
+
+// numberFloat64 converts the OCINumber to a float64.
+func numberFloat64(env *Env, num *C.OCINumber) (f64 float64, err error) {
+	if C.OCINumberToReal(
+		env.ocierr,           //OCIError              *err,
+		num,                  //const OCINumber     *number,
+		byteWidth64,          //uword               rsl_length,
+		unsafe.Pointer(&f64), //void                *rsl );
+	) == C.OCI_ERROR {
+		return 0, env.ociError()
+	}
+	return f64, nil
+}
+
+// unixMicro returns t as microseconds since the Unix epoch.
+func unixMicro(t time.Time) int64 {
+	return t.Unix()*1e6 + int64(t.Nanosecond()/1e3)
+}
+
+// isNullValue reports whether the boxed value of a def is NULL.
+func isNullValue(value interface{}) bool {
+	switch x := value.(type) {
+	case nil:
+		return true
+	case interface {
+		IsNull() bool
+	}:
+		return x.IsNull()
+	case *Lob:
+		return x == nil || x.Reader == nil
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		return rv.IsNil()
+	case reflect.Struct:
+		// the nullable types of this package
+		if f := rv.FieldByName("IsNull"); f.IsValid() && f.Kind() == reflect.Bool {
+			return f.Bool()
+		}
+	}
+	return false
+}
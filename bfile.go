@@ -0,0 +1,245 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <stdlib.h>
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// BfileExists reports whether the file of the Bfile exists on the server,
+// and is readable by it.
+func (ses *Ses) BfileExists(bf Bfile) (exists bool, err error) {
+	err = ses.withBfile(bf, func(svc *C.OCISvcCtx, env *Env, lob *C.OCILobLocator) error {
+		var flag C.boolean
+		if C.OCILobFileExists(
+			svc,        //OCISvcCtx          *svchp,
+			env.ocierr, //OCIError           *errhp,
+			lob,        //OCILobLocator      *filep,
+			&flag,      //boolean            *flag );
+		) == C.OCI_ERROR {
+			return env.ociError("OCILobFileExists")
+		}
+		exists = flag == C.TRUE
+		return nil
+	})
+	return exists, err
+}
+
+// BfileSize returns the length of the file of the Bfile in bytes.
+func (ses *Ses) BfileSize(bf Bfile) (size int64, err error) {
+	err = ses.withBfile(bf, func(svc *C.OCISvcCtx, env *Env, lob *C.OCILobLocator) error {
+		var length C.oraub8
+		if C.OCILobGetLength2(
+			svc,        //OCISvcCtx          *svchp,
+			env.ocierr, //OCIError           *errhp,
+			lob,        //OCILobLocator      *locp,
+			&length,    //oraub8             *lenp );
+		) == C.OCI_ERROR {
+			return env.ociError("OCILobGetLength2")
+		}
+		size = int64(length)
+		return nil
+	})
+	return size, err
+}
+
+// OpenBfile opens the file of the Bfile for reading.
+//
+// The returned io.ReadCloser must be closed, before closing the session.
+func (ses *Ses) OpenBfile(bf Bfile) (io.ReadCloser, error) {
+	if err := ses.checkClosed(); err != nil {
+		return nil, errE(err)
+	}
+	br := &bfileReader{ses: ses, timeout: ses.Cfg().StmtCfg.callTimeout}
+	lob, err := ses.bfileLocator(bf)
+	if err != nil {
+		return nil, err
+	}
+	err = br.do(func(svc *C.OCISvcCtx, env *Env) error {
+		if C.OCILobFileOpen(
+			svc,                 //OCISvcCtx          *svchp,
+			env.ocierr,          //OCIError           *errhp,
+			lob,                 //OCILobLocator      *filep,
+			C.OCI_FILE_READONLY, //ub1                mode );
+		) == C.OCI_ERROR {
+			return env.ociError("OCILobFileOpen")
+		}
+		return nil
+	})
+	if err != nil {
+		freeBfileLocator(lob)
+		return nil, err
+	}
+	br.lob = lob
+	return br, nil
+}
+
+// withBfile calls f with a locator of bf, in a call limited by the call
+// timeout.
+func (ses *Ses) withBfile(bf Bfile, f func(svc *C.OCISvcCtx, env *Env, lob *C.OCILobLocator) error) error {
+	if err := ses.checkClosed(); err != nil {
+		return errE(err)
+	}
+	lob, err := ses.bfileLocator(bf)
+	if err != nil {
+		return err
+	}
+	defer freeBfileLocator(lob)
+	ses.RLock()
+	svc, env := ses.ocisvcctx, ses.srv.env
+	ses.RUnlock()
+	call, err := ses.startCall(context.Background(), ses.Cfg().StmtCfg.callTimeout)
+	if err != nil {
+		return err
+	}
+	return call.end(f(svc, env, lob))
+}
+
+// bfileLocator returns a new locator for the file of bf.
+func (ses *Ses) bfileLocator(bf Bfile) (*C.OCILobLocator, error) {
+	if bf.IsNull {
+		return nil, errNew("Bfile is null")
+	}
+	if bf.DirectoryAlias == "" || bf.Filename == "" {
+		return nil, errNew("DirectoryAlias and Filename must be specified")
+	}
+	env := ses.Env()
+	var lob *C.OCILobLocator
+	r := C.OCIDescriptorAlloc(
+		unsafe.Pointer(env.ocienv),              //CONST dvoid   *parenth,
+		(*unsafe.Pointer)(unsafe.Pointer(&lob)), //dvoid         **descpp,
+		C.OCI_DTYPE_FILE,                        //ub4           type,
+		0,                                       //size_t        xtramem_sz,
+		nil)                                     //dvoid         **usrmempp);
+	if r == C.OCI_ERROR {
+		return nil, env.ociError("OCIDescriptorAlloc")
+	} else if r == C.OCI_INVALID_HANDLE {
+		return nil, errNew("unable to allocate oci lob handle for Bfile")
+	}
+	cDirectoryAlias := C.CString(bf.DirectoryAlias)
+	defer C.free(unsafe.Pointer(cDirectoryAlias))
+	cFilename := C.CString(bf.Filename)
+	defer C.free(unsafe.Pointer(cFilename))
+	if C.OCILobFileSetName(
+		env.ocienv, //OCIEnv             *envhp,
+		env.ocierr, //OCIError           *errhp,
+		&lob,       //OCILobLocator      **filepp,
+		(*C.OraText)(unsafe.Pointer(cDirectoryAlias)), //const OraText      *dir_alias,
+		C.ub2(len(bf.DirectoryAlias)),                 //ub2                d_length,
+		(*C.OraText)(unsafe.Pointer(cFilename)),       //const OraText      *filename,
+		C.ub2(len(bf.Filename)),                       //ub2                f_length );
+	) == C.OCI_ERROR {
+		freeBfileLocator(lob)
+		return nil, env.ociError("OCILobFileSetName")
+	}
+	return lob, nil
+}
+
+func freeBfileLocator(lob *C.OCILobLocator) {
+	C.OCIDescriptorFree(
+		unsafe.Pointer(lob), //void     *descp,
+		C.OCI_DTYPE_FILE)    //ub4      type );
+}
+
+var _ = io.ReadCloser((*bfileReader)(nil))
+
+// bfileReader reads an opened BFILE.
+type bfileReader struct {
+	sync.Mutex
+	ses     *Ses
+	lob     *C.OCILobLocator
+	off     C.oraub8
+	timeout time.Duration
+}
+
+// Read reads the next bytes of the file into p.
+func (br *bfileReader) Read(p []byte) (n int, err error) {
+	br.Lock()
+	defer br.Unlock()
+	if br.lob == nil {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	err = br.do(func(svc *C.OCISvcCtx, env *Env) error {
+		byteAmt := C.oraub8(len(p))
+		r := C.OCILobRead2(
+			svc,                   //OCISvcCtx          *svchp,
+			env.ocierr,            //OCIError           *errhp,
+			br.lob,                //OCILobLocator      *locp,
+			&byteAmt,              //oraub8             *byteAmtp,
+			nil,                   //oraub8             *char_amtp,
+			br.off+1,              //oraub8             offset, offset is 1-based
+			unsafe.Pointer(&p[0]), //void               *bufp,
+			C.oraub8(len(p)),      //oraub8             bufl,
+			C.OCI_ONE_PIECE,       //ub1                piece,
+			nil,                   //void               *ctxp,
+			nil,                   //OCICallbackLobRead2 (cbfp)
+			0,                     //ub2                csid,
+			C.SQLCS_IMPLICIT,      //ub1                csfrm );
+		)
+		switch r {
+		case C.OCI_ERROR:
+			return env.ociError("OCILobRead2")
+		case C.OCI_INVALID_HANDLE:
+			return fmt.Errorf("Invalid handle %v", br.lob)
+		case C.OCI_NO_DATA:
+			return io.EOF
+		}
+		n = int(byteAmt)
+		br.off += byteAmt
+		return nil
+	})
+	return n, err
+}
+
+// Close closes the file, and frees the locator.
+func (br *bfileReader) Close() error {
+	br.Lock()
+	defer br.Unlock()
+	lob := br.lob
+	if lob == nil {
+		return nil
+	}
+	br.lob = nil
+	defer freeBfileLocator(lob)
+	if err := br.ses.checkClosed(); err != nil {
+		return errE(err)
+	}
+	return br.do(func(svc *C.OCISvcCtx, env *Env) error {
+		if C.OCILobFileClose(
+			svc,        //OCISvcCtx          *svchp,
+			env.ocierr, //OCIError           *errhp,
+			lob,        //OCILobLocator      *filep );
+		) == C.OCI_ERROR {
+			return env.ociError("OCILobFileClose")
+		}
+		return nil
+	})
+}
+
+// do calls f in a call limited by the call timeout.
+func (br *bfileReader) do(f func(svc *C.OCISvcCtx, env *Env) error) error {
+	br.ses.RLock()
+	svc, env := br.ses.ocisvcctx, br.ses.srv.env
+	br.ses.RUnlock()
+	call, err := br.ses.startCall(context.Background(), br.timeout)
+	if err != nil {
+		return err
+	}
+	return call.end(f(svc, env))
+}
@@ -0,0 +1,111 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"unsafe"
+)
+
+type bndJSON struct {
+	stmt   *Stmt
+	ocibnd *C.OCIBind
+	jsond  unsafe.Pointer
+	value  JSON
+	isPtr  bool
+	nullp
+}
+
+func (bnd *bndJSON) bind(value JSON, position namedPos, stmt *Stmt) error {
+	bnd.stmt = stmt
+	bnd.value = value
+	bnd.isPtr = value.isPtr()
+	env := stmt.ses.srv.env
+	if r := C.jsonDescAlloc(env.ocienv, &bnd.jsond); r == C.OCI_ERROR {
+		return env.ociError("JSON OCIDescriptorAlloc")
+	} else if r == C.OCI_INVALID_HANDLE {
+		return errNew("unable to allocate oci json handle during bind")
+	}
+	isNull := value.isNull()
+	bnd.nullp.Set(isNull)
+	if !isNull {
+		data, err := value.oson()
+		if err != nil {
+			return errE(err)
+		}
+		r := C.jsonFromBinary(
+			stmt.ses.ocisvcctx,                 //OCISvcCtx *svchp,
+			bnd.jsond,                          //OCIJson   *jsond,
+			(*C.ub1)(unsafe.Pointer(&data[0])), //ub1       *bufp,
+			C.oraub8(len(data)),                //oraub8    buf_sz,
+			env.ocierr)                         //OCIError  *errhp );
+		if r == C.OCI_ERROR {
+			return env.ociError()
+		}
+	}
+	ph, phLen, phFree := position.CString()
+	if ph != nil {
+		defer phFree()
+	}
+	r := C.bindByNameOrPos(
+		stmt.ocistmt,            //OCIStmt      *stmtp,
+		&bnd.ocibnd,             //OCIBind      **bindpp,
+		env.ocierr,              //OCIError     *errhp,
+		C.ub4(position.Ordinal), //ub4          position,
+		ph,
+		phLen,
+		unsafe.Pointer(&bnd.jsond),          //void         *valuep,
+		C.LENGTH_TYPE(C.sof_Jsonp),          //sb8          value_sz,
+		C.SQLT_JSON,                         //ub2          dty,
+		unsafe.Pointer(bnd.nullp.Pointer()), //void         *indp,
+		nil,                                 //ub2          *alenp,
+		nil,                                 //ub2          *rcodep,
+		0,                                   //ub4          maxarr_len,
+		nil,                                 //ub4          *curelep,
+		C.OCI_DEFAULT)                       //ub4          mode );
+	if r == C.OCI_ERROR {
+		return env.ociError()
+	}
+	return nil
+}
+
+func (bnd *bndJSON) setPtr() error {
+	if !bnd.isPtr {
+		return nil
+	}
+	if bnd.nullp.IsNull() {
+		return bnd.value.decode([]byte("null"))
+	}
+	data, err := bnd.stmt.ses.jsonToOSON(bnd.jsond)
+	if err != nil {
+		return err
+	}
+	return bnd.value.decode(data)
+}
+
+func (bnd *bndJSON) close() (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+
+	if bnd.jsond != nil {
+		C.jsonDescFree(bnd.jsond)
+		bnd.jsond = nil
+	}
+	stmt := bnd.stmt
+	bnd.stmt = nil
+	bnd.ocibnd = nil
+	bnd.value = JSON{}
+	bnd.isPtr = false
+	bnd.nullp.Free()
+	stmt.putBnd(bndIdxJSON, bnd)
+	return nil
+}
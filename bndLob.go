@@ -12,6 +12,7 @@ package ora
 import "C"
 import (
 	"io"
+	"sync/atomic"
 	"unsafe"
 )
 
@@ -205,11 +206,11 @@ func writeLob(ociLobLocator *C.OCILobLocator, stmt *Stmt, r io.Reader, lobBuffer
 			off+1,                      //oraub8          offset, starting position is 1
 			unsafe.Pointer(&actBuf[0]), //void            *bufp,
 			C.oraub8(n),
-			actPiece,         //ub1             piece,
-			nil,              //void            *ctxp,
-			nil,              //OCICallbackLobWrite2 (cbfp)
-			C.ub2(0),         //ub2             csid,
-			C.SQLCS_IMPLICIT, //ub1             csfrm );
+			actPiece,                                //ub1             piece,
+			nil,                                     //void            *ctxp,
+			nil,                                     //OCICallbackLobWrite2 (cbfp)
+			C.ub2(atomic.LoadUint32(&csIDAl32UTF8)), //ub2             csid,
+			C.SQLCS_IMPLICIT,                        //ub1             csfrm );
 		//fmt.Printf("r %v, current %v, buffer %v\n", r, current, buffer)
 		//fmt.Printf("C.OCI_NEED_DATA %v, C.OCI_SUCCESS %v\n", C.OCI_NEED_DATA, C.OCI_SUCCESS)
 		) == C.OCI_ERROR {
@@ -0,0 +1,118 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import "unsafe"
+
+// bndLobLocator binds the locator of a *LobLocator as an IN OUT parameter,
+// or a new locator for the output, if the LobLocator has none.
+type bndLobLocator struct {
+	stmt   *Stmt
+	ocibnd *C.OCIBind
+	value  *LobLocator
+	owned  bool // the locator is allocated by the bind
+	lobLocatorp
+	nullp
+}
+
+func (bnd *bndLobLocator) bind(value *LobLocator, position namedPos, stmt *Stmt) error {
+	bnd.stmt = stmt
+	bnd.value = value
+	sqlt := C.ub2(C.SQLT_BLOB)
+	if value.C {
+		sqlt = C.SQLT_CLOB
+	}
+	value.mu.Lock()
+	lob := value.lob
+	value.mu.Unlock()
+	bnd.owned = lob == nil
+	if bnd.owned {
+		r := C.OCIDescriptorAlloc(
+			unsafe.Pointer(stmt.ses.srv.env.ocienv),                      //CONST dvoid   *parenth,
+			(*unsafe.Pointer)(unsafe.Pointer(bnd.lobLocatorp.Pointer())), //dvoid         **descpp,
+			C.OCI_DTYPE_LOB, //ub4           type,
+			0,               //size_t        xtramem_sz,
+			nil)             //dvoid         **usrmempp);
+		if r == C.OCI_ERROR {
+			return stmt.ses.srv.env.ociError()
+		} else if r == C.OCI_INVALID_HANDLE {
+			return errNew("unable to allocate oci lob handle during bind")
+		}
+	} else {
+		*(bnd.lobLocatorp.Pointer()) = lob
+	}
+	bnd.nullp.Set(bnd.owned)
+
+	ph, phLen, phFree := position.CString()
+	if ph != nil {
+		defer phFree()
+	}
+	r := C.bindByNameOrPos(
+		bnd.stmt.ocistmt,            //OCIStmt      *stmtp,
+		&bnd.ocibnd,                 //OCIBind      **bindpp,
+		bnd.stmt.ses.srv.env.ocierr, //OCIError     *errhp,
+		C.ub4(position.Ordinal),     //ub4          position,
+		ph,
+		phLen,
+		unsafe.Pointer(bnd.lobLocatorp.Pointer()), //void         *valuep,
+		C.LENGTH_TYPE(bnd.lobLocatorp.Size()),     //sb8          value_sz,
+		sqlt,                                      //ub2          dty,
+		unsafe.Pointer(bnd.nullp.Pointer()),       //void         *indp,
+		nil,                                       //ub2          *alenp,
+		nil,                                       //ub2          *rcodep,
+		0,                                         //ub4          maxarr_len,
+		nil,                                       //ub4          *curelep,
+		C.OCI_DEFAULT)                             //ub4          mode );
+	if r == C.OCI_ERROR {
+		return bnd.stmt.ses.srv.env.ociError()
+	}
+	return nil
+}
+
+// setPtr hands the returned locator over to the LobLocator.
+func (bnd *bndLobLocator) setPtr() error {
+	if !bnd.owned || bnd.nullp.IsNull() {
+		return nil
+	}
+	loc := bnd.value
+	loc.mu.Lock()
+	loc.ses = bnd.stmt.ses
+	loc.lob = bnd.lobLocatorp.Value()
+	loc.timeout = bnd.stmt.Cfg().callTimeout
+	loc.opened, loc.pos = false, 0
+	loc.mu.Unlock()
+	*(bnd.lobLocatorp.Pointer()) = nil
+	bnd.owned = false
+	return nil
+}
+
+func (bnd *bndLobLocator) close() (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+	if lob := bnd.lobLocatorp.Value(); bnd.owned && lob != nil {
+		// not handed over: free lob locator handle
+		C.OCIDescriptorFree(
+			unsafe.Pointer(lob), //void     *descp,
+			C.OCI_DTYPE_LOB)     //ub4      type );
+	}
+	if bnd.lobLocatorp.p != nil {
+		*(bnd.lobLocatorp.Pointer()) = nil
+	}
+	stmt := bnd.stmt
+	bnd.stmt = nil
+	bnd.value = nil
+	bnd.ocibnd = nil
+	bnd.owned = false
+	stmt.putBnd(bndIdxLobLocator, bnd)
+	return nil
+}
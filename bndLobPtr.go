@@ -64,6 +64,7 @@ func (bnd *bndLobPtr) setPtr() error {
 		ociLobLocator: bnd.lobLocatorp.Value(),
 		piece:         C.OCI_FIRST_PIECE,
 		Length:        lobLength,
+		timeout:       bnd.stmt.Cfg().callTimeout,
 	}
 	bnd.value.Reader, bnd.value.Closer = lr, lr
 	return nil
@@ -0,0 +1,114 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <stdlib.h>
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"unsafe"
+)
+
+type bndObject struct {
+	stmt   *Stmt
+	ocibnd *C.OCIBind
+	value  *Object
+	// dest receives the decoded value of a Record bind
+	dest interface{}
+	// pp holds the instance and its null structure, in C memory
+	pp []unsafe.Pointer
+}
+
+func (bnd *bndObject) bind(value *Object, isPtr bool, position namedPos, stmt *Stmt) error {
+	bnd.stmt = stmt
+	if isPtr {
+		bnd.value = value
+	}
+	if value == nil || value.Type == nil {
+		return errNew("Object.Type is required for binding")
+	}
+	bnd.stmt.logF(_drv.Cfg().Log.Stmt.Bind, "%p pos=%v type=%s", bnd, position, value.Type.FullName())
+	if bnd.pp == nil {
+		bnd.pp = (*((*[2]unsafe.Pointer)(C.calloc(2, C.size_t(unsafe.Sizeof(unsafe.Pointer(nil)))))))[:2]
+	}
+	var err error
+	if bnd.pp[0], bnd.pp[1], err = stmt.ses.newObjectInstance(value); err != nil {
+		return err
+	}
+	env := stmt.ses.srv.env
+	ph, phLen, phFree := position.CString()
+	if ph != nil {
+		defer phFree()
+	}
+	r := C.bindByNameOrPos(
+		stmt.ocistmt, //OCIStmt      *stmtp,
+		&bnd.ocibnd,
+		env.ocierr,              //OCIError     *errhp,
+		C.ub4(position.Ordinal), //ub4          position,
+		ph,
+		phLen,
+		nil,           //void         *valuep,
+		0,             //sb8          value_sz,
+		C.SQLT_NTY,    //ub2          dty,
+		nil,           //void         *indp,
+		nil,           //ub2          *alenp,
+		nil,           //ub2          *rcodep,
+		0,             //ub4          maxarr_len,
+		nil,           //ub4          *curelep,
+		C.OCI_DEFAULT) //ub4          mode );
+	if r == C.OCI_ERROR {
+		return env.ociError()
+	}
+	r = C.OCIBindObject(
+		bnd.ocibnd,     //OCIBind          *bindp,
+		env.ocierr,     //OCIError         *errhp,
+		value.Type.tdo, //const OCIType    *type,
+		&bnd.pp[0],     //void             **pgvpp,
+		nil,            //ub4              *pvszsp,
+		&bnd.pp[1],     //void             **indpp,
+		nil)            //ub4              *indszp );
+	if r == C.OCI_ERROR {
+		return env.ociError()
+	}
+	return nil
+}
+
+func (bnd *bndObject) setPtr() error {
+	if bnd.value == nil {
+		return nil
+	}
+	obj, err := bnd.stmt.ses.objectFromInstance(bnd.value.Type, bnd.pp[0], bnd.pp[1])
+	if err != nil {
+		return err
+	}
+	*bnd.value = *obj
+	if bnd.dest != nil {
+		return obj.Decode(bnd.dest)
+	}
+	return nil
+}
+
+func (bnd *bndObject) close() (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+	stmt := bnd.stmt
+	if bnd.pp != nil {
+		stmt.ses.freeObjectInstance(bnd.pp[0])
+		C.free(unsafe.Pointer(&bnd.pp[0]))
+		bnd.pp = nil
+	}
+	bnd.stmt = nil
+	bnd.ocibnd = nil
+	bnd.value = nil
+	bnd.dest = nil
+	stmt.putBnd(bndIdxObject, bnd)
+	return nil
+}
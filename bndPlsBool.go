@@ -0,0 +1,105 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <stdlib.h>
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"unsafe"
+)
+
+// bndPlsBool binds a bool as a native PL/SQL BOOLEAN (SQLT_BOL).
+type bndPlsBool struct {
+	stmt   *Stmt
+	ocibnd *C.OCIBind
+	value  *bool
+	// nullable is set when value is a *Bool
+	nullable *Bool
+	buf      []C.int
+	nullp
+}
+
+func (bnd *bndPlsBool) bind(value bool, isNull bool, position namedPos, stmt *Stmt) error {
+	bnd.stmt = stmt
+	if bnd.buf == nil {
+		bnd.buf = (*((*[1]C.int)(C.malloc(C.sizeof_int))))[:1]
+	}
+	bnd.buf[0] = 0
+	if value {
+		bnd.buf[0] = 1
+	}
+	bnd.nullp.Set(isNull)
+	ph, phLen, phFree := position.CString()
+	if ph != nil {
+		defer phFree()
+	}
+	r := C.bindByNameOrPos(
+		bnd.stmt.ocistmt, //OCIStmt      *stmtp,
+		&bnd.ocibnd,
+		bnd.stmt.ses.srv.env.ocierr, //OCIError     *errhp,
+		C.ub4(position.Ordinal),     //ub4          position,
+		ph,
+		phLen,
+		unsafe.Pointer(&bnd.buf[0]),         //void         *valuep,
+		C.LENGTH_TYPE(C.sizeof_int),         //sb8          value_sz,
+		C.SQLT_BOL,                          //ub2          dty,
+		unsafe.Pointer(bnd.nullp.Pointer()), //void         *indp,
+		nil,                                 //ub2          *alenp,
+		nil,                                 //ub2          *rcodep,
+		0,                                   //ub4          maxarr_len,
+		nil,                                 //ub4          *curelep,
+		C.OCI_DEFAULT)                       //ub4          mode );
+	if r == C.OCI_ERROR {
+		return bnd.stmt.ses.srv.env.ociError()
+	}
+	return nil
+}
+
+func (bnd *bndPlsBool) bindPtr(value *bool, position namedPos, stmt *Stmt) error {
+	bnd.value = value
+	return bnd.bind(value != nil && *value, value == nil, position, stmt)
+}
+
+func (bnd *bndPlsBool) bindBoolPtr(value *Bool, position namedPos, stmt *Stmt) error {
+	bnd.nullable = value
+	return bnd.bind(value.Value, value.IsNull, position, stmt)
+}
+
+func (bnd *bndPlsBool) setPtr() error {
+	if bnd.nullable != nil {
+		bnd.nullable.IsNull = bnd.nullp.IsNull()
+		bnd.nullable.Value = !bnd.nullable.IsNull && bnd.buf[0] != 0
+		return nil
+	}
+	if bnd.value != nil && !bnd.nullp.IsNull() {
+		*bnd.value = bnd.buf[0] != 0
+	}
+	return nil
+}
+
+func (bnd *bndPlsBool) close() (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+
+	stmt := bnd.stmt
+	bnd.stmt = nil
+	bnd.ocibnd = nil
+	bnd.value = nil
+	bnd.nullable = nil
+	if bnd.buf != nil {
+		C.free(unsafe.Pointer(&bnd.buf[0]))
+		bnd.buf = nil
+	}
+	bnd.nullp.Free()
+	stmt.putBnd(bndIdxPlsBool, bnd)
+	return nil
+}
@@ -25,7 +25,22 @@ type bndString struct {
 // https://ellebaek.wordpress.com/2011/02/25/oracle-type-code-mappings/
 
 func (bnd *bndString) bind(value string, position namedPos, stmt *Stmt) error {
+	return bnd.bindForm(value, C.SQLCS_IMPLICIT, position, stmt)
+}
+
+// bindForm binds value in the charset form csfrm: SQLCS_IMPLICIT for the
+// database character set, SQLCS_NCHAR for the national character set.
+func (bnd *bndString) bindForm(value string, csfrm C.ub1, position namedPos, stmt *Stmt) (err error) {
 	bnd.stmt = stmt
+	env := stmt.ses.srv.env
+	if csfrm == C.SQLCS_NCHAR {
+		value, err = env.ncharset.encode(value)
+	} else {
+		value, err = env.encode(value)
+	}
+	if err != nil {
+		return err
+	}
 	bnd.cString = C.CString(value)
 	bnd.alen[0] = C.ACTUAL_LENGTH_TYPE(len(value))
 	bnd.nullp.Set(value == "")
@@ -58,7 +73,11 @@ func (bnd *bndString) bind(value string, position namedPos, stmt *Stmt) error {
 	if r == C.OCI_ERROR {
 		return bnd.stmt.ses.srv.env.ociError()
 	}
-	return nil
+	if csfrm == C.SQLCS_IMPLICIT {
+		return nil
+	}
+	return env.setAttr(unsafe.Pointer(bnd.ocibnd), C.OCI_HTYPE_BIND,
+		unsafe.Pointer(&csfrm), 0, C.OCI_ATTR_CHARSET_FORM)
 }
 
 func (bnd *bndString) setPtr() error {
@@ -36,11 +36,16 @@ func (bnd *bndStringPtr) bind(value *string, valueIsNull *bool, position namedPo
 	if C < stringPtrBufferSize {
 		C = stringPtrBufferSize
 	}
+	var s string
 	if value != nil {
-		lv := len(*value)
+		var err error
+		if s, err = stmt.ses.srv.env.encode(*value); err != nil {
+			return err
+		}
+		lv := len(s)
 		if lv > maxStringLength {
 			lv = maxStringLength
-			*value = (*value)[:lv]
+			s = s[:lv]
 		}
 		if lv > C {
 			L, C = lv, lv
@@ -58,11 +63,11 @@ func (bnd *bndStringPtr) bind(value *string, valueIsNull *bool, position namedPo
 		bnd.alen[0] = 0
 		bnd.buf = bnd.buf[:2]
 	} else {
-		if len(*value) == 0 {
+		if len(s) == 0 {
 			bnd.buf = bnd.buf[:2] // to be able to address bnd.buf[0]
 			bnd.buf[0], bnd.buf[1] = 0, 0
 		} else {
-			L = len(*value)
+			L = len(s)
 			if L < 2 {
 				L = 2
 			} else if L%2 != 0 {
@@ -70,9 +75,9 @@ func (bnd *bndStringPtr) bind(value *string, valueIsNull *bool, position namedPo
 			}
 			bnd.buf = bnd.buf[:L]
 			bnd.buf[L-1] = 0
-			copy(bnd.buf, []byte(*value))
+			copy(bnd.buf, s)
 		}
-		bnd.alen[0] = C.ACTUAL_LENGTH_TYPE(len(*value))
+		bnd.alen[0] = C.ACTUAL_LENGTH_TYPE(len(s))
 	}
 	bnd.stmt.logF(_drv.Cfg().Log.Stmt.Bind,
 		"%p pos=%v cap=%d len=%d alen=%d bufSize=%d", bnd, position, cap(bnd.buf), len(bnd.buf), bnd.alen[0], stringPtrBufferSize)
@@ -113,7 +118,11 @@ func (bnd *bndStringPtr) setPtr() error {
 		"StringPtr.setPtr isNull=%t alen=%d", bnd.nullp.IsNull(), bnd.alen[0])
 
 	if !bnd.nullp.IsNull() {
-		*bnd.value = string(bnd.buf[:bnd.alen[0]])
+		s, err := bnd.stmt.ses.srv.env.decode(bnd.buf[:bnd.alen[0]])
+		if err != nil {
+			return err
+		}
+		*bnd.value = s
 	} else {
 		*bnd.value = ""
 	}
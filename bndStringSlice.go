@@ -66,8 +66,17 @@ func (bnd *bndStringSlice) bind(values *[]string, position namedPos, stmt *Stmt,
 		}
 	}
 	bnd.strings = values
+	encoded := *values
+	if env := stmt.ses.srv.env; !env.charset.isUTF8() {
+		encoded = make([]string, len(*values))
+		for i, str := range *values {
+			if encoded[i], err = env.encode(str); err != nil {
+				return iterations, err
+			}
+		}
+	}
 	bnd.maxLen = stmt.Cfg().stringPtrBufferSize
-	for _, str := range *values {
+	for _, str := range encoded {
 		strLen := len(str)
 		if strLen > bnd.maxLen {
 			bnd.maxLen = strLen
@@ -78,7 +87,7 @@ func (bnd *bndStringSlice) bind(values *[]string, position namedPos, stmt *Stmt,
 	} else {
 		bnd.bytes = bnd.bytes[:bnd.maxLen*L]
 	}
-	for m, str := range *values {
+	for m, str := range encoded {
 		copy(bnd.bytes[m*bnd.maxLen:], str)
 		bnd.alen[m] = C.ACTUAL_LENGTH_TYPE(len(str))
 	}
@@ -146,7 +155,11 @@ func (bnd *bndStringSlice) setPtr() error {
 			}
 			continue
 		}
-		(*bnd.strings)[i] = string(bnd.bytes[i*bnd.maxLen : i*bnd.maxLen+int(length)])
+		s, err := bnd.stmt.ses.srv.env.decode(bnd.bytes[i*bnd.maxLen : i*bnd.maxLen+int(length)])
+		if err != nil {
+			return err
+		}
+		(*bnd.strings)[i] = s
 		bnd.stmt.logF(_drv.Cfg().Log.Stmt.Bind,
 			"StringSlice.setPtr[%d]=%s", i, (*bnd.strings)[i])
 		if bnd.values != nil {
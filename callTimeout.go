@@ -0,0 +1,205 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// ORA-03156: OCI call timed out
+const errCodeCallTimeout = 3156
+
+// ctxError is the error of an OCI call interrupted because its context is done,
+// or its CallTimeout is exceeded.
+//
+// Its Cause is context.Canceled or context.DeadlineExceeded.
+type ctxError struct {
+	ctxErr error
+	err    error
+}
+
+func (e *ctxError) Error() string {
+	return e.ctxErr.Error() + ": " + e.err.Error()
+}
+
+// Cause returns context.Canceled or context.DeadlineExceeded.
+func (e *ctxError) Cause() error {
+	return e.ctxErr
+}
+
+// contextCause returns the context error of err, if it is or wraps a ctxError.
+func contextCause(err error) error {
+	for err != nil {
+		if ce, ok := err.(*ctxError); ok {
+			return ce.ctxErr
+		}
+		c, ok := err.(interface {
+			Cause() error
+		})
+		if !ok {
+			return nil
+		}
+		err = c.Cause()
+	}
+	return nil
+}
+
+// ociCall limits the OCI round trips of a call, see Ses.startCall.
+type ociCall struct {
+	ses    *Ses
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	// whether OCI_ATTR_CALL_TIMEOUT is set, and its previous value
+	timeoutSet bool
+	prev       uint32
+
+	// mu guards inCall, which is set until end, so watch breaks the
+	// session only while the call is running
+	mu     sync.Mutex
+	inCall bool
+}
+
+// startCall prepares the session for the OCI round trips of a call, which
+// must be followed by ociCall.end.
+//
+// The round trips are limited by the earlier of the ctx deadline and timeout
+// (if positive). With 18c or newer clients, OCI enforces this limit per round
+// trip with OCI_ATTR_CALL_TIMEOUT; with older clients, a timer breaks the
+// running call with Ses.Break. A canceled ctx breaks the running call with
+// any client.
+//
+// A goroutine watches the ctx until ociCall.end, if it can be done (canceled,
+// or past a deadline or the timer of an older client); none is started
+// otherwise. It breaks the session only until ociCall.end returns, never
+// after. A call whose last round trip has just returned, but has not reached
+// ociCall.end yet, may still be broken: then Ses.Break resets the idle
+// session, and the call returns its own result.
+func (ses *Ses) startCall(ctx context.Context, timeout time.Duration) (*ociCall, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	byDeadline := false
+	if deadline, ok := ctx.Deadline(); ok {
+		if d := deadline.Sub(time.Now()); timeout <= 0 || d < timeout {
+			timeout, byDeadline = d, true
+		}
+		if timeout <= 0 {
+			return nil, context.DeadlineExceeded
+		}
+	}
+	call := &ociCall{ses: ses}
+	if timeout > 0 {
+		if C.HAS_CALL_TIMEOUT != 0 {
+			ms := uint32((timeout + time.Millisecond - 1) / time.Millisecond)
+			call.prev = atomic.LoadUint32(&ses.callTimeout)
+			if err := ses.setCallTimeout(ms); err != nil {
+				return nil, err
+			}
+			call.timeoutSet = true
+		} else if !byDeadline {
+			ctx, call.cancel = context.WithTimeout(ctx, timeout)
+		}
+	}
+	call.ctx = ctx
+	if ctx.Done() != nil {
+		call.inCall = true
+		call.done = make(chan struct{})
+		go call.watch()
+	}
+	return call, nil
+}
+
+// watch breaks the running call when the context is done.
+func (call *ociCall) watch() {
+	select {
+	case <-call.done:
+		return
+	case <-call.ctx.Done():
+	}
+	// OCI_ATTR_CALL_TIMEOUT takes care of the deadline
+	if call.timeoutSet && call.ctx.Err() == context.DeadlineExceeded {
+		return
+	}
+	// end waits for the Break, so it can't reach the next call
+	call.mu.Lock()
+	defer call.mu.Unlock()
+	if call.inCall {
+		call.ses.Break()
+	}
+}
+
+// end finishes the call, and returns err, or a ctxError if the call failed
+// because it was interrupted by its context or timeout.
+func (call *ociCall) end(err error) error {
+	if call == nil {
+		return err
+	}
+	if call.done != nil {
+		call.mu.Lock()
+		call.inCall = false
+		call.mu.Unlock()
+		close(call.done)
+	}
+	if call.timeoutSet {
+		if resetErr := call.ses.setCallTimeout(call.prev); resetErr != nil && err == nil {
+			err = resetErr
+		}
+	}
+	if err != nil && err != io.EOF {
+		if ctxErr := call.ctx.Err(); ctxErr != nil {
+			err = &ctxError{ctxErr: ctxErr, err: err}
+		} else if cd, ok := err.(interface {
+			Code() int
+		}); ok && cd.Code() == errCodeCallTimeout {
+			err = &ctxError{ctxErr: context.DeadlineExceeded, err: err}
+		}
+	}
+	if call.cancel != nil {
+		call.cancel()
+	}
+	return err
+}
+
+// setCallTimeout sets OCI_ATTR_CALL_TIMEOUT of the session in milliseconds,
+// 0 meaning no timeout.
+func (ses *Ses) setCallTimeout(ms uint32) error {
+	if atomic.LoadUint32(&ses.callTimeout) == ms {
+		return nil
+	}
+	ses.RLock()
+	ocisvcctx := ses.ocisvcctx
+	env := ses.Env()
+	ses.RUnlock()
+	if ocisvcctx == nil || env == nil {
+		return er("Ses is closed.")
+	}
+	value := C.ub4(ms)
+	if r := C.OCIAttrSet(
+		unsafe.Pointer(ocisvcctx), //void        *trgthndlp,
+		C.OCI_HTYPE_SVCCTX,        //ub4         trghndltyp,
+		unsafe.Pointer(&value),    //void        *attributep,
+		4,                         //ub4         size,
+		C.OCI_ATTR_CALL_TIMEOUT,   //ub4         attrtype,
+		env.ocierr,                //OCIError    *errhp );
+	); r == C.OCI_ERROR {
+		return env.ociError("OCI_ATTR_CALL_TIMEOUT")
+	}
+	atomic.StoreUint32(&ses.callTimeout, ms)
+	return nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"unicode/utf8"
+	"unsafe"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+)
+
+// EnvCfg configures an Env, see OpenEnv.
+type EnvCfg struct {
+	// Charset is the Oracle name of the client character set, such as
+	// WE8ISO8859P1 or EE8ISO8859P2, in which OCI exchanges CHAR, VARCHAR2
+	// and LONG values, and the statement text.
+	//
+	// The default is AL32UTF8. With any other character set, the strings of
+	// the binds and defines are transcoded by the ora package to and from
+	// UTF-8, so Go code always deals with UTF-8.
+	Charset string
+
+	// NCharset is the Oracle name of the client national character set, used
+	// for NCHAR, NVARCHAR2 and NCLOB values exchanged in the national form.
+	//
+	// The default is AL32UTF8.
+	NCharset string
+
+	// StrictCharset makes transcoding return an error for characters which
+	// cannot be represented in the client character set (or, when decoding,
+	// bytes which are invalid in it), instead of replacing them.
+	StrictCharset bool
+
+	// StmtCfg configures new Srvs. If zero, the driver's StmtCfg is used.
+	StmtCfg
+}
+
+// charsetEncodings maps the supported Oracle character set names to their
+// encoding; nil means UTF-8, which needs no transcoding.
+var charsetEncodings = map[string]encoding.Encoding{
+	"AL32UTF8": nil,
+	"UTF8":     nil,
+
+	"WE8ISO8859P1":   charmap.ISO8859_1,
+	"EE8ISO8859P2":   charmap.ISO8859_2,
+	"SE8ISO8859P3":   charmap.ISO8859_3,
+	"NEE8ISO8859P4":  charmap.ISO8859_4,
+	"CL8ISO8859P5":   charmap.ISO8859_5,
+	"AR8ISO8859P6":   charmap.ISO8859_6,
+	"EL8ISO8859P7":   charmap.ISO8859_7,
+	"IW8ISO8859P8":   charmap.ISO8859_8,
+	"WE8ISO8859P9":   charmap.ISO8859_9,
+	"NE8ISO8859P10":  charmap.ISO8859_10,
+	"BLT8ISO8859P13": charmap.ISO8859_13,
+	"WE8ISO8859P15":  charmap.ISO8859_15,
+	"EE8MSWIN1250":   charmap.Windows1250,
+	"CL8MSWIN1251":   charmap.Windows1251,
+	"WE8MSWIN1252":   charmap.Windows1252,
+	"EL8MSWIN1253":   charmap.Windows1253,
+	"TR8MSWIN1254":   charmap.Windows1254,
+	"IW8MSWIN1255":   charmap.Windows1255,
+	"AR8MSWIN1256":   charmap.Windows1256,
+	"BLT8MSWIN1257":  charmap.Windows1257,
+	"VN8MSWIN1258":   charmap.Windows1258,
+	"CL8KOI8R":       charmap.KOI8R,
+	"CL8KOI8U":       charmap.KOI8U,
+	"US8PC437":       charmap.CodePage437,
+	"WE8PC850":       charmap.CodePage850,
+	"EE8PC852":       charmap.CodePage852,
+	"RU8PC866":       charmap.CodePage866,
+
+	"JA16SJIS":     japanese.ShiftJIS,
+	"JA16EUC":      japanese.EUCJP,
+	"ZHS16GBK":     simplifiedchinese.GBK,
+	"ZHS32GB18030": simplifiedchinese.GB18030,
+	"ZHT16BIG5":    traditionalchinese.Big5,
+	"KO16MSWIN949": korean.EUCKR,
+	"KO16KSC5601":  korean.EUCKR,
+}
+
+// charset transcodes text between UTF-8 and a client character set.
+//
+// A nil *charset, as any charset without encoding, is UTF-8.
+type charset struct {
+	name   string
+	id     C.ub2
+	enc    encoding.Encoding
+	strict bool
+}
+
+// newCharset returns the charset of the Oracle character set name, which
+// is AL32UTF8 if empty.
+func newCharset(name string, strict bool) (*charset, error) {
+	if name == "" {
+		name = "AL32UTF8"
+	}
+	name = strings.ToUpper(name)
+	enc, ok := charsetEncodings[name]
+	if !ok {
+		return nil, errF("unsupported client character set %q", name)
+	}
+	id, err := charsetID(name)
+	if err != nil {
+		return nil, err
+	}
+	return &charset{name: name, id: id, enc: enc, strict: strict}, nil
+}
+
+var (
+	charsetIDsMu sync.Mutex
+	charsetIDs   = make(map[string]C.ub2, 2)
+)
+
+// charsetID returns the OCI id of the character set name.
+func charsetID(name string) (C.ub2, error) {
+	charsetIDsMu.Lock()
+	defer charsetIDsMu.Unlock()
+	if id, ok := charsetIDs[name]; ok {
+		return id, nil
+	}
+	var ocienv *C.OCIEnv
+	r := C.OCIEnvCreate(&ocienv, C.OCI_DEFAULT|C.OCI_THREADED, nil, nil, nil, nil, 0, nil)
+	if r == C.OCI_ERROR {
+		return 0, errF("Unable to create environment handle (Return code = %d).", r)
+	}
+	defer C.OCIHandleFree(unsafe.Pointer(ocienv), C.OCI_HTYPE_ENV)
+	// http://docs.oracle.com/cd/B10501_01/server.920/a96529/ch8.htm#14284
+	csName := C.CString(name)
+	defer C.free(unsafe.Pointer(csName))
+	id := C.OCINlsCharSetNameToId(unsafe.Pointer(ocienv), (*C.oratext)(unsafe.Pointer(csName)))
+	if id == 0 {
+		return 0, errF("unknown character set %q", name)
+	}
+	charsetIDs[name] = id
+	return id, nil
+}
+
+// isUTF8 reports whether the charset is UTF-8, so needs no transcoding.
+func (cs *charset) isUTF8() bool { return cs == nil || cs.enc == nil }
+
+// encode converts the UTF-8 s to the charset.
+func (cs *charset) encode(s string) (string, error) {
+	if cs.isUTF8() || isASCII(s) {
+		return s, nil
+	}
+	enc := cs.enc.NewEncoder()
+	if !cs.strict {
+		enc = encoding.ReplaceUnsupported(enc)
+	}
+	t, err := enc.String(s)
+	if err != nil {
+		return "", errF("cannot convert %q to %s: %v", s, cs.name, err)
+	}
+	return t, nil
+}
+
+// decode converts p from the charset to UTF-8.
+func (cs *charset) decode(p []byte) (string, error) {
+	if cs.isUTF8() || isASCIIBytes(p) {
+		return string(p), nil
+	}
+	q, err := cs.enc.NewDecoder().Bytes(p)
+	if err != nil {
+		return "", errF("cannot convert %q from %s: %v", p, cs.name, err)
+	}
+	// the decoders replace the invalid bytes with utf8.RuneError
+	if cs.strict && bytes.ContainsRune(q, utf8.RuneError) {
+		return "", errF("cannot convert %q from %s: invalid bytes", p, cs.name)
+	}
+	return string(q), nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+func isASCIIBytes(p []byte) bool {
+	for _, b := range p {
+		if b >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
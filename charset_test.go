@@ -0,0 +1,62 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import (
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestCharset(t *testing.T) {
+	for i, tc := range []struct {
+		cs      *charset
+		in, out string
+		err     bool
+	}{
+		{cs: nil, in: "árvíztűrő", out: "árvíztűrő"},
+		{cs: &charset{name: "AL32UTF8"}, in: "árvíztűrő", out: "árvíztűrő"},
+		{cs: &charset{name: "EE8ISO8859P2", enc: charmap.ISO8859_2}, in: "ascii", out: "ascii"},
+		{cs: &charset{name: "EE8ISO8859P2", enc: charmap.ISO8859_2}, in: "tűrő", out: "t\xfbr\xf5"},
+		{cs: &charset{name: "WE8ISO8859P1", enc: charmap.ISO8859_1}, in: "tűrő", out: "t\x1ar\x1a"},
+		{cs: &charset{name: "WE8ISO8859P1", enc: charmap.ISO8859_1, strict: true}, in: "tűrő", err: true},
+		{cs: &charset{name: "WE8ISO8859P1", enc: charmap.ISO8859_1, strict: true}, in: "tér", out: "t\xe9r"},
+	} {
+		got, err := tc.cs.encode(tc.in)
+		if err != nil {
+			if !tc.err {
+				t.Errorf("%d. encode %q: %v", i, tc.in, err)
+			}
+			continue
+		}
+		if tc.err {
+			t.Errorf("%d. encode %q: wanted error, got %q", i, tc.in, got)
+			continue
+		}
+		if got != tc.out {
+			t.Errorf("%d. encode %q: got %q, wanted %q", i, tc.in, got, tc.out)
+		}
+		if tc.out != tc.in && !tc.cs.strict {
+			// lossy
+			continue
+		}
+		back, err := tc.cs.decode([]byte(got))
+		if err != nil {
+			t.Errorf("%d. decode %q: %v", i, got, err)
+		} else if back != tc.in {
+			t.Errorf("%d. decode %q: got %q, wanted %q", i, got, back, tc.in)
+		}
+	}
+
+	// 0x81 is undefined in Windows-1252
+	cs := &charset{name: "WE8MSWIN1252", enc: charmap.Windows1252}
+	if got, err := cs.decode([]byte("a\x81b")); err != nil || got != "a\uFFFDb" {
+		t.Errorf("decode: got %q, %v", got, err)
+	}
+	cs.strict = true
+	if got, err := cs.decode([]byte("a\x81b")); err == nil {
+		t.Errorf("decode: wanted error, got %q", got)
+	}
+}
@@ -181,6 +181,10 @@ func maybeBadConn(err error) error {
 	if err == nil {
 		return nil
 	}
+	// database/sql expects the context error of an interrupted call
+	if ctxErr := contextCause(err); ctxErr != nil {
+		return ctxErr
+	}
 	// database/sql API expect driver.ErrBadConn to reconnect to the database
 	if cd, ok := err.(interface {
 		Code() int
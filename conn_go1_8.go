@@ -84,30 +84,11 @@ func (con *Con) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx,
 	if err := con.checkIsOpen(); err != nil {
 		return nil, err
 	}
-	var tx *Tx
-	done := make(chan error, 1)
-	go func() {
-		defer close(done)
-		var err error
-		tx, err = con.ses.StartTx(TxFlags(uint32(flags)))
-		done <- err
-	}()
-	var err error
-	select {
-	case err = <-done:
-		return tx, err
-	case <-ctx.Done():
-		// select again to avoid race condition if both are done
-		select {
-		case err = <-done:
-			return tx, err
-		default:
-			if err = ctx.Err(); isCanceled(err) {
-				con.ses.Break()
-			}
-		}
+	tx, err := con.ses.startTx(ctx, []TxOption{TxFlags(uint32(flags))})
+	if err != nil {
+		return nil, maybeBadConn(err)
 	}
-	return nil, maybeBadConn(err)
+	return tx, nil
 }
 
 // vim: set fileencoding=utf-8 noet:
@@ -4,6 +4,13 @@
 
 package ora
 
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"time"
+)
+
 // GoColumnType defines the Go type returned from a sql select column.
 type GoColumnType uint
 
@@ -73,6 +80,21 @@ const (
 	OraN
 	// L defins an sql select column as an ora.Lob.
 	L
+	// J defines a sql select column as a Go json.RawMessage.
+	J
+	// JV defines a sql select column as a Go interface{} decoded from JSON:
+	// map[string]interface{}, []interface{}, string, json.Number, bool or nil.
+	JV
+	// BigI defines a sql select column as a Go *big.Int.
+	BigI
+	// BigF defines a sql select column as a Go *big.Float.
+	BigF
+	// BigR defines a sql select column as a Go *big.Rat.
+	BigR
+	// Loc defines a BLOB or CLOB select column as an *ora.LobLocator.
+	Loc
+	// Rid defines a ROWID or UROWID select column as an ora.Rowid.
+	Rid
 )
 
 func GctName(gct GoColumnType) string {
@@ -141,6 +163,20 @@ func GctName(gct GoColumnType) string {
 		return "OraN"
 	case L:
 		return "L"
+	case J:
+		return "J"
+	case JV:
+		return "JV"
+	case BigI:
+		return "BigI"
+	case BigF:
+		return "BigF"
+	case BigR:
+		return "BigR"
+	case Loc:
+		return "Loc"
+	case Rid:
+		return "Rid"
 	}
 	return ""
 }
@@ -149,6 +185,89 @@ func (gct GoColumnType) String() string {
 	return GctName(gct)
 }
 
+// gctGoType returns the type of the values of a select column defined as gct.
+func gctGoType(gct GoColumnType) reflect.Type {
+	switch gct {
+	case I64:
+		return reflect.TypeOf(int64(0))
+	case I32:
+		return reflect.TypeOf(int32(0))
+	case I16:
+		return reflect.TypeOf(int16(0))
+	case I8:
+		return reflect.TypeOf(int8(0))
+	case U64:
+		return reflect.TypeOf(uint64(0))
+	case U32:
+		return reflect.TypeOf(uint32(0))
+	case U16:
+		return reflect.TypeOf(uint16(0))
+	case U8:
+		return reflect.TypeOf(uint8(0))
+	case F64:
+		return reflect.TypeOf(float64(0))
+	case F32:
+		return reflect.TypeOf(float32(0))
+	case OraI64:
+		return reflect.TypeOf(Int64{})
+	case OraI32:
+		return reflect.TypeOf(Int32{})
+	case OraI16:
+		return reflect.TypeOf(Int16{})
+	case OraI8:
+		return reflect.TypeOf(Int8{})
+	case OraU64:
+		return reflect.TypeOf(Uint64{})
+	case OraU32:
+		return reflect.TypeOf(Uint32{})
+	case OraU16:
+		return reflect.TypeOf(Uint16{})
+	case OraU8:
+		return reflect.TypeOf(Uint8{})
+	case OraF64:
+		return reflect.TypeOf(Float64{})
+	case OraF32:
+		return reflect.TypeOf(Float32{})
+	case T:
+		return reflect.TypeOf(time.Time{})
+	case OraT:
+		return reflect.TypeOf(Time{})
+	case S:
+		return reflect.TypeOf("")
+	case OraS:
+		return reflect.TypeOf(String{})
+	case B:
+		return reflect.TypeOf(false)
+	case OraB:
+		return reflect.TypeOf(Bool{})
+	case Bin:
+		return reflect.TypeOf([]byte(nil))
+	case OraBin:
+		return reflect.TypeOf(Raw{})
+	case N:
+		return reflect.TypeOf(OCINum{})
+	case OraN:
+		return reflect.TypeOf(OraOCINum{})
+	case L:
+		return reflect.TypeOf((*Lob)(nil))
+	case J:
+		return reflect.TypeOf(json.RawMessage(nil))
+	case JV:
+		return reflect.TypeOf((*interface{})(nil)).Elem()
+	case BigI:
+		return reflect.TypeOf((*big.Int)(nil))
+	case BigF:
+		return reflect.TypeOf((*big.Float)(nil))
+	case BigR:
+		return reflect.TypeOf((*big.Rat)(nil))
+	case Loc:
+		return reflect.TypeOf((*LobLocator)(nil))
+	case Rid:
+		return reflect.TypeOf(Rowid{})
+	}
+	return nil
+}
+
 // bind pool indexes
 const (
 	bndIdxInt64 int = iota
@@ -211,6 +330,7 @@ const (
 	bndIdxLob
 	bndIdxLobPtr
 	bndIdxLobSlice
+	bndIdxLobLocator
 
 	bndIdxIntervalYM
 	bndIdxIntervalYMSlice
@@ -219,6 +339,9 @@ const (
 
 	bndIdxBfile
 	bndIdxRset
+	bndIdxObject
+	bndIdxPlsBool
+	bndIdxJSON
 	bndIdxNil
 )
 
@@ -245,10 +368,14 @@ const (
 	defIdxLob
 	defIdxRaw
 	defIdxLongRaw
+	defIdxLong
 
 	defIdxIntervalYM
 	defIdxIntervalDS
 	defIdxBfile
 	defIdxRowid
 	defIdxRset
+	defIdxObject
+	defIdxJSON
+	defIdxBigNum
 )
@@ -0,0 +1,106 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <stdlib.h>
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"math/big"
+	"unsafe"
+
+	"gopkg.in/rana/ora.v4/num"
+)
+
+// defBigNum defines a NUMBER column as a *big.Int, *big.Float or *big.Rat.
+type defBigNum struct {
+	ociDef
+	ociNumber []C.OCINumber
+	gct       GoColumnType
+}
+
+func (def *defBigNum) define(position int, gct GoColumnType, rset *Rset) error {
+	def.rset = rset
+	def.gct = gct
+	if def.ociNumber != nil {
+		C.free(unsafe.Pointer(&def.ociNumber[0]))
+	}
+	def.ociNumber = (*((*[MaxFetchLen]C.OCINumber)(C.malloc(C.size_t(rset.fetchLen) * C.sizeof_OCINumber))))[:rset.fetchLen]
+	return def.ociDef.defineByPos(position, unsafe.Pointer(&def.ociNumber[0]), C.sizeof_OCINumber, C.SQLT_VNU)
+}
+
+func (def *defBigNum) value(offset int) (value interface{}, err error) {
+	if def.nullInds[offset] < 0 {
+		return nil, nil
+	}
+	var n OCINum
+	n.FromC(def.ociNumber[offset])
+	return bigValue(def.gct, n.OCINum)
+}
+
+// bigValue returns the number as the big type of gct: BigI, BigF or BigR.
+func bigValue(gct GoColumnType, n num.OCINum) (interface{}, error) {
+	switch gct {
+	case BigF:
+		return n.BigFloat()
+	case BigR:
+		return n.BigRat()
+	}
+	return n.BigInt()
+}
+
+func (def *defBigNum) alloc() error { return nil }
+func (def *defBigNum) free() {
+	def.arrHlp.close()
+}
+
+func (def *defBigNum) close() (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+
+	rset := def.rset
+	def.rset = nil
+	def.ocidef = nil
+	if def.ociNumber != nil {
+		C.free(unsafe.Pointer(&def.ociNumber[0]))
+		def.ociNumber = nil
+	}
+	rset.putDef(defIdxBigNum, def)
+	return nil
+}
+
+// setBig sets n to the *big.Int, *big.Float or *big.Rat value, and reports
+// whether the value is a nil pointer.
+func setBig(n *num.OCINum, value interface{}) (isNil bool, err error) {
+	switch x := value.(type) {
+	case *big.Int:
+		if x == nil {
+			return true, nil
+		}
+		err = n.SetBigInt(x)
+	case *big.Float:
+		if x == nil {
+			return true, nil
+		}
+		err = n.SetBigFloat(x)
+	case *big.Rat:
+		if x == nil {
+			return true, nil
+		}
+		err = n.SetBigRat(x)
+	default:
+		return false, errF("unsupported big number type %T", value)
+	}
+	if err != nil {
+		return false, errE(err)
+	}
+	return false, nil
+}
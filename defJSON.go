@@ -0,0 +1,88 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <stdlib.h>
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import "unsafe"
+
+// defJSON defines a JSON column, fetched as OCIJson descriptors.
+type defJSON struct {
+	ociDef
+	gct   GoColumnType
+	jsons []unsafe.Pointer
+}
+
+func (def *defJSON) define(position int, gct GoColumnType, rset *Rset) error {
+	def.rset = rset
+	def.gct = gct
+	if def.jsons != nil {
+		C.free(unsafe.Pointer(&def.jsons[0]))
+	}
+	def.jsons = (*((*[MaxFetchLen]unsafe.Pointer)(C.malloc(C.size_t(rset.fetchLen) * C.sof_Jsonp))))[:rset.fetchLen]
+	def.ensureAllocatedLength(len(def.jsons))
+	return def.ociDef.defineByPos(position, unsafe.Pointer(&def.jsons[0]), int(C.sof_Jsonp), C.SQLT_JSON)
+}
+
+func (def *defJSON) value(offset int) (value interface{}, err error) {
+	if def.nullInds[offset] < 0 {
+		return jsonValue(def.gct, nil, true)
+	}
+	data, err := def.rset.stmt.ses.jsonToOSON(def.jsons[offset])
+	if err != nil {
+		return nil, err
+	}
+	return jsonValue(def.gct, data, false)
+}
+
+func (def *defJSON) alloc() error {
+	env := def.rset.stmt.ses.srv.env
+	for i := range def.jsons {
+		def.allocated[i] = false
+		if r := C.jsonDescAlloc(env.ocienv, &def.jsons[i]); r == C.OCI_ERROR {
+			return env.ociError("JSON OCIDescriptorAlloc")
+		} else if r == C.OCI_INVALID_HANDLE {
+			return errNew("unable to allocate oci json handle during define")
+		}
+		def.allocated[i] = true
+	}
+	return nil
+}
+
+func (def *defJSON) free() {
+	for i, d := range def.jsons {
+		if d == nil {
+			continue
+		}
+		def.jsons[i] = nil
+		if def.allocated[i] {
+			C.jsonDescFree(d)
+		}
+	}
+	def.arrHlp.close()
+}
+
+func (def *defJSON) close() (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+
+	def.free()
+	rset := def.rset
+	def.rset = nil
+	if def.jsons != nil {
+		C.free(unsafe.Pointer(&def.jsons[0]))
+		def.jsons = nil
+	}
+	def.ocidef = nil
+	rset.putDef(defIdxJSON, def)
+	return nil
+}
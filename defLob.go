@@ -12,10 +12,12 @@ package ora
 import "C"
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"sync"
 	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
@@ -110,6 +112,8 @@ func (def *defLob) Reader(offset int) io.ReadCloser {
 		ses:           def.rset.stmt.ses,
 		ociLobLocator: def.lobs[offset],
 		piece:         C.OCI_FIRST_PIECE,
+		ctx:           def.rset.ctx,
+		timeout:       def.rset.stmt.Cfg().callTimeout,
 	}
 	//def.rset.RUnlock()
 	def.lobs[offset] = nil // don't use it anywhere else
@@ -156,6 +160,27 @@ func (def *defLob) value(offset int) (result interface{}, err error) {
 		b, err := def.Bytes(offset)
 		return String{Value: string(b)}, err
 
+	case J, JV:
+		if isNull {
+			return jsonValue(gct, nil, true)
+		}
+		b, err := def.Bytes(offset)
+		if err != nil {
+			return nil, err
+		}
+		return jsonValue(gct, b, false)
+
+	case Loc:
+		if isNull {
+			return (*LobLocator)(nil), nil
+		}
+		def.Lock()
+		loc := newLobLocator(def.rset.stmt.ses, def.lobs[offset], def.sqlt == C.SQLT_CLOB, def.rset.stmt.Cfg().callTimeout)
+		def.lobs[offset] = nil // owned by loc
+		def.allocated[offset] = false
+		def.Unlock()
+		return loc, nil
+
 	default: // D or L
 		if isNull {
 			return (*Lob)(nil), nil
@@ -236,6 +261,10 @@ type lobReader struct {
 	off           C.oraub8
 	opened        bool
 
+	// limit the round trips, see Ses.startCall
+	ctx     context.Context
+	timeout time.Duration
+
 	// Length is the underlying LOB's length.
 	// It is 0 before the first Read call!
 	Length C.oraub8
@@ -276,6 +305,11 @@ func (lr *lobReader) Read(p []byte) (n int, err error) {
 	if ociLobLocator == nil {
 		return 0, io.EOF
 	}
+	call, err := ses.startCall(lr.ctx, lr.timeout)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { err = call.end(err) }()
 	if !opened {
 		lr.Lock()
 		lr.opened = true
@@ -283,6 +317,7 @@ func (lr *lobReader) Read(p []byte) (n int, err error) {
 		//Log.Infof("Reader OCILobOpen %p", def.ociLobLocator)
 		//fmt.Printf("lobOpen(%p loc=%p)\n", lr, lr.ociLobLocator)
 		lr.Length, err = lobOpen(ses, ociLobLocator, C.OCI_LOB_READONLY)
+		lr.csfrm = lobCharsetForm(ses.srv.env, ociLobLocator)
 		lr.Unlock()
 		if err != nil {
 			return 0, err
@@ -290,7 +325,7 @@ func (lr *lobReader) Read(p []byte) (n int, err error) {
 	}
 
 	lr.Lock()
-	Length, off := lr.Length, lr.off
+	Length, off, csfrm := lr.Length, lr.off, lr.csfrm
 	piece := lr.piece
 	lr.Unlock()
 	if Length == 0 || off >= Length {
@@ -312,7 +347,7 @@ func (lr *lobReader) Read(p []byte) (n int, err error) {
 		nil,                   //void               *ctxp,
 		nil,                   //OCICallbackLobRead2 (cbfp)
 		C.ub2(atomic.LoadUint32(&csIDAl32UTF8)), //ub2             csid,
-		C.SQLCS_IMPLICIT,                        //lr.charsetForm,                          //ub1                csfrm );
+		csfrm,                                   //ub1                csfrm );
 	)
 	//Log.Infof("LobRead2 returned %d amt=%d", r, byteAmt)
 	err = nil
@@ -356,107 +391,6 @@ func (lr *lobReader) WriteTo(w io.Writer) (n int64, err error) {
 	}
 }
 
-// TODO(tgulacsi): find how to return lobReadWriter.
-
-var _ = io.ReaderAt((*lobReadWriter)(nil))
-var _ = io.WriterAt((*lobReadWriter)(nil))
-
-type lobReadWriter struct {
-	ses           *Ses
-	ociLobLocator *C.OCILobLocator
-	size          C.oraub8
-}
-
-// Size returns the actual size of the LOB.
-func (lrw lobReadWriter) Size() uint64 {
-	return uint64(lrw.size)
-}
-
-// Close the LOB.
-func (lrw *lobReadWriter) Close() error {
-	lob := lrw.ociLobLocator
-	if lob == nil {
-		return nil
-	}
-	lrw.ociLobLocator = nil
-	return lobClose(lrw.ses, lob)
-}
-
-// Truncate the lob to the given length.
-func (lrw *lobReadWriter) Truncate(length int64) error {
-	if C.OCILobTrim2(
-		lrw.ses.ocisvcctx,      //OCISvcCtx          *svchp,
-		lrw.ses.srv.env.ocierr, //OCIError           *errhp,
-		lrw.ociLobLocator,      //OCILobLocator      *locp,
-		C.oraub8(length),       //oraub8             *newlen)
-	) == C.OCI_ERROR {
-		return lrw.ses.srv.env.ociError()
-	}
-	return nil
-}
-
-// ReadAt reads into p, starting from off.
-func (lrw *lobReadWriter) ReadAt(p []byte, off int64) (n int, err error) {
-	byteAmt := C.oraub8(len(p))
-	//Log.Infof("LobRead2 off=%d amt=%d", off, len(p))
-	r := C.OCILobRead2(
-		lrw.ses.ocisvcctx,      //OCISvcCtx          *svchp,
-		lrw.ses.srv.env.ocierr, //OCIError           *errhp,
-		lrw.ociLobLocator,      //OCILobLocator      *locp,
-		&byteAmt,               //oraub8             *byteAmtp,
-		nil,                    //oraub8             *char_amtp,
-		C.oraub8(off)+1,        //oraub8             offset, offset is 1-based
-		unsafe.Pointer(&p[0]),  //void               *bufp,
-		C.oraub8(len(p)),       //oraub8             bufl,
-		C.OCI_ONE_PIECE,        //ub1                piece,
-		nil,                    //void               *ctxp,
-		nil,                    //OCICallbackLobRead2 (cbfp)
-		C.ub2(atomic.LoadUint32(&csIDAl32UTF8)),
-		C.SQLCS_IMPLICIT, //ub1                csfrm );
-	)
-	//Log.Infof("LobRead2 returned %d amt=%d", r, byteAmt)
-	switch r {
-	case C.OCI_ERROR:
-		return 0, lrw.ses.srv.env.ociError()
-	case C.OCI_NO_DATA:
-		return int(byteAmt), io.EOF
-	case C.OCI_INVALID_HANDLE:
-		return 0, fmt.Errorf("Invalid Handle %v", lrw.ociLobLocator)
-	}
-	return int(byteAmt), nil
-}
-
-// WriteAt writes data in p into the LOB, starting at off.
-func (lrw *lobReadWriter) WriteAt(p []byte, off int64) (n int, err error) {
-	//Log.Infof("LobWrite2 off=%d len=%d", off, n)
-	byteAmt := C.oraub8(len(p))
-	// Write to Oracle
-	//fmt.Println("OCILobWrite2")
-	if C.OCILobWrite2(
-		lrw.ses.ocisvcctx,      //OCISvcCtx          *svchp,
-		lrw.ses.srv.env.ocierr, //OCIError           *errhp,
-		lrw.ociLobLocator,      //OCILobLocator      *locp,
-		&byteAmt,               //oraub8          *byteAmtp,
-		nil,                    //oraub8          *char_amtp,
-		C.oraub8(off)+1,        //oraub8          offset, starting position is 1
-		unsafe.Pointer(&p[0]),  //void            *bufp,
-		C.oraub8(len(p)),
-		C.OCI_ONE_PIECE, //ub1             piece,
-		nil,             //void            *ctxp,
-		nil,             //OCICallbackLobWrite2 (cbfp)
-		C.ub2(atomic.LoadUint32(&csIDAl32UTF8)), //ub2             csid,
-		C.SQLCS_IMPLICIT,                        //ub1             csfrm );
-	//fmt.Printf("C.OCI_NEED_DATA %v, C.OCI_SUCCESS %v\n", C.OCI_NEED_DATA, C.OCI_SUCCESS)
-	) == C.OCI_ERROR {
-		return 0, lrw.ses.srv.env.ociError()
-	}
-	//fmt.Printf("r %v, current %v, buffer %v\n", r, current, buffer)
-	if C.oraub8(off)+byteAmt > lrw.size {
-		lrw.size = C.oraub8(off) + byteAmt
-	}
-	return int(byteAmt), nil
-}
-
 func lobOpen(ses *Ses, lob *C.OCILobLocator, mode C.ub1) (
 	length C.oraub8, err error,
 ) {
@@ -508,6 +442,21 @@ func lobOpen(ses *Ses, lob *C.OCILobLocator, mode C.ub1) (
 	return length, nil
 }
 
+// lobCharsetForm returns the character set form of the lob: SQLCS_NCHAR for
+// NCLOBs, SQLCS_IMPLICIT for CLOBs (and BLOBs).
+func lobCharsetForm(env *Env, lob *C.OCILobLocator) C.ub1 {
+	var csfrm C.ub1
+	if C.OCILobCharSetForm(
+		env.ocienv, //OCIEnv             *envhp,
+		env.ocierr, //OCIError           *errhp,
+		lob,        //const OCILobLocator *locp,
+		&csfrm,     //ub1                *csfrm );
+	) == C.OCI_ERROR || csfrm == 0 {
+		return C.SQLCS_IMPLICIT
+	}
+	return csfrm
+}
+
 func lobClose(ses *Ses, lob *C.OCILobLocator) (err error) {
 	if lob == nil {
 		return nil
@@ -0,0 +1,181 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <stdlib.h>
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"unsafe"
+)
+
+// maxLongSize is the maximum length of a LONG or LONG RAW value.
+const maxLongSize = 1<<31 - 1
+
+// pieceDef is a define of a LONG or LONG RAW column, fetched piecewise.
+type pieceDef interface {
+	pieceDef() *ociPieceDef
+}
+
+// ociPieceDef defines a column with OCI_DYNAMIC_FETCH, and collects the pieces
+// of the values of each row of a fetch (see Rset.fetchPieces), so the values
+// are not limited by the size of a buffer.
+type ociPieceDef struct {
+	ociDef
+	buf       unsafe.Pointer // C buffer of a piece
+	pieceSize C.ub4
+	alenp     *C.ub4
+	indp      *C.sb2
+	rcodep    *C.ub2
+	iter      int
+	values    [][]byte
+	nulls     []bool
+}
+
+func (d *ociPieceDef) pieceDef() *ociPieceDef { return d }
+
+// defineDynamic defines the column for a piecewise fetch in pieces of at most
+// pieceSize bytes.
+func (d *ociPieceDef) defineDynamic(position int, pieceSize uint32, dty C.ub2) error {
+	if pieceSize == 0 || pieceSize > lobChunkSize {
+		pieceSize = lobChunkSize
+	}
+	if d.buf == nil || d.pieceSize != C.ub4(pieceSize) {
+		if d.buf != nil {
+			C.free(d.buf)
+		}
+		d.buf = C.malloc(C.size_t(pieceSize))
+		d.pieceSize = C.ub4(pieceSize)
+	}
+	if d.alenp == nil {
+		d.alenp = (*C.ub4)(C.malloc(C.size_t(unsafe.Sizeof(C.ub4(0)))))
+		d.indp = (*C.sb2)(C.malloc(C.size_t(unsafe.Sizeof(C.sb2(0)))))
+		d.rcodep = (*C.ub2)(C.malloc(C.size_t(unsafe.Sizeof(C.ub2(0)))))
+	}
+	d.reset()
+	if r := C.OCIDEFINEBYPOS(
+		d.rset.ocistmt,             //OCIStmt     *stmtp,
+		&d.ocidef,                  //OCIDefine   **defnpp,
+		d.rset.env.ocierr,          //OCIError    *errhp,
+		C.ub4(position),            //ub4         position,
+		nil,                        //void        *valuep,
+		C.LENGTH_TYPE(maxLongSize), //sb8         value_sz,
+		dty,                        //ub2         dty,
+		nil,                        //void        *indp,
+		nil,                        //ub4         *rlenp,
+		nil,                        //ub2         *rcodep,
+		C.OCI_DYNAMIC_FETCH,        //ub4         mode );
+	); r == C.OCI_ERROR {
+		return d.rset.env.ociError()
+	}
+	return nil
+}
+
+// reset empties the values before a fetch.
+func (d *ociPieceDef) reset() {
+	n := d.rset.fetchLen
+	if cap(d.values) < n {
+		d.values = make([][]byte, n)
+		d.nulls = make([]bool, n)
+	}
+	d.values, d.nulls = d.values[:n], d.nulls[:n]
+	for i := range d.values {
+		d.values[i] = d.values[i][:0]
+		d.nulls[i] = false
+	}
+}
+
+// next prepares the buffer for the piece of the value of the iter-th row.
+func (d *ociPieceDef) next(iter int, piece C.ub1) {
+	for iter >= len(d.values) {
+		d.values = append(d.values, nil)
+		d.nulls = append(d.nulls, false)
+	}
+	d.iter = iter
+	if piece == C.OCI_FIRST_PIECE || piece == C.OCI_ONE_PIECE {
+		d.values[iter] = d.values[iter][:0]
+		d.nulls[iter] = false
+	}
+	*d.alenp, *d.indp, *d.rcodep = d.pieceSize, 0, 0
+}
+
+// got appends the fetched piece to the value.
+func (d *ociPieceDef) got() {
+	if *d.indp < 0 {
+		d.nulls[d.iter] = true
+		return
+	}
+	if n := int(*d.alenp); n > 0 {
+		piece := (*[lobChunkSize]byte)(d.buf)[:n:n]
+		d.values[d.iter] = append(d.values[d.iter], piece...)
+	}
+}
+
+// isNull reports whether the value of the row at offset is null.
+func (d *ociPieceDef) isNull(offset int) bool {
+	return offset >= len(d.nulls) || d.nulls[offset]
+}
+
+func (d *ociPieceDef) free() {
+	d.arrHlp.close()
+	if d.buf != nil {
+		C.free(d.buf)
+		C.free(unsafe.Pointer(d.alenp))
+		C.free(unsafe.Pointer(d.indp))
+		C.free(unsafe.Pointer(d.rcodep))
+		d.buf, d.pieceSize = nil, 0
+		d.alenp, d.indp, d.rcodep = nil, nil, nil
+	}
+	d.values, d.nulls = nil, nil
+}
+
+// defLong defines a LONG column, fetched piecewise.
+type defLong struct {
+	ociPieceDef
+	isNullable bool
+}
+
+func (def *defLong) define(position int, pieceSize uint32, isNullable bool, rset *Rset) error {
+	def.rset = rset
+	def.isNullable = isNullable
+	return def.defineDynamic(position, pieceSize, C.SQLT_CHR)
+}
+
+func (def *defLong) value(offset int) (value interface{}, err error) {
+	if def.isNull(offset) {
+		if def.isNullable {
+			return String{IsNull: true}, nil
+		}
+		return "", nil
+	}
+	s, err := def.rset.env.decode(def.values[offset])
+	if err != nil {
+		return nil, err
+	}
+	if def.isNullable {
+		return String{Value: s}, nil
+	}
+	return s, nil
+}
+
+func (def *defLong) alloc() error { return nil }
+
+func (def *defLong) close() (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+
+	rset := def.rset
+	def.rset = nil
+	def.ocidef = nil
+	def.free()
+	rset.putDef(defIdxLong, def)
+	return nil
+}
@@ -10,46 +10,28 @@ package ora
 #include "version.h"
 */
 import "C"
-import (
-	"unsafe"
-)
 
+// defLongRaw defines a LONG RAW column, fetched piecewise.
 type defLongRaw struct {
-	ociDef
+	ociPieceDef
 	isNullable bool
-	buf        []byte
-	bufSize    int
 }
 
-func (def *defLongRaw) define(position int, bufSize uint32, isNullable bool, rset *Rset) error {
+func (def *defLongRaw) define(position int, pieceSize uint32, isNullable bool, rset *Rset) error {
 	def.rset = rset
 	def.isNullable = isNullable
-	if n := rset.fetchLen * int(bufSize); cap(def.buf) < n {
-		//def.buf = make([]byte, n)
-		def.buf = bytesPool.Get(n)
-	} else {
-		def.buf = def.buf[:n]
-	}
-	def.bufSize = int(bufSize)
-
-	return def.ociDef.defineByPos(position, unsafe.Pointer(&def.buf[0]), int(bufSize), C.SQLT_LBI)
+	return def.defineDynamic(position, pieceSize, C.SQLT_LBI)
 }
 
 func (def *defLongRaw) value(offset int) (value interface{}, err error) {
-	if def.nullInds[offset] < 0 {
+	if def.isNull(offset) {
 		if def.isNullable {
 			return Raw{IsNull: true}, nil
 		}
 		return nil, nil
 	}
-	// Make a slice of length equal to the return length
-	result := make([]byte, def.alen[offset])
-	// Copy returned data
-	copyLength := copy(result, def.buf[offset*def.bufSize:(offset+1)*def.bufSize])
-	if C.ACTUAL_LENGTH_TYPE(copyLength) != def.alen[offset] {
-		return nil, errNew("unable to copy LONG RAW result data from buffer")
-	}
-
+	result := make([]byte, len(def.values[offset]))
+	copy(result, def.values[offset])
 	if def.isNullable {
 		return Raw{Value: result}, nil
 	}
@@ -57,13 +39,6 @@ func (def *defLongRaw) value(offset int) (value interface{}, err error) {
 }
 
 func (def *defLongRaw) alloc() error { return nil }
-func (def *defLongRaw) free() {
-	def.arrHlp.close()
-	if def.buf != nil {
-		bytesPool.Put(def.buf)
-		def.buf = nil
-	}
-}
 
 func (def *defLongRaw) close() (err error) {
 	defer func() {
@@ -0,0 +1,101 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <stdlib.h>
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import "unsafe"
+
+type defObject struct {
+	ociDef
+	typ         *ObjectType
+	instances   []unsafe.Pointer
+	nullStructs []unsafe.Pointer
+}
+
+func (def *defObject) define(position int, typ *ObjectType, rset *Rset) error {
+	def.rset = rset
+	def.typ = typ
+	def.free()
+	def.instances = (*((*[MaxFetchLen]unsafe.Pointer)(C.calloc(C.size_t(rset.fetchLen), C.size_t(unsafe.Sizeof(unsafe.Pointer(nil)))))))[:rset.fetchLen]
+	def.nullStructs = (*((*[MaxFetchLen]unsafe.Pointer)(C.calloc(C.size_t(rset.fetchLen), C.size_t(unsafe.Sizeof(unsafe.Pointer(nil)))))))[:rset.fetchLen]
+
+	env := rset.env
+	if r := C.OCIDEFINEBYPOS(
+		rset.ocistmt,    //OCIStmt     *stmtp,
+		&def.ocidef,     //OCIDefine   **defnpp,
+		env.ocierr,      //OCIError    *errhp,
+		C.ub4(position), //ub4         position,
+		nil,             //void        *valuep,
+		0,               //sb8         value_sz,
+		C.SQLT_NTY,      //ub2         dty,
+		nil,             //void        *indp,
+		nil,             //ub4         *rlenp,
+		nil,             //ub2         *rcodep,
+		C.OCI_DEFAULT,   //ub4         mode );
+	); r == C.OCI_ERROR {
+		return env.ociError()
+	}
+	if r := C.OCIDefineObject(
+		def.ocidef,          //OCIDefine       *defnp,
+		env.ocierr,          //OCIError        *errhp,
+		typ.tdo,             //const OCIType   *type,
+		&def.instances[0],   //void            **pgvpp,
+		nil,                 //ub4             *pvszsp,
+		&def.nullStructs[0], //void            **indpp,
+		nil,                 //ub4             *indszp );
+	); r == C.OCI_ERROR {
+		return env.ociError()
+	}
+	return nil
+}
+
+func (def *defObject) value(offset int) (value interface{}, err error) {
+	ns := def.nullStructs[offset]
+	if def.instances[offset] == nil || ns == nil || *(*C.OCIInd)(ns) == C.OCI_IND_NULL {
+		return nil, nil
+	}
+	return def.rset.stmt.ses.objectFromInstance(def.typ, def.instances[offset], ns)
+}
+
+func (def *defObject) alloc() error { return nil }
+
+func (def *defObject) free() {
+	if def.instances != nil {
+		for i, p := range def.instances {
+			if p != nil {
+				def.rset.stmt.ses.freeObjectInstance(p)
+				def.instances[i] = nil
+			}
+		}
+		C.free(unsafe.Pointer(&def.instances[0]))
+		def.instances = nil
+	}
+	if def.nullStructs != nil {
+		C.free(unsafe.Pointer(&def.nullStructs[0]))
+		def.nullStructs = nil
+	}
+	def.arrHlp.close()
+}
+
+func (def *defObject) close() (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+
+	rset := def.rset
+	def.free()
+	def.rset = nil
+	def.ocidef = nil
+	def.typ = nil
+	rset.putDef(defIdxObject, def)
+	return nil
+}
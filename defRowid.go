@@ -16,32 +16,60 @@ import (
 
 const rowidLen = 19
 
+// sqltUrowid is the data type code of UROWID columns.
+const sqltUrowid = 208
+
 type defRowid struct {
 	ociDef
-	buf []byte
+	buf        []byte
+	bufLen     int
+	isNullable bool
+	isRowid    bool
 }
 
-func (def *defRowid) define(position int, rset *Rset) error {
+func (def *defRowid) define(position int, columnSize int, gct GoColumnType, rset *Rset) error {
 	def.rset = rset
+	def.isNullable = gct == OraS || gct == Rid
+	def.isRowid = gct == Rid
 	// using a character host variable of width between 19
 	// (18 bytes plus the null-terminator) and 4001 as the
 	// host bind variable for universal ROWID.
-	if n := rset.fetchLen * rowidLen; cap(def.buf) < n {
+	// The text of an UROWID is the base-64 form of its bytes.
+	def.bufLen = columnSize*4/3 + 3
+	if def.bufLen < rowidLen {
+		def.bufLen = rowidLen
+	}
+	if n := rset.fetchLen * def.bufLen; cap(def.buf) < n {
 		//def.buf = make([]byte, n)
 		def.buf = bytesPool.Get(n)
 	} else {
 		def.buf = def.buf[:n]
 	}
-	return def.ociDef.defineByPos(position, unsafe.Pointer(&def.buf[0]), rowidLen, C.SQLT_STR)
+	return def.ociDef.defineByPos(position, unsafe.Pointer(&def.buf[0]), def.bufLen, C.SQLT_STR)
 }
 
 func (def *defRowid) value(offset int) (value interface{}, err error) {
-	n := bytes.Index(def.buf[offset*rowidLen:(offset+1)*rowidLen], []byte{0})
-	if n == -1 {
-		n = rowidLen
+	if def.nullInds[offset] < 0 {
+		if def.isRowid {
+			return Rowid{IsNull: true}, nil
+		}
+		if def.isNullable {
+			return String{IsNull: true}, nil
+		}
+		return "", nil
+	}
+	b := def.buf[offset*def.bufLen : (offset+1)*def.bufLen]
+	if n := bytes.IndexByte(b, 0); n >= 0 {
+		b = b[:n]
+	}
+	s := string(b)
+	if def.isRowid {
+		return Rowid{Value: s}, nil
+	}
+	if def.isNullable {
+		return String{Value: s}, nil
 	}
-	value = string(def.buf[offset*rowidLen : offset*rowidLen+n])
-	return value, err
+	return s, nil
 }
 
 func (def *defRowid) alloc() error { return nil }
@@ -32,7 +32,8 @@ func (def *defRset) define(position int, rset *Rset) error {
 		if result.id == 0 {
 			result.id = _drv.rsetId.nextId()
 		}
-		result.autoClose = true
+		// the Stmt is the parent's: do not close it with the cursor
+		result.autoClose = false
 		result.env = def.rset.env
 		result.stmt = rset.stmt
 		result.ocistmt = rset.ocistmt
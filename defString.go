@@ -11,6 +11,7 @@ package ora
 */
 import "C"
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"strings"
@@ -23,6 +24,7 @@ type defString struct {
 	sync.RWMutex
 	buf               []byte
 	isNullable, rTrim bool
+	national          bool // NCHAR or NVARCHAR2
 	columnSize        int
 }
 
@@ -31,14 +33,14 @@ type defNumString struct {
 }
 
 func (def *defNumString) define(position int, isNullable bool, rset *Rset) error {
-	return def.defString.define(position, 40, isNullable, false, rset)
+	return def.defString.define(position, 40, isNullable, false, false, rset)
 }
 
-func (def *defString) define(position int, columnSize int, isNullable, rTrim bool, rset *Rset) error {
+func (def *defString) define(position int, columnSize int, isNullable, rTrim, national bool, rset *Rset) error {
 	def.Lock()
 	defer def.Unlock()
 	def.rset = rset
-	def.isNullable, def.rTrim = isNullable, rTrim
+	def.isNullable, def.rTrim, def.national = isNullable, rTrim, national
 	//Log.Infof("defString position=%d columnSize=%d", position, columnSize)
 	n := columnSize
 	// AL32UTF8: one db "char" can be 4 bytes on wire, esp. if the database's
@@ -52,7 +54,8 @@ func (def *defString) define(position int, columnSize int, isNullable, rTrim boo
 	isUTF8 := rset.stmt.ses.srv.IsUTF8()
 	rset.stmt.ses.RUnlock()
 	rset.stmt.RUnlock()
-	if !isUTF8 {
+	// The national character set is AL16UTF16 or UTF8 on the server.
+	if !isUTF8 || national {
 		n *= 2
 	}
 	if n == 0 {
@@ -69,7 +72,15 @@ func (def *defString) define(position int, columnSize int, isNullable, rTrim boo
 		def.buf = def.buf[:n]
 	}
 
-	return def.ociDef.defineByPos(position, unsafe.Pointer(&def.buf[0]), def.columnSize, C.SQLT_CHR)
+	if err := def.ociDef.defineByPos(position, unsafe.Pointer(&def.buf[0]), def.columnSize, C.SQLT_CHR); err != nil {
+		return err
+	}
+	if !national {
+		return nil
+	}
+	csfrm := C.ub1(C.SQLCS_NCHAR)
+	return rset.env.setAttr(unsafe.Pointer(def.ocidef), C.OCI_HTYPE_DEFINE,
+		unsafe.Pointer(&csfrm), 0, C.OCI_ATTR_CHARSET_FORM)
 }
 
 func (def *defString) value(offset int) (value interface{}, err error) {
@@ -90,7 +101,13 @@ func (def *defString) value(offset int) (value interface{}, err error) {
 	//	def, offset, def.alen, def.columnSize, def.buf[offset*def.columnSize:offset*def.columnSize+int(def.alen[offset])])
 	if def.alen[offset] > 0 {
 		off := offset * def.columnSize
-		s = string(def.buf[off : off+int(def.alen[offset])])
+		cs := def.rset.env.charset
+		if def.national {
+			cs = def.rset.env.ncharset
+		}
+		if s, err = cs.decode(def.buf[off : off+int(def.alen[offset])]); err != nil {
+			return nil, err
+		}
 		if def.rTrim {
 			s = strings.TrimRight(s, " ")
 		}
@@ -101,6 +118,28 @@ func (def *defString) value(offset int) (value interface{}, err error) {
 	return s, nil
 }
 
+// bytes returns the value of the row at offset, as UTF-8; with a UTF-8
+// charset, it is a slice of the define buffer. The caller holds the lock.
+func (def *defString) bytes(offset int) ([]byte, error) {
+	off := offset * def.columnSize
+	p := def.buf[off : off+int(def.alen[offset])]
+	if def.rTrim {
+		p = bytes.TrimRight(p, " ")
+	}
+	cs := def.rset.env.charset
+	if def.national {
+		cs = def.rset.env.ncharset
+	}
+	if cs.isUTF8() {
+		return p, nil
+	}
+	s, err := cs.decode(p)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
 func (def *defString) alloc() error {
 	return nil
 }
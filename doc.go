@@ -108,8 +108,7 @@ in `$PKG_CONFIG_PATH` or a system folder, such as
 
 	cp -aL contrib/oci8.pc /usr/local/lib/pkgconfig/oci8.pc
 
-The ora package has no external Go dependencies and is available on GitHub and
-gopkg.in:
+The ora package is available on GitHub and gopkg.in:
 
 	go get gopkg.in/rana/ora.v4
 
@@ -125,7 +124,7 @@ The ora package supports all built-in Oracle data types. The supported Oracle
 built-in data types are NUMBER, BINARY_DOUBLE, BINARY_FLOAT, FLOAT, DATE,
 TIMESTAMP, TIMESTAMP WITH TIME ZONE, TIMESTAMP WITH LOCAL TIME ZONE,
 INTERVAL YEAR TO MONTH, INTERVAL DAY TO SECOND, CHAR, NCHAR, VARCHAR, VARCHAR2,
-NVARCHAR2, LONG, CLOB, NCLOB, BLOB, LONG RAW, RAW, ROWID and BFILE.
+NVARCHAR2, LONG, CLOB, NCLOB, BLOB, LONG RAW, RAW, ROWID, UROWID and BFILE.
 SYS_REFCURSOR is also supported.
 
 Oracle does not provide a built-in boolean type. Oracle provides a single-byte
@@ -150,6 +149,23 @@ characters, prefixed with a colon (:). For example:
 Placeholders within a SQL statement are bound by position. The actual name is not
 used by the ora package driver e.g., placeholder names :c1, :1, or :xyz are
 treated equally.
+
+Queries written for other databases may use ? or $1 placeholders, which Oracle
+rejects. With StmtCfg.SetNumberPlaceholders(true) (also on SesCfg and DrvCfg)
+they are rewritten to :1..:n when the statement is prepared, leaving string
+literals and comments untouched. The parameters of $n placeholders are bound
+by n, so they may be repeated or out of order:
+
+	ses.SetCfg(ses.Cfg().SetNumberPlaceholders(true))
+	rset, err := ses.PrepAndQry("SELECT * FROM t WHERE a = $2 AND b = $1", b, a)
+
+The lex package tokenizes SQL and PL/SQL text without a database. It lists
+the placeholders of a statement, skipping string literals (including q'[...]'
+quoting), quoted identifiers and comments, and tells whether the statement is
+a query, DML, DDL, PL/SQL block or CALL, and whether it is terminated:
+
+	st, err := lex.Parse("BEGIN :x := f(:y); END;")
+	// st.Kind == lex.PLSQL, st.Terminated == true, st.BindNames() == ["X", "Y"]
 */
 //
 // LastInsertId
@@ -253,6 +269,9 @@ may be changed. The Go-to-Oracle type mapping for the ora package is:
 	[]float64, []float32
 	[]Float64, []Float32
 
+	*big.Int, *big.Float		NUMBER⁸
+	*big.Rat
+
 	time.Time			TIMESTAMP, TIMESTAMP WITH TIME ZONE,
 	Time				TIMESTAMP WITH LOCAL TIME ZONE, DATE
 	*time.Time
@@ -277,6 +296,8 @@ may be changed. The Go-to-Oracle type mapping for the ora package is:
 
 	Raw, []Raw			RAW, LONG RAW
 
+	Rowid, *Rowid		ROWID, UROWID
+
 	IntervalYM			INTERVAL MONTH TO YEAR
 	[]IntervalYM
 
@@ -285,6 +306,13 @@ may be changed. The Go-to-Oracle type mapping for the ora package is:
 
 	Bfile				BFILE
 
+	Object, *Object		OBJECT, VARRAY, nested TABLE⁴
+	Record				PL/SQL RECORD⁵
+	PlsMap				PL/SQL INDEX BY PLS_INTEGER or VARCHAR2 table⁶
+
+	JSON				JSON⁷
+	json.RawMessage
+
 	° A select-list column defined as an Oracle NUMBER with zero scale e.g.,
 	NUMBER(10,0) is returned as an int64 by default. Integer and floating point
 	numerics may be inserted into a NUMBER column with zero scale. Inserting a
@@ -302,6 +330,35 @@ may be changed. The Go-to-Oracle type mapping for the ora package is:
 	³ The Go bool value false is mapped to the zero rune '0'. The Go bool value
 	true is mapped to the one rune '1'.
 
+	⁴ User-defined types must be described with Ses.DescribeType before an
+	Object can be bound; select-list columns are described automatically.
+	Attributes and collection elements are converted to the Go types above,
+	nested objects and collections are returned as *Object. Use Object.Decode
+	to store an Object into a struct or slice, and ObjectType.NewObject to
+	create one from a struct, map or slice.
+
+	⁵ PL/SQL RECORD types, and BOOLEAN attributes, require Oracle 12.1 or later
+	on both client and server. Describe the type with Ses.DescribeType using
+	the PACKAGE.TYPE or SCHEMA.PACKAGE.TYPE name. A Record with a pointer
+	Value is bound IN OUT and the result is decoded back into the Value.
+
+	⁶ Slices passed to ExeP are bound as dense tables indexed from 1. A PlsMap
+	binds a map[int32]T as a (sparse) INDEX BY PLS_INTEGER table, and a
+	map[string]T as an INDEX BY VARCHAR2 table, within a generated PL/SQL block.
+
+	⁷ The JSON data type requires Oracle 21c or later on both client and
+	server; a JSON Value is marshaled to OSON with the oson package. With older
+	servers JSON is bound as text (IN only). JSON columns, and with
+	StmtCfg.SetJSONLobs the CLOB or BLOB columns with an IS JSON check
+	constraint, are returned as json.RawMessage by default, or as the decoded
+	value with JV.
+
+	⁸ NUMBER columns are returned as *big.Int (truncated), *big.Float or *big.Rat
+	with BigI, BigF and BigR, without the int64 and float64 limits. Big numbers
+	are bound as IN parameters; a nil pointer is bound as NULL. Values with more
+	than 40 significant digits (39 with an odd decimal exponent) are rounded,
+	except for *big.Int which is an error.
+
 An example of using the ora package directly:
 
 	package main
@@ -653,6 +710,16 @@ GoColumnTypes defined by the ora package are:
 
 	Lob°		Bin or S
 
+	json.RawMessage	J
+
+	interface{}	JV
+
+	*big.Int	BigI
+
+	*big.Float	BigF
+
+	*big.Rat	BigR
+
 	default¹	D
 
 	° Lob will return binary data if the Oracle column is a BLOB; otherwise, Lob
@@ -684,6 +751,22 @@ with ses.Prep() will use SesCfg.StmtCfg if it is specified; otherwise, a new
 StmtCfg with default values is set on the Stmt. Call Stmt.Cfg() to change a Stmt's
 configuration.
 
+EnvCfg.Charset and EnvCfg.NCharset choose the client character sets of the
+Env, AL32UTF8 by default. With another client character set, such as
+WE8ISO8859P1, the statement text, the string binds and the CHAR, VARCHAR2 and
+LONG values are transcoded to and from UTF-8 by the ora package, and CLOBs are
+read and written as AL32UTF8 by OCI. Characters which are missing from the
+character set are replaced, or return an error with EnvCfg.StrictCharset:
+
+	env, err := ora.OpenEnv(ora.EnvCfg{Charset: "WE8ISO8859P1", StrictCharset: true})
+
+NCHAR, NVARCHAR2 and NCLOB columns are fetched in the national character set
+form, transcoded with EnvCfg.NCharset, so their characters survive even when
+they are missing from the database character set. To bind a string in the
+national form, for example to insert into an NVARCHAR2 column, use NString:
+
+	_, err = ses.PrepAndExe("INSERT INTO t (nc) VALUES (:1)", ora.NString("árvíztűrő ≠ 日本"))
+
 An Env may contain multiple Srv. A Srv may contain multiple Ses. A Ses may
 contain multiple Stmt. A Stmt may contain multiple Rset.
 
@@ -753,13 +836,55 @@ Another scenario may be to configure the runes mapped to bool values:
 		fmt.Println(rset.Row[0])
 	}
 
+The rows of a Rset are fetched in arrays of StmtCfg.FetchLen rows, or of
+StmtCfg.LOBFetchLen rows with LOB and LONG columns. With
+StmtCfg.SetAdaptiveFetch the array length is computed from the widths of the
+select-list columns instead, to fit StmtCfg.FetchMemory, so narrow queries
+fetch up to MaxFetchLen rows in a round trip, and queries of LOBs only a few.
+StmtCfg.SetFetchGrowth starts with short fetches, and doubles their length as
+long as that makes them faster per row:
+
+	ses.SetCfg(ses.Cfg().SetAdaptiveFetch(true).SetFetchGrowth(true))
+
+With Oracle 12.1 or later, bool, *bool and Bool parameters of a PL/SQL block
+may be bound as native PL/SQL BOOLEAN values instead of runes:
+
+	stmt, err = ses.Prep("BEGIN :1 := pkg.is_valid; END;")
+	stmt.SetCfg(stmt.Cfg().SetPlsBool(true))
+	var ok bool
+	stmt.Exe(&ok)
+
 Oracle-specific types offered by the ora package are ora.Rset, ora.IntervalYM,
 ora.IntervalDS, ora.Raw, ora.Lob and ora.Bfile. ora.Rset represents an Oracle
 SYS_REFCURSOR. ora.IntervalYM represents an Oracle INTERVAL YEAR TO MONTH.
 ora.IntervalDS represents an Oracle INTERVAL DAY TO SECOND. ora.Raw represents
 an Oracle RAW or LONG RAW. ora.Lob may represent an Oracle BLOB or Oracle CLOB.
-And ora.Bfile represents an Oracle BFILE. ROWID columns are returned as strings and
-don't have a unique Go type.
+And ora.Bfile represents an Oracle BFILE. ROWID and UROWID columns are returned
+as strings by default, or as ora.Rowid with the Rid GoColumnType (or
+RsetCfg.SetRowid(ora.Rid)).
+
+An extended ROWID (of a heap table) can be decoded, and encoded, to split a
+table into ROWID ranges, without DBMS_ROWID:
+
+	x, err := rowid.Extended() // x.Object, x.File, x.Block, x.Row
+	from := ora.ExtendedRowid{Object: x.Object, File: x.File, Block: 128}.Rowid()
+	to := ora.ExtendedRowid{Object: x.Object, File: x.File, Block: 255, Row: 65535}.Rowid()
+	rset, err := ses.PrepAndQry("SELECT * FROM t WHERE ROWID BETWEEN :1 AND :2", from, to)
+
+LONG and LONG RAW columns are fetched piecewise, in pieces of at most
+StmtCfg.LongBufferSize and LongRawBufferSize bytes, so their values (such as
+ALL_VIEWS.TEXT) are returned whole, whatever their length.
+
+Ses.BfileExists and Ses.BfileSize tell whether the file of a fetched or
+constructed Bfile exists on the server and its length, and Ses.OpenBfile
+returns an io.ReadCloser of its content:
+
+	r, err := ses.OpenBfile(ora.Bfile{DirectoryAlias: "DOCS_DIR", Filename: "a.pdf"})
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	_, err = io.Copy(w, r)
 
 #### LOBs
 
@@ -782,6 +907,33 @@ Failing to adhere these rules results in "Invalid handle" and ORA-03127 errors.
 You cannot start reading another LOB till you haven't finished reading the previous
 LOB, not even in the same row! Failing this results in ORA-24804!
 
+To read or change parts of a LOB in place, fetch it with ora.Loc as an
+*ora.LobLocator (SELECT ... FOR UPDATE to write), or bind a *LobLocator as an
+output parameter. LobLocator has Size, ChunkSize, ReadAt, ReadCharsAt, WriteAt,
+Read, Write, Seek, Append, Trim and Truncate, each being a round trip to the
+server. ReadAt reads BLOBs only, as the offsets of CLOBs are in characters;
+ReadCharsAt reads both. Group many writes between Open and Close:
+
+	loc := new(ora.LobLocator)
+	_, err = ses.PrepAndExe("UPDATE docs SET body = body WHERE id = :1 RETURNING body INTO :2", id, loc)
+	defer loc.Free()
+	chunk, err := loc.ChunkSize()
+	err = loc.Open(false)
+	_, err = loc.WriteAt(page, int64(n*chunk))
+	err = loc.Close()
+
+LobLocator.Writer returns an io.WriteCloser streaming straight into the LOB in
+chunk-aligned pieces, without a temporary LOB:
+
+	loc := new(ora.LobLocator)
+	_, err = ses.PrepAndExe("INSERT INTO docs (id, body) VALUES (:1, EMPTY_BLOB()) RETURNING body INTO :2", id, loc)
+	defer loc.Free()
+	w, err := loc.Writer()
+	zw := gzip.NewWriter(w)
+	_, err = io.Copy(zw, src)
+	err = zw.Close()
+	err = w.Close()
+
 For examples, see [z_lob_test.go](z_lob_test.go).
 
 #### Rset
@@ -805,6 +957,63 @@ statement:
 		fmt.Println(rset.Index, rset.Row[0], rset.Row[1], rset.Row[2])
 	}
 
+Instead of type asserting the values of Row, Rset.Scan copies them into typed
+destinations, converting them as database/sql's Rows.Scan does, and returns an
+error when a value cannot be converted:
+
+	var c1 int64
+	var c2 string
+	var c3 ora.String // C3 may be NULL
+	for rset.Next() {
+		if err = rset.Scan(&c1, &c2, &c3); err != nil {
+			return err
+		}
+	}
+
+Rset.ScanStruct copies the current row into a struct, mapping the columns to
+the fields by `db` tag or case-insensitive name, and Ses.QryStructs collects
+all the rows of a query into a slice of structs. A nested cursor column is
+collected into a slice field. StmtCfg.SetStructMode chooses whether the
+columns without a field, or the fields without a column, are errors:
+
+	type emp struct {
+		ID   int64  `db:"empno"`
+		Name string `db:"ename"`
+		Dept string `db:"dname"`
+	}
+	var emps []emp
+	err = ses.QryStructs(&emps, "SELECT empno, ename, dname FROM emp JOIN dept USING (deptno)")
+
+For extracts and analytics, Rset.NextBatch fetches rows into the typed column
+vectors of a Batch, straight from the define buffers, without boxing each
+value into Row. The vectors use the Apache Arrow layouts (validity bitmap,
+value buffers, offsets and data), so they can be handed to Arrow as is:
+
+	for {
+		b, err := rset.NextBatch(1000)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		ids := b.Columns[0].Int64s[:b.Len]
+		names := &b.Columns[1] // names.String(i) or names.Bytes(i)
+	}
+
+Rset.NextNoRow steps over the rows without populating Row. The typed
+accessors Int64, Float64, String, Bytes, Time and IsNull, and Scan, then read
+the column values of the current row straight from the define buffers, so a
+row allocates only for the values asked for:
+
+	for rset.NextNoRow() {
+		id, err := rset.Int64(0)
+		if err != nil {
+			return err
+		}
+		name, err := rset.Bytes(1) // valid until the next NextNoRow
+		...
+	}
+
 Or, *Rset may be passed to Stmt.Exe when prepared with a stored procedure accepting
 an OUT SYS_REFCURSOR parameter:
 
@@ -846,6 +1055,34 @@ multiple Rsets:
 		}
 	}
 
+A query executed with StmtCfg.SetScrollable(true) returns a scrollable Rset,
+which can be navigated with Prev, First, Last, Absolute and Relative besides
+Next. Rows are fetched FetchLen at a time, backward when moving backward:
+
+	stmt.SetCfg(stmt.Cfg().SetScrollable(true))
+	rset, err := stmt.Qry()
+	rset.Last()
+	fmt.Println(rset.Position(), rset.Row)
+	for rset.Prev() {
+		fmt.Println(rset.Row)
+	}
+
+Implicit result sets, returned by a PL/SQL block with DBMS_SQL.RETURN_RESULT
+(Oracle 12.1+), are read with Stmt.NextResultSet after Exe, or with
+Rows.NextResultSet in database/sql:
+
+	stmt, err = ses.Prep("BEGIN PROC2; END;")
+	stmt.Exe()
+	for {
+		rset, err := stmt.NextResultSet()
+		if err != nil || rset == nil {
+			break
+		}
+		for rset.Next() {
+			fmt.Println(rset.Row[0])
+		}
+	}
+
 The types of values assigned to Row may be configured in StmtCfg.Rset. For configuration
 to take effect, assign StmtCfg.Rset prior to calling Stmt.Qry or Stmt.Exe.
 
@@ -1232,6 +1469,44 @@ Some helpful SQL maintenance statements:
 
 Run the tests.
 
+Timeouts and Cancellation
+
+StmtCfg.SetCallTimeout limits each round trip to the server of statement
+executions, Rset fetches, LOB reads, and transaction starts and commits. The
+deadline of the context given to the database/sql Context methods limits them,
+too, and a canceled context interrupts the running call:
+
+	ses.SetCfg(ses.Cfg().SetCallTimeout(30 * time.Second))
+
+With Oracle 18c or newer clients the limit is enforced by OCI itself
+(OCI_ATTR_CALL_TIMEOUT), and the session remains usable. With older clients
+the running call is interrupted with Ses.Break when the time is up, and a
+canceled context breaks the running call with any client. This needs a
+goroutine per call that watches the context; it breaks the session only
+while the call runs, but a call that has just finished its last round trip
+may still be broken, which resets the then idle session.
+An interrupted call returns an error with errors.Cause context.DeadlineExceeded
+(or context.Canceled); the database/sql methods return the context error itself.
+
+The native API has Context variants, too: Ses.PrepAndExeContext,
+Ses.PrepAndQryContext, Stmt.ExeContext, Stmt.QryContext, Rset.NextContext,
+Ses.StartTxContext, Tx.CommitContext and Pool.GetContext. The context of
+QryContext limits the fetches of the Rset, and the context of StartTxContext
+limits Commit:
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	rset, err := ses.PrepAndQryContext(ctx, "SELECT object_name FROM all_objects")
+	if err != nil {
+		return err
+	}
+	for rset.Next() {
+		...
+	}
+	if errors.Cause(rset.Err()) == context.DeadlineExceeded {
+		...
+	}
+
 Limitations
 
 database/sql method Stmt.QueryRow is not supported.
@@ -60,6 +60,34 @@ func (c DrvCfg) SetByteSlice(gct GoColumnType) DrvCfg {
 	c.StmtCfg = c.StmtCfg.SetByteSlice(gct)
 	return c
 }
+func (c DrvCfg) SetPlsBool(plsBool bool) DrvCfg {
+	c.StmtCfg = c.StmtCfg.SetPlsBool(plsBool)
+	return c
+}
+func (c DrvCfg) SetScrollable(scrollable bool) DrvCfg {
+	c.StmtCfg = c.StmtCfg.SetScrollable(scrollable)
+	return c
+}
+func (c DrvCfg) SetNumberPlaceholders(numberPlaceholders bool) DrvCfg {
+	c.StmtCfg = c.StmtCfg.SetNumberPlaceholders(numberPlaceholders)
+	return c
+}
+func (c DrvCfg) SetCallTimeout(timeout time.Duration) DrvCfg {
+	c.StmtCfg = c.StmtCfg.SetCallTimeout(timeout)
+	return c
+}
+func (c DrvCfg) SetAdaptiveFetch(adaptive bool) DrvCfg {
+	c.StmtCfg = c.StmtCfg.SetAdaptiveFetch(adaptive)
+	return c
+}
+func (c DrvCfg) SetFetchMemory(size int) DrvCfg {
+	c.StmtCfg = c.StmtCfg.SetFetchMemory(size)
+	return c
+}
+func (c DrvCfg) SetFetchGrowth(growth bool) DrvCfg {
+	c.StmtCfg = c.StmtCfg.SetFetchGrowth(growth)
+	return c
+}
 func (c DrvCfg) SetNumberInt(gct GoColumnType) DrvCfg {
 	c.StmtCfg = c.StmtCfg.SetNumberInt(gct)
 	return c
@@ -106,6 +134,16 @@ func (c DrvCfg) SetClob(gct GoColumnType) DrvCfg    { c.StmtCfg = c.StmtCfg.SetC
 func (c DrvCfg) SetBlob(gct GoColumnType) DrvCfg    { c.StmtCfg = c.StmtCfg.SetBlob(gct); return c }
 func (c DrvCfg) SetRaw(gct GoColumnType) DrvCfg     { c.StmtCfg = c.StmtCfg.SetRaw(gct); return c }
 func (c DrvCfg) SetLongRaw(gct GoColumnType) DrvCfg { c.StmtCfg = c.StmtCfg.SetLongRaw(gct); return c }
+func (c DrvCfg) SetJSON(gct GoColumnType) DrvCfg    { c.StmtCfg = c.StmtCfg.SetJSON(gct); return c }
+func (c DrvCfg) SetRowid(gct GoColumnType) DrvCfg   { c.StmtCfg = c.StmtCfg.SetRowid(gct); return c }
+func (c DrvCfg) SetJSONLobs(jsonLobs bool) DrvCfg {
+	c.StmtCfg = c.StmtCfg.SetJSONLobs(jsonLobs)
+	return c
+}
+func (c DrvCfg) SetStructMode(mode StructMode) DrvCfg {
+	c.StmtCfg = c.StmtCfg.SetStructMode(mode)
+	return c
+}
 
 func (c DrvCfg) SetLogger(lgr Logger) DrvCfg { c.Log.Logger = lgr; return c }
 
@@ -8,6 +8,7 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"io"
+	"math"
 	"reflect"
 	"strconv"
 	"time"
@@ -23,6 +24,10 @@ import "C"
 // DrvQueryResult implements the driver.Rows interface.
 type DrvQueryResult struct {
 	rset *Rset
+	stmt *Stmt
+	// next is the implicit result set after rset, fetched by HasNextResultSet
+	next   *Rset
+	noMore bool
 }
 
 // Next populates the specified slice with the next row of data.
@@ -40,6 +45,9 @@ func (qr *DrvQueryResult) Next(dest []driver.Value) (err error) {
 		qr.rset.closeWithRemove()
 		// but without this close, memory consumption grows!
 		qr.rset = nil
+		if ctxErr := contextCause(err); ctxErr != nil {
+			return ctxErr
+		}
 		return err
 	}
 	defer qr.rset.endRow()
@@ -63,11 +71,38 @@ func (qr *DrvQueryResult) Next(dest []driver.Value) (err error) {
 }
 
 // HasNextResultSet reports whether there is another result set after the current one.
-func (qr *DrvQueryResult) HasNextResultSet() bool { return false }
+//
+// Only PL/SQL blocks returning implicit result sets (DBMS_SQL.RETURN_RESULT)
+// have more than one result set.
+func (qr *DrvQueryResult) HasNextResultSet() bool {
+	if qr.next != nil {
+		return true
+	}
+	if qr.noMore || qr.stmt == nil {
+		return false
+	}
+	next, err := qr.stmt.NextResultSet()
+	if err != nil || next == nil {
+		qr.noMore = true
+		return false
+	}
+	qr.next = next
+	return true
+}
 
 // NextResultSet advances the driver to the next result set even
 // if there are remaining rows in the current result set.
-func (qr *DrvQueryResult) NextResultSet() error { return io.EOF }
+func (qr *DrvQueryResult) NextResultSet() error {
+	if !qr.HasNextResultSet() {
+		return io.EOF
+	}
+	var err error
+	if qr.rset != nil {
+		err = qr.rset.closeWithRemove()
+	}
+	qr.rset, qr.next = qr.next, nil
+	return err
+}
 
 // Columns returns query column names.
 //
@@ -95,6 +130,11 @@ func (qr *DrvQueryResult) ColumnTypeDatabaseTypeName(index int) string {
 	qr.rset.RLock()
 	x := qr.rset.Columns[index].Type
 	qr.rset.RUnlock()
+	return sqltName(x)
+}
+
+// sqltName returns the name of the OCI data type x.
+func sqltName(x C.ub2) string {
 	switch x {
 	case C.SQLT_CHR:
 		return "VARCHAR2"
@@ -136,6 +176,8 @@ func (qr *DrvQueryResult) ColumnTypeDatabaseTypeName(index int) string {
 		return "CHARZ"
 	case C.SQLT_RDD:
 		return "ROWID"
+	case sqltUrowid:
+		return "UROWID"
 	case C.SQLT_NTY:
 		return "NAMED"
 	case C.SQLT_REF:
@@ -167,6 +209,70 @@ func (qr *DrvQueryResult) ColumnTypeDatabaseTypeName(index int) string {
 	}
 }
 
+// columnTypeName returns the SQL type of the column c, with its length,
+// precision and scale.
+func columnTypeName(c Column) string {
+	national := c.CharsetForm == C.SQLCS_NCHAR
+	switch c.Type {
+	case C.SQLT_CHR, C.SQLT_AFC:
+		name := "CHAR"
+		if c.Type == C.SQLT_CHR {
+			name = "VARCHAR2"
+		}
+		switch {
+		case national:
+			return fmt.Sprintf("N%s(%d)", name, c.CharLength)
+		case c.CharUsed:
+			return fmt.Sprintf("%s(%d CHAR)", name, c.CharLength)
+		}
+		return fmt.Sprintf("%s(%d)", name, c.Length)
+	case C.SQLT_CLOB:
+		if national {
+			return "NCLOB"
+		}
+		return "CLOB"
+	case C.SQLT_BIN:
+		return fmt.Sprintf("RAW(%d)", c.Length)
+	case C.SQLT_NUM:
+		switch {
+		case c.Precision == 0 && c.Scale == -127:
+			return "NUMBER"
+		case c.Precision == 0:
+			return fmt.Sprintf("NUMBER(*,%d)", c.Scale)
+		case c.Scale == -127:
+			return fmt.Sprintf("FLOAT(%d)", c.Precision)
+		case c.Scale == 0:
+			return fmt.Sprintf("NUMBER(%d)", c.Precision)
+		}
+		return fmt.Sprintf("NUMBER(%d,%d)", c.Precision, c.Scale)
+	case C.SQLT_IBFLOAT:
+		return "BINARY_FLOAT"
+	case C.SQLT_IBDOUBLE:
+		return "BINARY_DOUBLE"
+	case C.SQLT_TIMESTAMP:
+		return fmt.Sprintf("TIMESTAMP(%d)", c.Scale)
+	case C.SQLT_TIMESTAMP_TZ:
+		return fmt.Sprintf("TIMESTAMP(%d) WITH TIME ZONE", c.Scale)
+	case C.SQLT_TIMESTAMP_LTZ:
+		return fmt.Sprintf("TIMESTAMP(%d) WITH LOCAL TIME ZONE", c.Scale)
+	case C.SQLT_INTERVAL_YM:
+		return fmt.Sprintf("INTERVAL YEAR(%d) TO MONTH", c.Precision)
+	case C.SQLT_INTERVAL_DS:
+		return fmt.Sprintf("INTERVAL DAY(%d) TO SECOND(%d)", c.Precision, c.Scale)
+	case C.SQLT_FILE:
+		return "BFILE"
+	case C.SQLT_RSET:
+		return "REF CURSOR"
+	case C.SQLT_JSON:
+		return "JSON"
+	case C.SQLT_NTY:
+		return c.ObjectSchema + "." + c.ObjectName
+	case C.SQLT_REF:
+		return "REF " + c.ObjectSchema + "." + c.ObjectName
+	}
+	return sqltName(c.Type)
+}
+
 // ColumnTypeLength returns the length of the column type
 // if the column is a variable length type.
 // If the column is not a variable length type ok should return false.
@@ -179,17 +285,31 @@ func (qr *DrvQueryResult) ColumnTypeLength(index int) (length int64, ok bool) {
 	qr.rset.RLock()
 	c := qr.rset.Columns[index]
 	qr.rset.RUnlock()
-	if c.Length == 0 {
-		return 0, false
+	switch c.Type {
+	case C.SQLT_CHR, C.SQLT_AFC:
+		if c.CharUsed || c.CharsetForm == C.SQLCS_NCHAR {
+			return int64(c.CharLength), true
+		}
+		return int64(c.Length), true
+	case C.SQLT_BIN, C.SQLT_RDD, sqltUrowid:
+		return int64(c.Length), true
+	case C.SQLT_CLOB, C.SQLT_BLOB, C.SQLT_LNG, C.SQLT_LBI:
+		return math.MaxInt64, true
 	}
-	return int64(c.Length), true
+	return 0, false
 }
 
 // ColumnTypeNullable returns true if it is known the column may be null,
 // or false if the column is known to be not nullable.
 // If the column nullability is unknown, ok should be false.
 func (qr *DrvQueryResult) ColumnTypeNullable(index int) (nullable, ok bool) {
-	return true, true
+	if qr.rset == nil {
+		return false, false
+	}
+	qr.rset.RLock()
+	nullable = qr.rset.Columns[index].Nullable
+	qr.rset.RUnlock()
+	return nullable, true
 }
 
 // ColumnTypePrecisionScale return the precision and scale for decimal types.
@@ -207,13 +327,18 @@ func (qr *DrvQueryResult) ColumnTypePrecisionScale(index int) (precision, scale
 	return 0, 0, false
 }
 
+// ColumnTypeScanType returns the type of the values of the column,
+// Column.GoType, as returned by Next.
 func (qr *DrvQueryResult) ColumnTypeScanType(index int) reflect.Type {
 	if qr.rset == nil {
 		return nil
 	}
 	qr.rset.RLock()
-	x := qr.rset.Columns[index].Type
+	x, goType := qr.rset.Columns[index].Type, qr.rset.Columns[index].GoType
 	qr.rset.RUnlock()
+	if goType != nil {
+		return goType
+	}
 	switch x {
 	case C.SQLT_CHR, C.SQLT_STR, C.SQLT_LNG, C.SQLT_VCS, C.SQLT_LVC, C.SQLT_AFC, C.SQLT_AVC, C.SQLT_CLOB, C.SQLT_VST:
 		return reflect.TypeOf("")
@@ -246,6 +371,10 @@ func (qr *DrvQueryResult) ColumnTypeScanType(index int) reflect.Type {
 //
 // Close is a member of the driver.Rows interface.
 func (qr *DrvQueryResult) Close() error {
+	if qr.next != nil {
+		qr.next.closeWithRemove()
+		qr.next = nil
+	}
 	if qr.rset == nil {
 		return nil
 	}
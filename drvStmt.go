@@ -90,7 +90,7 @@ func (ds *DrvStmt) Query(values []driver.Value) (driver.Rows, error) {
 	if err != nil {
 		return nil, maybeBadConn(err)
 	}
-	return &DrvQueryResult{rset: rset}, nil
+	return &DrvQueryResult{rset: rset, stmt: ds.stmt}, nil
 }
 
 // sysName returns a string representing the DrvStmt.
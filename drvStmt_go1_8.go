@@ -26,29 +26,9 @@ func (ds *DrvStmt) ExecContext(ctx context.Context, values []driver.NamedValue)
 		return nil, err
 	}
 
-	done := make(chan struct{})
-	go func() {
-		select {
-		case <-done:
-		case <-ctx.Done():
-			// select again to avoid race condition if both are done
-			select {
-			case <-done:
-			default:
-				if isCanceled(ctx.Err()) {
-					ds.stmt.RLock()
-					ses := ds.stmt.ses
-					ds.stmt.RUnlock()
-					ses.Break()
-				}
-			}
-		}
-	}()
-
 	var err error
 	var res DrvExecResult
 	res.rowsAffected, res.lastInsertId, err = ds.stmt.exeC(ctx, params, false)
-	close(done)
 
 	if err != nil {
 		return nil, maybeBadConn(err)
@@ -73,32 +53,12 @@ func (ds *DrvStmt) QueryContext(ctx context.Context, values []driver.NamedValue)
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
-	done := make(chan struct{})
-	go func() {
-		select {
-		case <-done:
-		case <-ctx.Done():
-			// select again to avoid race condition if both are done
-			select {
-			case <-done:
-			default:
-				if isCanceled(ctx.Err()) {
-					ds.stmt.RLock()
-					ses := ds.stmt.ses
-					ds.stmt.RUnlock()
-					ses.Break()
-				}
-			}
-		}
-	}()
-
 	rset, err := ds.stmt.qryC(ctx, params)
-	close(done)
 
 	if err != nil {
 		return nil, maybeBadConn(err)
 	}
-	return &DrvQueryResult{rset: rset}, nil
+	return &DrvQueryResult{rset: rset, stmt: ds.stmt}, nil
 }
 
 // vim: set fileencoding=utf-8 noet:
@@ -59,6 +59,9 @@ type Env struct {
 	ociHndMu sync.Mutex
 	isPkgEnv bool
 
+	// charset and ncharset are the client character sets
+	charset, ncharset *charset
+
 	openSrvs *srvList
 	openCons *conList
 
@@ -100,6 +103,7 @@ func (env *Env) Close() (err error) {
 		env.SetCfg(StmtCfg{})
 		env.Lock()
 		env.isPkgEnv = false
+		env.charset, env.ncharset = nil, nil
 		env.ocienv = nil
 		env.ocierr = nil
 		env.Unlock()
@@ -510,6 +514,9 @@ func (env *Env) ociError(prefix ...string) error {
 		C.ub4(len(env.errBuf)),
 		C.OCI_HTYPE_ERROR)
 	msg := C.GoString(&env.errBuf[0])
+	if s, err := env.charset.decode([]byte(msg)); err == nil {
+		msg = s
+	}
 	env.RUnlock()
 	return er(&ORAError{
 		code:    int(errcode),
@@ -518,6 +525,24 @@ func (env *Env) ociError(prefix ...string) error {
 	})
 }
 
+// encode converts the UTF-8 s to the client character set of the Env.
+func (env *Env) encode(s string) (string, error) {
+	return env.charset.encode(s)
+}
+
+// decode converts p from the client character set of the Env to UTF-8.
+func (env *Env) decode(p []byte) (string, error) {
+	return env.charset.decode(p)
+}
+
+// Charset returns the Oracle name of the client character set of the Env.
+func (env *Env) Charset() string {
+	if env.charset == nil {
+		return "AL32UTF8"
+	}
+	return env.charset.name
+}
+
 type ORAError struct {
 	code            int
 	prefix, message string
@@ -0,0 +1,223 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// csvEncoder writes CSV and TSV.
+type csvEncoder struct {
+	*exporter
+	csv    *csv.Writer // nil for TSV
+	record []string
+}
+
+func (e *csvEncoder) begin() error {
+	if e.opts.Format == CSV {
+		e.csv = csv.NewWriter(e.Writer)
+		e.csv.Comma = e.opts.Comma
+		e.csv.UseCRLF = e.opts.UseCRLF
+	}
+	e.record = make([]string, len(e.columns))
+	if !e.opts.Header {
+		return nil
+	}
+	for i, col := range e.columns {
+		e.record[i] = col.Name
+	}
+	return e.write()
+}
+
+func (e *csvEncoder) encode(values []interface{}) error {
+	for i, v := range values {
+		if v == nil {
+			e.record[i] = e.opts.Null
+			continue
+		}
+		e.record[i] = e.text(v, e.columns[i])
+	}
+	return e.write()
+}
+
+func (e *csvEncoder) write() error {
+	if e.csv != nil {
+		return e.csv.Write(e.record)
+	}
+	for i, s := range e.record {
+		if i > 0 {
+			e.WriteByte('\t')
+		}
+		tsvEscaper.WriteString(e.Writer, s)
+	}
+	if e.opts.UseCRLF {
+		e.WriteByte('\r')
+	}
+	return e.WriteByte('\n')
+}
+
+func (e *csvEncoder) end() error {
+	if e.csv == nil {
+		return nil
+	}
+	e.csv.Flush()
+	return e.csv.Error()
+}
+
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// jsonEncoder writes NDJSON and JSON arrays.
+type jsonEncoder struct {
+	*exporter
+	names [][]byte // the quoted column names, with the colon
+}
+
+func (e *jsonEncoder) begin() error {
+	e.names = make([][]byte, len(e.columns))
+	for i, col := range e.columns {
+		b, err := json.Marshal(col.Name)
+		if err != nil {
+			return err
+		}
+		e.names[i] = append(b, ':')
+	}
+	if e.opts.Format == JSON {
+		return e.WriteByte('[')
+	}
+	return nil
+}
+
+func (e *jsonEncoder) encode(values []interface{}) error {
+	if e.opts.Format == JSON {
+		if e.row > 1 {
+			e.WriteByte(',')
+		}
+		e.WriteByte('\n')
+	}
+	e.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			e.WriteByte(',')
+		}
+		e.Write(e.names[i])
+		if err := e.value(v, e.columns[i]); err != nil {
+			return err
+		}
+	}
+	e.WriteByte('}')
+	if e.opts.Format == NDJSON {
+		return e.WriteByte('\n')
+	}
+	return nil
+}
+
+// value writes v as a JSON value.
+func (e *jsonEncoder) value(v interface{}, col Column) error {
+	switch x := v.(type) {
+	case nil:
+		_, err := e.WriteString("null")
+		return err
+	case bool, int64:
+		_, err := e.WriteString(e.text(x, col))
+		return err
+	case float64:
+		if isFinite(x) {
+			_, err := e.WriteString(e.text(x, col))
+			return err
+		}
+	case Number:
+		_, err := e.WriteString(jsonNumber(string(x)))
+		return err
+	}
+	b, err := json.Marshal(e.text(v, col))
+	if err != nil {
+		return err
+	}
+	_, err = e.Write(b)
+	return err
+}
+
+func (e *jsonEncoder) end() error {
+	if e.opts.Format != JSON {
+		return nil
+	}
+	if e.row > 0 {
+		e.WriteByte('\n')
+	}
+	_, err := e.WriteString("]\n")
+	return err
+}
+
+// jsonNumber returns the decimal number s in the JSON syntax, which needs a
+// digit before the decimal point.
+func jsonNumber(s string) string {
+	if strings.HasPrefix(s, ".") {
+		return "0" + s
+	}
+	if strings.HasPrefix(s, "-.") {
+		return "-0" + s[1:]
+	}
+	return s
+}
+
+// xmlEncoder writes XML.
+type xmlEncoder struct {
+	*exporter
+	names []string // the element names of the columns
+}
+
+func (e *xmlEncoder) begin() error {
+	e.names = make([]string, len(e.columns))
+	for i, col := range e.columns {
+		e.names[i] = xmlName(col.Name)
+	}
+	_, err := e.WriteString(xml.Header + "<" + xmlName(e.opts.RootTag) + ">\n")
+	return err
+}
+
+func (e *xmlEncoder) encode(values []interface{}) error {
+	row := xmlName(e.opts.RowTag)
+	e.WriteString(" <" + row + ">\n")
+	for i, v := range values {
+		if v == nil {
+			continue
+		}
+		e.WriteString("  <" + e.names[i] + ">")
+		if err := xml.EscapeText(e.Writer, []byte(e.text(v, e.columns[i]))); err != nil {
+			return err
+		}
+		e.WriteString("</" + e.names[i] + ">\n")
+	}
+	_, err := e.WriteString(" </" + row + ">\n")
+	return err
+}
+
+func (e *xmlEncoder) end() error {
+	_, err := e.WriteString("</" + xmlName(e.opts.RootTag) + ">\n")
+	return err
+}
+
+// xmlName returns name as an XML element name, with the invalid characters
+// replaced by '_'.
+func xmlName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, name)
+	if name == "" {
+		return "_"
+	}
+	if r, _ := utf8.DecodeRuneInString(name); !unicode.IsLetter(r) && r != '_' {
+		return "_" + name
+	}
+	return name
+}
@@ -0,0 +1,292 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+// Package export streams the rows of a query as RFC 4180 CSV, TSV, NDJSON,
+// a JSON array or XML.
+//
+// Write reads the rows from a Rows: FromRset adapts an *ora.Rset, and
+// FromSQLRows an *sql.Rows. The rows are written as they are fetched, so the
+// size of the result is not limited by memory:
+//
+//	rset, err := ses.PrepAndQry("SELECT * FROM emp")
+//	if err != nil {
+//		return err
+//	}
+//	n, err := export.Write(os.Stdout, export.FromRset(rset), export.Options{
+//		Format: export.CSV,
+//		Header: true,
+//		Null:   `\N`,
+//	})
+//
+// The Options set the text of NULL values, the formats of dates and
+// floating-point numbers, and how binary values and LOBs are written: inline,
+// in base64, or into sidecar files.
+package export
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Format is the output format of Write.
+type Format uint8
+
+const (
+	// CSV is RFC 4180 comma-separated values, quoted as needed.
+	CSV Format = iota
+	// TSV is tab-separated values, with tab, newline, carriage return and
+	// backslash escaped as \t, \n, \r and \\.
+	TSV
+	// NDJSON writes a JSON object per row, each on its own line.
+	NDJSON
+	// JSON writes a JSON array of an object per row.
+	JSON
+	// XML writes a root element holding an element per row, which holds an
+	// element per non-NULL column, in the form of DBMS_XMLGEN.
+	XML
+)
+
+// LOBMode is how the binary values and the LOBs are written.
+type LOBMode uint8
+
+const (
+	// LOBInline writes the LOBs inline, and the binary values in
+	// hexadecimal.
+	LOBInline LOBMode = iota
+	// LOBBase64 writes the LOBs inline, and the binary values in standard
+	// base64.
+	LOBBase64
+	// LOBFile writes each CLOB, NCLOB and BLOB value into a sidecar file in
+	// Options.LOBDir, and the name of the file as the value. The other binary
+	// values are written in hexadecimal.
+	LOBFile
+)
+
+// Options configure Write. The zero value writes CSV without a header.
+type Options struct {
+	Format Format
+
+	// Header writes the column names as the first row of CSV and TSV.
+	Header bool
+
+	// Comma is the separator of CSV; the default is ','.
+	Comma rune
+
+	// UseCRLF ends the rows of CSV and TSV with \r\n, instead of \n.
+	UseCRLF bool
+
+	// Null is the text of NULL values in CSV and TSV; the default is the
+	// empty string. NULL is null in JSON, and the column is left out in XML.
+	Null string
+
+	// TimeFormat is the layout of dates and timestamps for time.Format; the
+	// default is time.RFC3339Nano.
+	TimeFormat string
+
+	// FloatFormat and FloatPrec format the floating-point numbers, as
+	// strconv.FormatFloat; the default is the shortest exact form ('g', -1).
+	// The exact numbers (NUMBER fetched as a decimal) are written as is.
+	FloatFormat byte
+	FloatPrec   int
+
+	// LOB is how the binary values and the LOBs are written.
+	LOB LOBMode
+
+	// LOBDir is the directory of the sidecar files of LOBFile; the default
+	// is the current directory. The files are named after the column, its
+	// position from 1 and the number of the row, such as DOC_2_12.txt and
+	// IMAGE_3_12.bin, so that columns with the same name in a file name do
+	// not overwrite each other's files.
+	LOBDir string
+
+	// RootTag and RowTag name the elements of XML; the defaults are ROWSET
+	// and ROW.
+	RootTag, RowTag string
+}
+
+// Write writes the rows of rows to w in the format of opts, and returns the
+// number of rows written.
+func Write(w io.Writer, rows Rows, opts Options) (int, error) {
+	if opts.Comma == 0 {
+		opts.Comma = ','
+	}
+	if opts.TimeFormat == "" {
+		opts.TimeFormat = time.RFC3339Nano
+	}
+	if opts.FloatFormat == 0 {
+		opts.FloatFormat, opts.FloatPrec = 'g', -1
+	}
+	if opts.RootTag == "" {
+		opts.RootTag = "ROWSET"
+	}
+	if opts.RowTag == "" {
+		opts.RowTag = "ROW"
+	}
+	bw := bufio.NewWriterSize(w, 65536)
+	x := &exporter{Writer: bw, opts: opts, columns: rows.Columns()}
+	var enc encoder
+	switch opts.Format {
+	case CSV, TSV:
+		enc = &csvEncoder{exporter: x}
+	case NDJSON, JSON:
+		enc = &jsonEncoder{exporter: x}
+	case XML:
+		enc = &xmlEncoder{exporter: x}
+	default:
+		return 0, errors.Errorf("unknown format %d", opts.Format)
+	}
+
+	if err := enc.begin(); err != nil {
+		return 0, err
+	}
+	values := make([]interface{}, len(x.columns))
+	for {
+		for i := range values {
+			values[i] = nil
+		}
+		if err := rows.Next(values); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return x.row, err
+		}
+		x.row++
+		for i, v := range values {
+			var err error
+			if values[i], err = x.resolve(i, v); err != nil {
+				return x.row - 1, err
+			}
+		}
+		if err := enc.encode(values); err != nil {
+			return x.row - 1, err
+		}
+	}
+	if err := enc.end(); err != nil {
+		return x.row, err
+	}
+	return x.row, bw.Flush()
+}
+
+// encoder writes the rows in a format.
+type encoder interface {
+	begin() error
+	encode(values []interface{}) error
+	end() error
+}
+
+// exporter holds the state shared by the encoders.
+type exporter struct {
+	*bufio.Writer
+	opts    Options
+	columns []Column
+	row     int // the number of the current row, from 1
+}
+
+// resolve reads the LOB streams of column i, and writes the LOBs into the
+// sidecar files of LOBFile.
+func (x *exporter) resolve(i int, v interface{}) (interface{}, error) {
+	col := x.columns[i]
+	if x.opts.LOB == LOBFile && col.LOB && v != nil {
+		return x.writeFile(i, v)
+	}
+	r, ok := v.(io.Reader)
+	if !ok {
+		return v, nil
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read %s of row %d", col.Name, x.row)
+	}
+	if col.Binary {
+		return b, nil
+	}
+	return string(b), nil
+}
+
+// writeFile writes the LOB value v of column i into its sidecar file, and
+// returns the name of the file.
+func (x *exporter) writeFile(i int, v interface{}) (interface{}, error) {
+	col := x.columns[i]
+	ext := ".txt"
+	if col.Binary {
+		ext = ".bin"
+	}
+	name := fileName(col.Name) + "_" + strconv.Itoa(i+1) + "_" + strconv.Itoa(x.row) + ext
+	fh, err := os.Create(filepath.Join(x.opts.LOBDir, name))
+	if err != nil {
+		return nil, errors.Wrapf(err, "create sidecar file of %s", col.Name)
+	}
+	switch v := v.(type) {
+	case io.Reader:
+		_, err = io.Copy(fh, v)
+	case []byte:
+		_, err = fh.Write(v)
+	case string:
+		_, err = io.WriteString(fh, v)
+	default:
+		_, err = io.WriteString(fh, x.text(v, col))
+	}
+	if closeErr := fh.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "write %s", fh.Name())
+	}
+	return name, nil
+}
+
+// text returns the text form of the non-NULL value v of col.
+func (x *exporter) text(v interface{}, col Column) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []byte:
+		if !col.Binary {
+			return string(v)
+		}
+		if x.opts.LOB == LOBBase64 {
+			return base64.StdEncoding.EncodeToString(v)
+		}
+		return strings.ToUpper(hex.EncodeToString(v))
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, x.opts.FloatFormat, x.opts.FloatPrec, 64)
+	case Number:
+		return string(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(x.opts.TimeFormat)
+	}
+	return toString(v)
+}
+
+// fileName returns name, with the characters that are not safe in file names
+// replaced by '_'.
+func fileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' || r == '.' || r == '$' || r == '#' ||
+			'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// isFinite reports whether f is neither infinite nor NaN.
+func isFinite(f float64) bool {
+	return !math.IsInf(f, 0) && !math.IsNaN(f)
+}
@@ -0,0 +1,216 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sliceRows are Rows of values in memory.
+type sliceRows struct {
+	columns []Column
+	rows    [][]interface{}
+}
+
+func (r *sliceRows) Columns() []Column { return r.columns }
+
+func (r *sliceRows) Next(dest []interface{}) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func testRows() *sliceRows {
+	ts := time.Date(2017, 3, 4, 5, 6, 7, 0, time.UTC)
+	return &sliceRows{
+		columns: []Column{
+			NewColumn("ID", "NUMBER(10)"),
+			NewColumn("AMOUNT", "NUMBER(12,2)"),
+			NewColumn("NAME", "VARCHAR2(20 CHAR)"),
+			NewColumn("CREATED", "DATE"),
+			NewColumn("BIN", "RAW(2)"),
+		},
+		rows: [][]interface{}{
+			{int64(1), Number("12.50"), `a "b", c`, ts, []byte{0xca, 0xfe}},
+			{int64(2), nil, "tab\there\nline", nil, nil},
+		},
+	}
+}
+
+func TestWrite(t *testing.T) {
+	for i, tc := range []struct {
+		opts Options
+		want string
+	}{
+		{Options{Header: true, Null: "NULL"},
+			"ID,AMOUNT,NAME,CREATED,BIN\n" +
+				"1,12.50,\"a \"\"b\"\", c\",2017-03-04T05:06:07Z,CAFE\n" +
+				"2,NULL,\"tab\there\nline\",NULL,NULL\n"},
+		{Options{Format: TSV, TimeFormat: "2006-01-02", LOB: LOBBase64},
+			"1\t12.50\ta \"b\", c\t2017-03-04\tyv4=\n" +
+				"2\t\ttab\\there\\nline\t\t\n"},
+		{Options{Format: NDJSON},
+			`{"ID":1,"AMOUNT":12.50,"NAME":"a \"b\", c","CREATED":"2017-03-04T05:06:07Z","BIN":"CAFE"}` + "\n" +
+				`{"ID":2,"AMOUNT":null,"NAME":"tab\there\nline","CREATED":null,"BIN":null}` + "\n"},
+		{Options{Format: XML, RowTag: "emp"},
+			xmlHeader + "<ROWSET>\n <emp>\n  <ID>1</ID>\n  <AMOUNT>12.50</AMOUNT>\n  <NAME>a &#34;b&#34;, c</NAME>\n" +
+				"  <CREATED>2017-03-04T05:06:07Z</CREATED>\n  <BIN>CAFE</BIN>\n </emp>\n" +
+				" <emp>\n  <ID>2</ID>\n  <NAME>tab&#x9;here&#xA;line</NAME>\n </emp>\n</ROWSET>\n"},
+	} {
+		var buf bytes.Buffer
+		n, err := Write(&buf, testRows(), tc.opts)
+		if err != nil {
+			t.Errorf("%d. %v", i, err)
+			continue
+		}
+		if n != 2 {
+			t.Errorf("%d. got %d rows, wanted 2", i, n)
+		}
+		if got := buf.String(); got != tc.want {
+			t.Errorf("%d. got\n%s\nwanted\n%s", i, got, tc.want)
+		}
+	}
+}
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8"?>` + "\n"
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := Write(&buf, testRows(), Options{Format: JSON}); err != nil {
+		t.Fatal(err)
+	}
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &rows); err != nil {
+		t.Fatalf("%s: %v", buf.String(), err)
+	}
+	if len(rows) != 2 || rows[0]["NAME"] != `a "b", c` || rows[1]["AMOUNT"] != nil {
+		t.Errorf("got %v", rows)
+	}
+
+	buf.Reset()
+	empty := &sliceRows{columns: []Column{NewColumn("X", "NUMBER")}}
+	if _, err := Write(&buf, empty, Options{Format: JSON}); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "[]\n" {
+		t.Errorf("got %q, wanted %q", got, "[]\n")
+	}
+
+	buf.Reset()
+	special := &sliceRows{
+		columns: []Column{NewColumn("F", "BINARY_DOUBLE"), NewColumn("N", "NUMBER")},
+		rows:    [][]interface{}{{math.Inf(1), Number("-.5")}},
+	}
+	if _, err := Write(&buf, special, Options{Format: NDJSON}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), `{"F":"+Inf","N":-0.5}`+"\n"; got != want {
+		t.Errorf("got %q, wanted %q", got, want)
+	}
+}
+
+func TestWriteLOBFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	rows := &sliceRows{
+		columns: []Column{NewColumn("ID", "NUMBER"), NewColumn("DOC", "CLOB"), NewColumn("IMG", "BLOB")},
+		rows: [][]interface{}{
+			{int64(1), strings.NewReader("text"), bytes.NewReader([]byte{1, 2, 3})},
+			{int64(2), nil, nil},
+		},
+	}
+	var buf bytes.Buffer
+	if _, err = Write(&buf, rows, Options{LOB: LOBFile, LOBDir: dir}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "1,DOC_2_1.txt,IMG_3_1.bin\n2,,\n"; got != want {
+		t.Errorf("got %q, wanted %q", got, want)
+	}
+	for name, want := range map[string]string{"DOC_2_1.txt": "text", "IMG_3_1.bin": "\x01\x02\x03"} {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Error(err)
+		} else if string(b) != want {
+			t.Errorf("%s: got %q, wanted %q", name, b, want)
+		}
+	}
+}
+
+func TestWriteLOBFileNames(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	// both names are A_B in a file name
+	rows := &sliceRows{
+		columns: []Column{NewColumn("A B", "CLOB"), NewColumn("A_B", "CLOB")},
+		rows:    [][]interface{}{{"first", "second"}},
+	}
+	var buf bytes.Buffer
+	if _, err = Write(&buf, rows, Options{LOB: LOBFile, LOBDir: dir}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "A_B_1_1.txt,A_B_2_1.txt\n"; got != want {
+		t.Errorf("got %q, wanted %q", got, want)
+	}
+	for name, want := range map[string]string{"A_B_1_1.txt": "first", "A_B_2_1.txt": "second"} {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Error(err)
+		} else if string(b) != want {
+			t.Errorf("%s: got %q, wanted %q", name, b, want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	type nullable struct {
+		IsNull bool
+		Value  float32
+	}
+	for i, tc := range []struct {
+		in, want interface{}
+	}{
+		{int32(3), int64(3)},
+		{uint64(1 << 63), Number("9223372036854775808")},
+		{nullable{Value: 1.5}, float64(1.5)},
+		{nullable{IsNull: true}, nil},
+		{(*string)(nil), nil},
+	} {
+		if got := normalize(tc.in); got != tc.want {
+			t.Errorf("%d. %#v: got %#v, wanted %#v", i, tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestXMLName(t *testing.T) {
+	for in, want := range map[string]string{
+		"NAME":     "NAME",
+		"1st col":  "_1st_col",
+		"ÉV":       "ÉV",
+		"":         "_",
+		`"quoted"`: "_quoted_",
+	} {
+		if got := xmlName(in); got != want {
+			t.Errorf("%q: got %q, wanted %q", in, got, want)
+		}
+	}
+}
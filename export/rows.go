@@ -0,0 +1,190 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package export
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rows is a result set to export.
+type Rows interface {
+	// Columns describes the columns of the rows.
+	Columns() []Column
+
+	// Next copies the values of the next row into dest, and returns io.EOF
+	// after the last row.
+	//
+	// The values are nil (NULL), bool, int64, float64, Number, string,
+	// []byte, time.Time or an io.Reader of a LOB; anything else is written
+	// in its fmt.Stringer or %v form.
+	Next(dest []interface{}) error
+}
+
+// Column describes a column of Rows.
+type Column struct {
+	Name string
+	// TypeName is the SQL type of the column, such as VARCHAR2 or BLOB.
+	TypeName string
+	// Binary reports whether the []byte and io.Reader values of the column
+	// are binary (RAW, LONG RAW or BLOB), not text.
+	Binary bool
+	// LOB reports whether the column is a CLOB, NCLOB or BLOB.
+	LOB bool
+}
+
+// NewColumn returns the Column of the SQL type typeName, which may hold the
+// length, precision and scale.
+func NewColumn(name, typeName string) Column {
+	base := strings.ToUpper(typeName)
+	if i := strings.IndexByte(base, '('); i >= 0 {
+		base = base[:i]
+	}
+	base = strings.TrimSpace(base)
+	return Column{
+		Name:     name,
+		TypeName: typeName,
+		Binary:   base == "RAW" || base == "LONG RAW" || base == "BLOB",
+		LOB:      base == "CLOB" || base == "NCLOB" || base == "BLOB",
+	}
+}
+
+// Number is an exact decimal number, such as a NUMBER fetched as a decimal
+// string; it is written as is.
+type Number string
+
+// FromSQLRows returns the Rows of rows. rows is not closed.
+func FromSQLRows(rows *sql.Rows) (Rows, error) {
+	cts, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	r := &sqlRows{rows: rows, columns: make([]Column, len(cts))}
+	for i, ct := range cts {
+		r.columns[i] = NewColumn(ct.Name(), ct.DatabaseTypeName())
+	}
+	r.ptrs = make([]interface{}, len(cts))
+	r.values = make([]interface{}, len(cts))
+	for i := range r.ptrs {
+		r.ptrs[i] = &r.values[i]
+	}
+	return r, nil
+}
+
+type sqlRows struct {
+	rows    *sql.Rows
+	columns []Column
+	values  []interface{}
+	ptrs    []interface{}
+}
+
+func (r *sqlRows) Columns() []Column { return r.columns }
+
+func (r *sqlRows) Next(dest []interface{}) error {
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return err
+		}
+		return io.EOF
+	}
+	if err := r.rows.Scan(r.ptrs...); err != nil {
+		return err
+	}
+	for i, v := range r.values {
+		dest[i] = normalize(v)
+	}
+	return nil
+}
+
+// normalize converts v to one of the types of Rows.Next.
+func normalize(v interface{}) interface{} {
+	switch x := v.(type) {
+	case nil, bool, int64, float64, Number, string, []byte, time.Time, io.Reader:
+		return x
+	case int:
+		return int64(x)
+	case int32:
+		return int64(x)
+	case int16:
+		return int64(x)
+	case int8:
+		return int64(x)
+	case uint:
+		return normalize(uint64(x))
+	case uint64:
+		if x > math.MaxInt64 {
+			return Number(strconv.FormatUint(x, 10))
+		}
+		return int64(x)
+	case uint32:
+		return int64(x)
+	case uint16:
+		return int64(x)
+	case uint8:
+		return int64(x)
+	case float32:
+		return float64(x)
+	case *big.Int:
+		if x == nil {
+			return nil
+		}
+		return Number(x.String())
+	case *big.Float:
+		if x == nil {
+			return nil
+		}
+		return Number(x.Text('g', -1))
+	case *big.Rat:
+		if x == nil {
+			return nil
+		}
+		if x.IsInt() {
+			return Number(x.Num().String())
+		}
+		return Number(x.FloatString(40))
+	}
+
+	// nullable types, with IsNull and Value fields
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		if _, ok := v.(fmt.Stringer); !ok {
+			return normalize(rv.Elem().Interface())
+		}
+	}
+	if rv.Kind() == reflect.Struct {
+		if f := rv.FieldByName("IsNull"); f.IsValid() && f.Kind() == reflect.Bool {
+			if f.Bool() {
+				return nil
+			}
+			if val := rv.FieldByName("Value"); val.IsValid() && val.CanInterface() {
+				return normalize(val.Interface())
+			}
+		}
+	}
+	if x, ok := v.(interface {
+		IsNull() bool
+	}); ok && x.IsNull() {
+		return nil
+	}
+	return v
+}
+
+// toString returns the text form of a value of another type.
+func toString(v interface{}) string {
+	if s, ok := v.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,88 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package export
+
+import (
+	"io"
+
+	"gopkg.in/rana/ora.v4"
+)
+
+// FromRset returns the Rows of rset, which is exhausted by Write.
+//
+// The LOBs fetched as *ora.Lob (the L GoColumnType) or *ora.LobLocator (the
+// Loc GoColumnType) are streamed, without reading them into memory first, and
+// closed or freed after their row is written.
+func FromRset(rset *ora.Rset) Rows {
+	columns := make([]Column, len(rset.Columns))
+	for i, c := range rset.Columns {
+		columns[i] = NewColumn(c.Name, c.TypeName)
+	}
+	return &rsetRows{rset: rset, columns: columns}
+}
+
+type rsetRows struct {
+	rset    *ora.Rset
+	columns []Column
+	lobs    []*ora.Lob
+	locs    []*ora.LobLocator
+}
+
+func (r *rsetRows) Columns() []Column { return r.columns }
+
+func (r *rsetRows) Next(dest []interface{}) error {
+	// the LOBs of the previous row are written by now
+	for _, lob := range r.lobs {
+		lob.Close()
+	}
+	r.lobs = r.lobs[:0]
+	for _, loc := range r.locs {
+		loc.Free()
+	}
+	r.locs = r.locs[:0]
+	if !r.rset.Next() {
+		if err := r.rset.Err(); err != nil {
+			return err
+		}
+		return io.EOF
+	}
+	for i, v := range r.rset.Row {
+		switch x := v.(type) {
+		case *ora.Lob:
+			if x == nil || x.Reader == nil {
+				dest[i] = nil
+				continue
+			}
+			r.lobs = append(r.lobs, x)
+			dest[i] = x.Reader
+		case *ora.LobLocator:
+			if x.IsNull() {
+				dest[i] = nil
+				continue
+			}
+			r.locs = append(r.locs, x)
+			dest[i] = x
+		case ora.Num:
+			dest[i] = Number(x)
+		case ora.OCINum:
+			dest[i] = Number(x.String())
+		case ora.OraOCINum:
+			if x.IsNull {
+				dest[i] = nil
+			} else {
+				dest[i] = Number(x.Value.String())
+			}
+		case ora.Date:
+			if x.IsNull() {
+				dest[i] = nil
+			} else {
+				dest[i] = x.Get()
+			}
+		default:
+			dest[i] = normalize(v)
+		}
+	}
+	return nil
+}
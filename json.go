@@ -0,0 +1,174 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"unsafe"
+
+	"gopkg.in/rana/ora.v4/oson"
+)
+
+// JSON wraps a Go value to be bound as an Oracle JSON value.
+//
+// Value is converted with the oson package: a json.RawMessage or
+// *json.RawMessage is parsed as JSON text, other values, strings and *string
+// included, are marshaled as with encoding/json. A nil Value, or a nil
+// pointer, map or slice, is bound as NULL. A non-nil pointer Value is bound
+// IN OUT: a *json.RawMessage receives the returned JSON text, and the other
+// pointers have it decoded into them.
+//
+// The JSON data type needs Oracle 21c or newer, both on the client and the
+// server; otherwise Value is bound as JSON text, as for a CLOB or VARCHAR2
+// column with an IS JSON check constraint, and only IN binds are supported.
+type JSON struct {
+	Value interface{}
+}
+
+// isNull reports whether the Value is nil, or a nil pointer, map or slice.
+func (j JSON) isNull() bool {
+	if j.Value == nil {
+		return true
+	}
+	rv := reflect.ValueOf(j.Value)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// isPtr reports whether the Value is to be returned.
+func (j JSON) isPtr() bool {
+	return !j.isNull() && reflect.TypeOf(j.Value).Kind() == reflect.Ptr
+}
+
+// text returns the JSON text of the Value.
+func (j JSON) text() (string, error) {
+	switch x := j.Value.(type) {
+	case json.RawMessage:
+		return string(x), nil
+	case *json.RawMessage:
+		return string(*x), nil
+	}
+	b, err := json.Marshal(j.Value)
+	return string(b), err
+}
+
+// oson returns the OSON image of the Value.
+func (j JSON) oson() ([]byte, error) {
+	switch x := j.Value.(type) {
+	case *json.RawMessage:
+		return oson.FromJSON(*x)
+	case *interface{}:
+		return oson.Marshal(*x)
+	}
+	return oson.Marshal(j.Value)
+}
+
+// decode stores the JSON, either OSON or text, into the Value.
+func (j JSON) decode(data []byte) error {
+	v, err := jsonValue(J, data, false)
+	if err != nil {
+		return err
+	}
+	raw := v.(json.RawMessage)
+	if x, ok := j.Value.(*json.RawMessage); ok {
+		*x = raw
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	return dec.Decode(j.Value)
+}
+
+// jsonValue converts the JSON data, either OSON or text, to the Go type
+// of gct: J, JV, S or OraS.
+func jsonValue(gct GoColumnType, data []byte, isNull bool) (interface{}, error) {
+	if isNull {
+		switch gct {
+		case S:
+			return "", nil
+		case OraS:
+			return String{IsNull: true}, nil
+		}
+		return nil, nil
+	}
+	isOSON := oson.IsOSON(data)
+	if gct == JV {
+		if isOSON {
+			return oson.Decode(data)
+		}
+		var v interface{}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err != nil {
+			return nil, errE(err)
+		}
+		return v, nil
+	}
+	if isOSON {
+		var err error
+		if data, err = oson.ToJSON(data); err != nil {
+			return nil, errE(err)
+		}
+	}
+	switch gct {
+	case S:
+		return string(data), nil
+	case OraS:
+		return String{Value: string(data)}, nil
+	}
+	return json.RawMessage(data), nil
+}
+
+// jsonToOSON returns the OSON image of the OCIJson descriptor.
+func (ses *Ses) jsonToOSON(jsond unsafe.Pointer) ([]byte, error) {
+	env := ses.srv.env
+	size := C.oraub8(32 << 10)
+	for {
+		buf := make([]byte, int(size))
+		n := size
+		r := C.jsonToBinary(ses.ocisvcctx, jsond, (*C.ub1)(unsafe.Pointer(&buf[0])), &n, env.ocierr)
+		if r == C.OCI_ERROR {
+			if n > size {
+				// buffer too small
+				size = n
+				continue
+			}
+			return nil, env.ociError()
+		}
+		return buf[:int(n)], nil
+	}
+}
+
+// bindJSON binds the value as JSON, or as JSON text when the client or the
+// server does not support the JSON data type.
+func (stmt *Stmt) bindJSON(value JSON, position namedPos) (bnd, error) {
+	if stmt.ses.hasJSON() {
+		bnd := stmt.getBnd(bndIdxJSON).(*bndJSON)
+		return bnd, bnd.bind(value, position, stmt)
+	}
+	if value.isPtr() {
+		return nil, errF("binding JSON OUT needs Oracle 21c or newer")
+	}
+	if value.isNull() {
+		bnd := stmt.getBnd(bndIdxNil).(*bndNil)
+		return bnd, bnd.bind(position, C.SQLT_CHR, stmt)
+	}
+	text, err := value.text()
+	if err != nil {
+		return nil, errE(err)
+	}
+	bnd := stmt.getBnd(bndIdxString).(*bndString)
+	return bnd, bnd.bind(text, position, stmt)
+}
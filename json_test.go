@@ -0,0 +1,75 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONIsNull(t *testing.T) {
+	var doc struct{ A int }
+	for i, tc := range []struct {
+		value         interface{}
+		isNull, isPtr bool
+	}{
+		{nil, true, false},
+		{(*json.RawMessage)(nil), true, false},
+		{(*string)(nil), true, false},
+		{map[string]interface{}(nil), true, false},
+		{json.RawMessage(nil), true, false},
+		{json.RawMessage("{}"), false, false},
+		{&doc, false, true},
+		{doc, false, false},
+		{0, false, false},
+	} {
+		j := JSON{Value: tc.value}
+		if got := j.isNull(); got != tc.isNull {
+			t.Errorf("%d. %#v: isNull got %t, wanted %t", i, tc.value, got, tc.isNull)
+		}
+		if got := j.isPtr(); got != tc.isPtr {
+			t.Errorf("%d. %#v: isPtr got %t, wanted %t", i, tc.value, got, tc.isPtr)
+		}
+	}
+}
+
+func TestJSONText(t *testing.T) {
+	s, raw := "abc", json.RawMessage(`{"a":1}`)
+	for i, tc := range []struct {
+		value interface{}
+		want  string
+	}{
+		{s, `"abc"`},
+		{&s, `"abc"`},
+		{raw, `{"a":1}`},
+		{&raw, `{"a":1}`},
+		{map[string]int{"a": 1}, `{"a":1}`},
+	} {
+		got, err := JSON{Value: tc.value}.text()
+		if err != nil {
+			t.Errorf("%d. %#v: %v", i, tc.value, err)
+		} else if got != tc.want {
+			t.Errorf("%d. %#v: got %s, wanted %s", i, tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestJSONDecode(t *testing.T) {
+	var s string
+	if err := (JSON{Value: &s}).decode([]byte(`"abc"`)); err != nil {
+		t.Fatal(err)
+	} else if s != "abc" {
+		t.Errorf("*string: got %q, wanted %q", s, "abc")
+	}
+	if err := (JSON{Value: &s}).decode([]byte(`{"a":1}`)); err == nil {
+		t.Errorf("*string: decoded an object into %q", s)
+	}
+	var raw json.RawMessage
+	if err := (JSON{Value: &raw}).decode([]byte(`{"a":1}`)); err != nil {
+		t.Fatal(err)
+	} else if string(raw) != `{"a":1}` {
+		t.Errorf("*json.RawMessage: got %s, wanted %s", raw, `{"a":1}`)
+	}
+}
@@ -0,0 +1,332 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+// Package lex is a lexer for Oracle SQL and PL/SQL text.
+//
+// It needs no database: Tokenize splits the text into tokens, Binds lists
+// the bind placeholders (:name, :1, :"Name"), and Parse classifies the
+// statement and tells whether it is terminated.
+//
+// String literals ('text', N'text', q'[it's]'), quoted identifiers, comments
+// and hints are single tokens, so placeholders inside them are never found.
+// Oracle comments don't nest: a /* inside a comment is just text, and the
+// comment ends at the first */.
+package lex
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// TokenType is the type of a Token.
+type TokenType uint8
+
+const (
+	Space       TokenType = iota // white space
+	Comment                      // -- line or /* block */ comment
+	Hint                         // --+ or /*+ */ optimizer hint
+	Ident                        // identifier or keyword
+	QuotedIdent                  // "quoted identifier"
+	String                       // 'text', N'text', q'[text]'
+	Number                       // 1, 1.5, .5e-3, 2f, 3d
+	Bind                         // :name, :1, :"Name"
+	Operator                     // operator or punctuation, including ; and /
+)
+
+func (t TokenType) String() string {
+	switch t {
+	case Space:
+		return "Space"
+	case Comment:
+		return "Comment"
+	case Hint:
+		return "Hint"
+	case Ident:
+		return "Ident"
+	case QuotedIdent:
+		return "QuotedIdent"
+	case String:
+		return "String"
+	case Number:
+		return "Number"
+	case Bind:
+		return "Bind"
+	case Operator:
+		return "Operator"
+	}
+	return fmt.Sprintf("TokenType(%d)", t)
+}
+
+// Token is a lexical token.
+type Token struct {
+	Type TokenType
+	Text string // the token as written
+	Pos  int    // byte offset of the token in the text
+}
+
+// IsSignificant reports whether the token is neither space nor a comment.
+// Hints are not significant either.
+func (t Token) IsSignificant() bool {
+	return t.Type != Space && t.Type != Comment && t.Type != Hint
+}
+
+// BindName returns the name of a Bind token: upper-cased unless quoted,
+// without the colon, as OCI reports it. It returns "" for other tokens.
+func (t Token) BindName() string {
+	if t.Type != Bind {
+		return ""
+	}
+	name := t.Text[1:]
+	if name[0] == '"' {
+		return name[1 : len(name)-1]
+	}
+	return strings.ToUpper(name)
+}
+
+// ErrUnterminated is returned for string literals, quoted identifiers and
+// comments without their closing quote or delimiter.
+var ErrUnterminated = errors.New("unterminated")
+
+// Tokenize splits the text into tokens.
+//
+// On error, the tokens lexed so far are returned, the last one running to the
+// end of the text.
+func Tokenize(text string) ([]Token, error) {
+	var tokens []Token
+	l := Lexer{text: text}
+	for {
+		tok, err := l.Next()
+		if tok.Text != "" {
+			tokens = append(tokens, tok)
+		}
+		if err != nil || tok.Text == "" {
+			return tokens, err
+		}
+	}
+}
+
+// Lexer returns the tokens of a text one by one.
+type Lexer struct {
+	text string
+	pos  int
+}
+
+// NewLexer returns a Lexer for the text.
+func NewLexer(text string) *Lexer {
+	return &Lexer{text: text}
+}
+
+// Next returns the next token, a Token with empty Text at the end of the text.
+func (l *Lexer) Next() (Token, error) {
+	tok := Token{Pos: l.pos}
+	n, err := l.scan(&tok)
+	tok.Text = l.text[l.pos : l.pos+n]
+	l.pos += n
+	if err != nil {
+		return tok, errors.Wrapf(err, "%s at %d", tok.Type, tok.Pos)
+	}
+	return tok, nil
+}
+
+// scan sets the type of the token at l.pos and returns its length.
+func (l *Lexer) scan(tok *Token) (int, error) {
+	s := l.text[l.pos:]
+	if s == "" {
+		return 0, nil
+	}
+	c, size := utf8.DecodeRuneInString(s)
+	switch {
+	case unicode.IsSpace(c):
+		tok.Type = Space
+		n := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsSpace(r) })
+		if n < 0 {
+			n = len(s)
+		}
+		return n, nil
+
+	case strings.HasPrefix(s, "--"):
+		tok.Type = Comment
+		if strings.HasPrefix(s, "--+") {
+			tok.Type = Hint
+		}
+		n := strings.IndexByte(s, '\n')
+		if n < 0 {
+			n = len(s)
+		}
+		return n, nil
+
+	case strings.HasPrefix(s, "/*"):
+		tok.Type = Comment
+		if strings.HasPrefix(s, "/*+") {
+			tok.Type = Hint
+		}
+		n := strings.Index(s[2:], "*/")
+		if n < 0 {
+			return len(s), ErrUnterminated
+		}
+		return n + 4, nil
+
+	case c == '\'':
+		tok.Type = String
+		return quoted(s, 0)
+
+	case c == '"':
+		tok.Type = QuotedIdent
+		n := strings.IndexByte(s[1:], '"')
+		if n < 0 {
+			return len(s), ErrUnterminated
+		}
+		return n + 2, nil
+
+	case '0' <= c && c <= '9' || c == '.' && len(s) > 1 && '0' <= s[1] && s[1] <= '9':
+		tok.Type = Number
+		return number(s), nil
+
+	case c == ':':
+		if len(s) > 1 {
+			if s[1] == '"' {
+				tok.Type = Bind
+				n := strings.IndexByte(s[2:], '"')
+				if n < 0 {
+					return len(s), ErrUnterminated
+				}
+				return n + 3, nil
+			}
+			if r, _ := utf8.DecodeRuneInString(s[1:]); isIdentStart(r) || '0' <= r && r <= '9' {
+				tok.Type = Bind
+				return 1 + identLen(s[1:]), nil
+			}
+		}
+		tok.Type = Operator
+		return operator(s), nil
+
+	case isIdentStart(c):
+		// N'text', Q'[text]' and NQ'[text]' literals
+		prefix := 0
+		if c == 'n' || c == 'N' {
+			prefix = 1
+		}
+		if len(s) > prefix && (s[prefix] == 'q' || s[prefix] == 'Q') {
+			prefix++
+		}
+		if prefix > 0 && len(s) > prefix && s[prefix] == '\'' {
+			tok.Type = String
+			if s[prefix-1] == 'q' || s[prefix-1] == 'Q' {
+				return qQuoted(s, prefix)
+			}
+			return quoted(s, prefix)
+		}
+		tok.Type = Ident
+		return size + identLen(s[size:]), nil
+	}
+
+	tok.Type = Operator
+	return operator(s), nil
+}
+
+func isIdentStart(r rune) bool {
+	return unicode.IsLetter(r)
+}
+
+// identLen returns the length of the identifier characters at the start of s.
+func identLen(s string) int {
+	n := strings.IndexFunc(s, func(r rune) bool {
+		return !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '$' || r == '#')
+	})
+	if n < 0 {
+		return len(s)
+	}
+	return n
+}
+
+// quoted returns the length of the quoted literal starting at s[start].
+func quoted(s string, start int) (int, error) {
+	for i := start + 1; i < len(s); i++ {
+		if s[i] != '\'' {
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '\'' {
+			i++
+			continue
+		}
+		return i + 1, nil
+	}
+	return len(s), ErrUnterminated
+}
+
+// qQuoted returns the length of the q'<delim>...<delim>' literal with
+// the quote at s[start].
+func qQuoted(s string, start int) (int, error) {
+	open, size := utf8.DecodeRuneInString(s[start+1:])
+	if size == 0 || unicode.IsSpace(open) {
+		return len(s), ErrUnterminated
+	}
+	closing := open
+	switch open {
+	case '[':
+		closing = ']'
+	case '{':
+		closing = '}'
+	case '(':
+		closing = ')'
+	case '<':
+		closing = '>'
+	}
+	body := start + 1 + size
+	n := strings.Index(s[body:], string(closing)+"'")
+	if n < 0 {
+		return len(s), ErrUnterminated
+	}
+	return body + n + utf8.RuneLen(closing) + 1, nil
+}
+
+// number returns the length of the numeric literal at the start of s.
+func number(s string) int {
+	i := 0
+	digits := func() {
+		for i < len(s) && '0' <= s[i] && s[i] <= '9' {
+			i++
+		}
+	}
+	digits()
+	// 1..10 is a range, not 1. followed by .10
+	if i < len(s) && s[i] == '.' && !strings.HasPrefix(s[i:], "..") {
+		i++
+		digits()
+	}
+	if i+1 < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if s[j] == '+' || s[j] == '-' {
+			j++
+		}
+		if j < len(s) && '0' <= s[j] && s[j] <= '9' {
+			i = j
+			digits()
+		}
+	}
+	if i < len(s) && strings.IndexByte("fFdD", s[i]) >= 0 {
+		i++
+	}
+	return i
+}
+
+// operators of more than one character
+var operators = []string{
+	":=", "=>", "||", "**", "..", "<<", ">>", "<>", "!=", "^=", "~=", "<=", ">=",
+}
+
+// operator returns the length of the operator at the start of s.
+func operator(s string) int {
+	for _, op := range operators {
+		if strings.HasPrefix(s, op) {
+			return len(op)
+		}
+	}
+	_, size := utf8.DecodeRuneInString(s)
+	return size
+}
@@ -0,0 +1,136 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package lex
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestTokenize(t *testing.T) {
+	for i, tc := range []struct {
+		text string
+		want []TokenType
+	}{
+		{"SELECT 1 FROM dual", []TokenType{Ident, Space, Number, Space, Ident, Space, Ident}},
+		{"a.b:=:x", []TokenType{Ident, Operator, Ident, Operator, Bind}},
+		{"'it''s' N'x' nq'{a'b}' q'!x!'", []TokenType{String, Space, String, Space, String, Space, String}},
+		{`"Col ':x'" :"Bind"`, []TokenType{QuotedIdent, Space, Bind}},
+		{"/*+ INDEX(t) */ /* :x ' /* */ --+ hint\n-- :y", []TokenType{Hint, Space, Comment, Space, Hint, Space, Comment}},
+		{"1.5e-3 .5 2f 1..10", []TokenType{Number, Space, Number, Space, Number, Space, Number, Operator, Number}},
+		{"x <> :1||:Ab$#", []TokenType{Ident, Space, Operator, Space, Bind, Operator, Bind}},
+		{"dátum := ? ;", []TokenType{Ident, Space, Operator, Space, Operator, Space, Operator}},
+	} {
+		tokens, err := Tokenize(tc.text)
+		if err != nil {
+			t.Errorf("%d. %q: %v", i, tc.text, err)
+			continue
+		}
+		got := make([]TokenType, len(tokens))
+		var buf bytes.Buffer
+		for j, tok := range tokens {
+			got[j] = tok.Type
+			if tc.text[tok.Pos:tok.Pos+len(tok.Text)] != tok.Text {
+				t.Errorf("%d. %q at %d: wrong position", i, tok.Text, tok.Pos)
+			}
+			buf.WriteString(tok.Text)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%d. %q: got %v, wanted %v (%v)", i, tc.text, got, tc.want, tokens)
+		}
+		if buf.String() != tc.text {
+			t.Errorf("%d. got %q, wanted %q", i, buf.String(), tc.text)
+		}
+	}
+}
+
+func TestTokenizeUnterminated(t *testing.T) {
+	for _, text := range []string{
+		"SELECT 'x FROM dual",
+		`SELECT "x FROM dual`,
+		"SELECT q'[x]x' FROM dual",
+		"SELECT 1 /* x",
+		`:"x`,
+	} {
+		if _, err := Tokenize(text); errors.Cause(err) != ErrUnterminated {
+			t.Errorf("%q: got %v, wanted %v", text, err, ErrUnterminated)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	for i, tc := range []struct {
+		text       string
+		kind       Kind
+		unit       bool
+		terminated bool
+		binds      []string
+	}{
+		{"", Unknown, false, false, nil},
+		{"SELECT :a, ':b', q'[:c]' FROM t WHERE x = :A -- :d", Query, false, false, []string{"A"}},
+		{"  /* c */ (SELECT 1 FROM dual) UNION (SELECT :1 FROM dual)", Query, false, false, []string{"1"}},
+		{"with x AS (SELECT 1 FROM dual) SELECT * FROM x;", Query, false, true, nil},
+		{`INSERT INTO t VALUES (:1, :"MixedCase", :2)`, DML, false, false, []string{"1", "MixedCase", "2"}},
+		{"merge INTO t USING s ON (t.id = s.id)", DML, false, false, nil},
+		{"BEGIN :x := 'a:b'; p(:y, :x); END;", PLSQL, false, true, []string{"X", "Y"}},
+		{"DECLARE\n  v NUMBER;\nBEGIN\n  NULL;\nEND;\n/\n", PLSQL, false, true, nil},
+		{"<<lbl>> BEGIN NULL; END;", PLSQL, false, true, nil},
+		{"CALL p(:1)", Call, false, false, []string{"1"}},
+		{"CREATE TABLE t (x NUMBER)", DDL, false, false, nil},
+		{"create or replace editionable trigger trg BEFORE INSERT ON t FOR EACH ROW BEGIN :new.x := 1; END;", DDL, true, true, nil},
+		{"CREATE OR REPLACE PACKAGE BODY p AS END;", DDL, true, true, nil},
+		{"SELECT 10 / 2 FROM dual", Query, false, false, nil},
+		{"SELECT 10\n/ 2 FROM dual\n/", Query, false, true, nil},
+		{"COMMIT", Other, false, false, nil},
+	} {
+		st, err := Parse(tc.text)
+		if err != nil {
+			t.Errorf("%d. %q: %v", i, tc.text, err)
+			continue
+		}
+		if st.Kind != tc.kind {
+			t.Errorf("%d. %q: got kind %s, wanted %s", i, tc.text, st.Kind, tc.kind)
+		}
+		if st.PLSQLUnit != tc.unit {
+			t.Errorf("%d. %q: got PLSQLUnit %t, wanted %t", i, tc.text, st.PLSQLUnit, tc.unit)
+		}
+		if st.Terminated != tc.terminated {
+			t.Errorf("%d. %q: got Terminated %t, wanted %t", i, tc.text, st.Terminated, tc.terminated)
+		}
+		if got := st.BindNames(); !reflect.DeepEqual(got, tc.binds) {
+			t.Errorf("%d. %q: got binds %q, wanted %q", i, tc.text, got, tc.binds)
+		}
+	}
+}
+
+func TestNumberPlaceholders(t *testing.T) {
+	for i, tc := range []struct {
+		text, want string
+		args       []int
+		err        error
+	}{
+		{"SELECT * FROM t WHERE a = ? AND b IN (?, ?)", "SELECT * FROM t WHERE a = :1 AND b IN (:2, :3)", nil, nil},
+		{"UPDATE t SET a = $1 WHERE b = $2", "UPDATE t SET a = :1 WHERE b = :2", nil, nil},
+		{"UPDATE t SET a = $2 WHERE b = $1 OR c = $2", "UPDATE t SET a = :1 WHERE b = :2 OR c = :3", []int{2, 1, 2}, nil},
+		{"SELECT '?', q'[$1 ?]', \"?\" /* ? */, ? -- $1\nFROM v$session", "SELECT '?', q'[$1 ?]', \"?\" /* ? */, :1 -- $1\nFROM v$session", nil, nil},
+		{"SELECT :a FROM dual", "SELECT :a FROM dual", nil, nil},
+		{"SELECT ?, $1 FROM dual", "SELECT ?, $1 FROM dual", nil, ErrMixedPlaceholders},
+		{"SELECT ? FROM 'x", "SELECT ? FROM 'x", nil, ErrUnterminated},
+	} {
+		got, args, err := NumberPlaceholders(tc.text)
+		if errors.Cause(err) != tc.err {
+			t.Errorf("%d. %q: got error %v, wanted %v", i, tc.text, err, tc.err)
+		}
+		if got != tc.want {
+			t.Errorf("%d. got %q, wanted %q", i, got, tc.want)
+		}
+		if !reflect.DeepEqual(args, tc.args) {
+			t.Errorf("%d. got args %v, wanted %v", i, args, tc.args)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package lex
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// ErrMixedPlaceholders is returned by NumberPlaceholders for text with both
+// ? and $n placeholders.
+var ErrMixedPlaceholders = errors.New("both ? and $n placeholders")
+
+// NumberPlaceholders rewrites the ? and $n placeholders of other databases to
+// Oracle's :1..:n, numbered in the order of appearance.
+//
+// Oracle binds the arguments by the position of the placeholders, so for
+// $n placeholders that are repeated or out of order, args returns the n of
+// each placeholder: the kth argument to bind is the args[k-1]th given.
+// args is nil if the arguments are to be bound as given.
+//
+// String literals, quoted identifiers and comments are left untouched, and so
+// are :name placeholders. Text without ? or $n placeholders is returned as is.
+func NumberPlaceholders(text string) (sql string, args []int, err error) {
+	tokens, err := Tokenize(text)
+	if err != nil {
+		return text, nil, err
+	}
+	var buf bytes.Buffer
+	var questions int
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case tok.Type == Operator && tok.Text == "?":
+			questions++
+			buf.WriteString(":" + strconv.Itoa(questions+len(args)))
+			continue
+		case tok.Type == Operator && tok.Text == "$" && i+1 < len(tokens):
+			if next := tokens[i+1]; next.Type == Number && isDigits(next.Text) {
+				n, err := strconv.Atoi(next.Text)
+				if err != nil {
+					return text, nil, errors.Wrap(err, next.Text)
+				}
+				args = append(args, n)
+				buf.WriteString(":" + strconv.Itoa(questions+len(args)))
+				i++
+				continue
+			}
+		}
+		buf.WriteString(tok.Text)
+	}
+	if questions > 0 && len(args) > 0 {
+		return text, nil, errors.Wrap(ErrMixedPlaceholders, text)
+	}
+	if questions == 0 && len(args) == 0 {
+		return text, nil, nil
+	}
+	for k, n := range args {
+		if n != k+1 {
+			return buf.String(), args, nil
+		}
+	}
+	return buf.String(), nil, nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,212 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package lex
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind is the kind of a statement.
+type Kind uint8
+
+const (
+	Unknown Kind = iota // empty or unrecognized text
+	Query               // SELECT, WITH
+	DML                 // INSERT, UPDATE, DELETE, MERGE, LOCK TABLE
+	DDL                 // CREATE, ALTER, DROP, TRUNCATE, GRANT, ...
+	PLSQL               // anonymous BEGIN or DECLARE block
+	Call                // CALL
+	Other               // COMMIT, ROLLBACK, SAVEPOINT, SET, EXPLAIN PLAN, ...
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Unknown:
+		return "Unknown"
+	case Query:
+		return "Query"
+	case DML:
+		return "DML"
+	case DDL:
+		return "DDL"
+	case PLSQL:
+		return "PLSQL"
+	case Call:
+		return "Call"
+	case Other:
+		return "Other"
+	}
+	return fmt.Sprintf("Kind(%d)", k)
+}
+
+var kinds = map[string]Kind{
+	"SELECT": Query,
+	"WITH":   Query,
+
+	"INSERT": DML,
+	"UPDATE": DML,
+	"DELETE": DML,
+	"MERGE":  DML,
+	"LOCK":   DML,
+
+	"CREATE":       DDL,
+	"ALTER":        DDL,
+	"DROP":         DDL,
+	"TRUNCATE":     DDL,
+	"RENAME":       DDL,
+	"GRANT":        DDL,
+	"REVOKE":       DDL,
+	"COMMENT":      DDL,
+	"ANALYZE":      DDL,
+	"AUDIT":        DDL,
+	"NOAUDIT":      DDL,
+	"PURGE":        DDL,
+	"FLASHBACK":    DDL,
+	"ASSOCIATE":    DDL,
+	"DISASSOCIATE": DDL,
+
+	"BEGIN":   PLSQL,
+	"DECLARE": PLSQL,
+
+	"CALL": Call,
+
+	"COMMIT":    Other,
+	"ROLLBACK":  Other,
+	"SAVEPOINT": Other,
+	"SET":       Other,
+	"EXPLAIN":   Other,
+}
+
+// plsqlUnits are the CREATE statements of stored PL/SQL units, which must
+// keep their terminating semicolon.
+var plsqlUnits = map[string]bool{
+	"PROCEDURE": true,
+	"FUNCTION":  true,
+	"PACKAGE":   true,
+	"TRIGGER":   true,
+	"TYPE":      true,
+	"LIBRARY":   true,
+}
+
+// Statement describes a SQL or PL/SQL statement.
+type Statement struct {
+	Kind Kind
+	// Keyword is the first keyword, upper-cased: SELECT, INSERT, BEGIN...
+	Keyword string
+	// PLSQLUnit is true for CREATE [OR REPLACE] PROCEDURE, FUNCTION,
+	// PACKAGE, TRIGGER, TYPE and LIBRARY statements, whose text is PL/SQL.
+	PLSQLUnit bool
+	// Terminated is true if the statement ends with a semicolon or
+	// a SQL*Plus style slash on its own line.
+	//
+	// OCI rejects a terminated SQL statement with ORA-00911, while
+	// PL/SQL blocks and units must end with a semicolon.
+	Terminated bool
+	// Binds are the Bind tokens, in the order of appearance.
+	// DDL statements have no binds: :NEW and :OLD in a trigger body
+	// are not placeholders.
+	Binds []Token
+	// Tokens are all the tokens of the text.
+	Tokens []Token
+}
+
+// BindNames returns the distinct bind names, in the order of their first
+// appearance, as Token.BindName returns them.
+func (st Statement) BindNames() []string {
+	var names []string
+	seen := make(map[string]bool, len(st.Binds))
+	for _, tok := range st.Binds {
+		name := tok.BindName()
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Parse tokenizes and classifies the statement text.
+func Parse(text string) (Statement, error) {
+	var st Statement
+	var err error
+	if st.Tokens, err = Tokenize(text); err != nil {
+		return st, err
+	}
+	var sig []Token
+	for _, tok := range st.Tokens {
+		if tok.IsSignificant() {
+			sig = append(sig, tok)
+		}
+	}
+
+	// skip the parentheses of (SELECT ...) UNION (SELECT ...)
+	i := 0
+	for i < len(sig) && sig[i].Text == "(" {
+		i++
+	}
+	if i < len(sig) && sig[i].Type == Ident {
+		st.Keyword = strings.ToUpper(sig[i].Text)
+		st.Kind = kinds[st.Keyword]
+	} else if i < len(sig) && sig[i].Text == "<<" {
+		// a labeled block
+		st.Kind, st.Keyword = PLSQL, "<<"
+	}
+	if st.Keyword == "CREATE" {
+		j := i + 1
+		for j < len(sig) && isCreateModifier(sig[j].Text) {
+			j++
+		}
+		if j < len(sig) {
+			st.PLSQLUnit = plsqlUnits[strings.ToUpper(sig[j].Text)]
+		}
+	}
+
+	if n := len(sig); n > 0 {
+		last := sig[n-1]
+		st.Terminated = last.Text == ";" || last.Text == "/" && isAloneOnLine(text, last)
+	}
+
+	if st.Kind != DDL {
+		for _, tok := range sig {
+			if tok.Type == Bind {
+				st.Binds = append(st.Binds, tok)
+			}
+		}
+	}
+	return st, nil
+}
+
+// Binds returns the distinct bind names of the statement text, in the order
+// of their first appearance.
+func Binds(text string) ([]string, error) {
+	st, err := Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return st.BindNames(), nil
+}
+
+// isCreateModifier reports whether word may come between CREATE and the
+// type of the created object.
+func isCreateModifier(word string) bool {
+	switch strings.ToUpper(word) {
+	case "OR", "REPLACE", "EDITIONABLE", "NONEDITIONABLE":
+		return true
+	}
+	return false
+}
+
+// isAloneOnLine reports whether only spaces surround the token on its line.
+func isAloneOnLine(text string, tok Token) bool {
+	start := strings.LastIndexByte(text[:tok.Pos], '\n') + 1
+	end := strings.IndexByte(text[tok.Pos:], '\n')
+	if end < 0 {
+		end = len(text)
+	} else {
+		end += tok.Pos
+	}
+	return strings.TrimSpace(text[start:end]) == tok.Text
+}
@@ -0,0 +1,485 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+	"unsafe"
+)
+
+var _ = io.WriterAt((*LobLocator)(nil))
+var _ = io.ReadWriteSeeker((*LobLocator)(nil))
+
+// LobLocator is a handle to a BLOB or CLOB in the database, for random access
+// reads and in-place writes, without copying the whole LOB.
+//
+// Fetch it with the Loc GoColumnType (use SELECT ... FOR UPDATE to write it),
+// or bind a *LobLocator as an output parameter, e.g.
+//
+//	INSERT INTO docs (id, body) VALUES (:1, EMPTY_BLOB()) RETURNING body INTO :2
+//
+// Offsets and sizes are in bytes for BLOBs, and in characters for CLOBs, as in
+// Oracle. ReadAt, the io.ReaderAt of BLOBs, fails on CLOBs: read them with
+// ReadCharsAt, which reads whole characters only, or with Read.
+//
+// Each method is a round trip to the server, limited by StmtCfg.CallTimeout.
+// Group many writes between Open and Close, so that the indexes and triggers
+// on the LOB are updated only once, at Close.
+//
+// Release the locator with Free, before closing the session.
+type LobLocator struct {
+	// C is true for a CLOB, false for a BLOB. It is set by fetches and binds,
+	// and chooses the type of a bound LobLocator without locator.
+	C bool
+
+	mu      sync.Mutex
+	ses     *Ses
+	lob     *C.OCILobLocator
+	timeout time.Duration
+	opened  bool
+	pos     int64
+}
+
+// IsNull reports whether the LobLocator has no locator: it is a NULL column
+// or output, or it is freed.
+func (loc *LobLocator) IsNull() bool {
+	if loc == nil {
+		return true
+	}
+	loc.mu.Lock()
+	defer loc.mu.Unlock()
+	return loc.lob == nil
+}
+
+// Size returns the length of the LOB: bytes for BLOBs, characters for CLOBs.
+func (loc *LobLocator) Size() (size int64, err error) {
+	err = loc.do(func(svc *C.OCISvcCtx, env *Env, lob *C.OCILobLocator) error {
+		var length C.oraub8
+		if C.OCILobGetLength2(
+			svc,        //OCISvcCtx          *svchp,
+			env.ocierr, //OCIError           *errhp,
+			lob,        //OCILobLocator      *locp,
+			&length,    //oraub8             *lenp );
+		) == C.OCI_ERROR {
+			return env.ociError("OCILobGetLength2")
+		}
+		size = int64(length)
+		return nil
+	})
+	return size, err
+}
+
+// ChunkSize returns the usable chunk size of the LOB: bytes for BLOBs,
+// characters for CLOBs. Reads and writes are the most efficient in multiples
+// of it.
+func (loc *LobLocator) ChunkSize() (size int, err error) {
+	err = loc.do(func(svc *C.OCISvcCtx, env *Env, lob *C.OCILobLocator) error {
+		var chunk C.ub4
+		if C.OCILobGetChunkSize(
+			svc,        //OCISvcCtx          *svchp,
+			env.ocierr, //OCIError           *errhp,
+			lob,        //OCILobLocator      *locp,
+			&chunk,     //ub4                *chunk_size );
+		) == C.OCI_ERROR {
+			return env.ociError("OCILobGetChunkSize")
+		}
+		size = int(chunk)
+		return nil
+	})
+	return size, err
+}
+
+// Open opens the LOB for a group of operations, which is ended by Close.
+//
+// Without Open, each write is a separate operation, updating the indexes
+// and triggers on the LOB.
+func (loc *LobLocator) Open(readOnly bool) error {
+	mode := C.ub1(C.OCI_LOB_READWRITE)
+	if readOnly {
+		mode = C.OCI_LOB_READONLY
+	}
+	return loc.do(func(svc *C.OCISvcCtx, env *Env, lob *C.OCILobLocator) error {
+		if loc.opened {
+			return er("LobLocator is already opened.")
+		}
+		if C.OCILobOpen(
+			svc,        //OCISvcCtx          *svchp,
+			env.ocierr, //OCIError           *errhp,
+			lob,        //OCILobLocator      *locp,
+			mode,       //ub1                mode );
+		) == C.OCI_ERROR {
+			return env.ociError("OCILobOpen")
+		}
+		loc.opened = true
+		return nil
+	})
+}
+
+// Close closes the LOB opened by Open. The locator remains usable.
+//
+// Close is a no-op if the LOB is not opened.
+func (loc *LobLocator) Close() error {
+	if loc.IsNull() {
+		return nil
+	}
+	return loc.do(func(svc *C.OCISvcCtx, env *Env, lob *C.OCILobLocator) error {
+		return loc.closeLob(svc, env, lob)
+	})
+}
+
+func (loc *LobLocator) closeLob(svc *C.OCISvcCtx, env *Env, lob *C.OCILobLocator) error {
+	if !loc.opened {
+		return nil
+	}
+	loc.opened = false
+	if C.OCILobClose(
+		svc,        //OCISvcCtx          *svchp,
+		env.ocierr, //OCIError           *errhp,
+		lob,        //OCILobLocator      *locp,
+	) == C.OCI_ERROR {
+		return env.ociError("OCILobClose")
+	}
+	return nil
+}
+
+// Free closes the LOB if opened, frees a temporary LOB, and releases the
+// locator.
+func (loc *LobLocator) Free() error {
+	if loc.IsNull() {
+		return nil
+	}
+	err := loc.do(func(svc *C.OCISvcCtx, env *Env, lob *C.OCILobLocator) error {
+		err := loc.closeLob(svc, env, lob)
+		var isTemp C.boolean
+		if C.OCILobIsTemporary(
+			env.ocienv, //OCIEnv            *envhp,
+			env.ocierr, //OCIError          *errhp,
+			lob,        //OCILobLocator     *locp,
+			&isTemp,    //boolean           *is_temporary );
+		) == C.OCI_SUCCESS && isTemp == C.TRUE {
+			if C.OCILobFreeTemporary(
+				svc,        //OCISvcCtx          *svchp,
+				env.ocierr, //OCIError           *errhp,
+				lob,        //OCILobLocator      *locp,
+			) == C.OCI_ERROR && err == nil {
+				err = env.ociError("OCILobFreeTemporary")
+			}
+		}
+		return err
+	})
+	loc.mu.Lock()
+	if loc.lob != nil {
+		C.OCIDescriptorFree(
+			unsafe.Pointer(loc.lob), //void     *descp,
+			C.OCI_DTYPE_LOB)         //ub4      type );
+		loc.lob, loc.ses = nil, nil
+	}
+	loc.mu.Unlock()
+	return err
+}
+
+// ReadAt reads len(p) bytes of a BLOB into p, starting at the byte off, as
+// io.ReaderAt. It fails on a CLOB, whose offsets are in characters: use
+// ReadCharsAt.
+func (loc *LobLocator) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errF("negative offset %d", off)
+	}
+	err = loc.do(func(svc *C.OCISvcCtx, env *Env, lob *C.OCILobLocator) error {
+		if loc.C {
+			return er("ReadAt cannot read a CLOB; use ReadCharsAt.")
+		}
+		var err error
+		n, _, err = loc.readAt(svc, env, lob, p, off)
+		return err
+	})
+	return n, err
+}
+
+// ReadCharsAt reads whole characters of a CLOB into p, starting at the
+// character off, and returns the number of bytes and of characters read.
+// It reads less than len(p) bytes without error when the next character
+// does not fit into p, and returns io.EOF at the end of the CLOB.
+//
+// For a BLOB, it reads bytes, as ReadAt.
+func (loc *LobLocator) ReadCharsAt(p []byte, off int64) (n int, chars int64, err error) {
+	if off < 0 {
+		return 0, 0, errF("negative offset %d", off)
+	}
+	err = loc.do(func(svc *C.OCISvcCtx, env *Env, lob *C.OCILobLocator) error {
+		var err error
+		n, chars, err = loc.readAt(svc, env, lob, p, off)
+		return err
+	})
+	return n, chars, err
+}
+
+// Read reads into p from the current position, which it advances.
+func (loc *LobLocator) Read(p []byte) (n int, err error) {
+	err = loc.do(func(svc *C.OCISvcCtx, env *Env, lob *C.OCILobLocator) error {
+		var amt int64
+		var err error
+		n, amt, err = loc.readAt(svc, env, lob, p, loc.pos)
+		loc.pos += amt
+		return err
+	})
+	return n, err
+}
+
+// readAt reads into p, starting at off, and returns the number of bytes read,
+// and the amount read: bytes for BLOBs, characters for CLOBs.
+func (loc *LobLocator) readAt(svc *C.OCISvcCtx, env *Env, lob *C.OCILobLocator, p []byte, off int64) (n int, amt int64, err error) {
+	if len(p) == 0 {
+		return 0, 0, nil
+	}
+	// with both amounts being 0, OCI would read the whole LOB
+	byteAmt, charAmt := C.oraub8(len(p)), C.oraub8(0)
+	if loc.C {
+		// at most 4 bytes per character in AL32UTF8
+		if byteAmt, charAmt = 0, C.oraub8(len(p)/4); charAmt == 0 {
+			return 0, 0, errF("buffer of %d bytes is too small for a character", len(p))
+		}
+	}
+	want := byteAmt + charAmt
+	r := C.OCILobRead2(
+		svc,                                     //OCISvcCtx          *svchp,
+		env.ocierr,                              //OCIError           *errhp,
+		lob,                                     //OCILobLocator      *locp,
+		&byteAmt,                                //oraub8             *byteAmtp,
+		&charAmt,                                //oraub8             *char_amtp,
+		C.oraub8(off)+1,                         //oraub8             offset, offset is 1-based
+		unsafe.Pointer(&p[0]),                   //void               *bufp,
+		C.oraub8(len(p)),                        //oraub8             bufl,
+		C.OCI_ONE_PIECE,                         //ub1                piece,
+		nil,                                     //void               *ctxp,
+		nil,                                     //OCICallbackLobRead2 (cbfp)
+		C.ub2(atomic.LoadUint32(&csIDAl32UTF8)), //ub2                csid,
+		lobCharsetForm(env, lob),                //ub1                csfrm );
+	)
+	switch r {
+	case C.OCI_ERROR:
+		return 0, 0, env.ociError("OCILobRead2")
+	case C.OCI_INVALID_HANDLE:
+		return 0, 0, fmt.Errorf("Invalid handle %v", lob)
+	case C.OCI_NO_DATA:
+		return 0, 0, io.EOF
+	}
+	n, amt = int(byteAmt), int64(byteAmt)
+	if loc.C {
+		amt = int64(utf8.RuneCount(p[:n]))
+		if C.oraub8(amt) < want {
+			err = io.EOF
+		}
+	} else if n < len(p) {
+		err = io.EOF
+	}
+	return n, amt, err
+}
+
+// WriteAt writes p into the LOB, starting at off. A gap after the end of
+// the LOB is filled with zero bytes (BLOB) or spaces (CLOB).
+func (loc *LobLocator) WriteAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errF("negative offset %d", off)
+	}
+	err = loc.do(func(svc *C.OCISvcCtx, env *Env, lob *C.OCILobLocator) error {
+		var err error
+		n, _, err = loc.writeAt(svc, env, lob, p, off)
+		return err
+	})
+	return n, err
+}
+
+// Write writes p at the current position, which it advances.
+func (loc *LobLocator) Write(p []byte) (n int, err error) {
+	err = loc.do(func(svc *C.OCISvcCtx, env *Env, lob *C.OCILobLocator) error {
+		var amt int64
+		var err error
+		n, amt, err = loc.writeAt(svc, env, lob, p, loc.pos)
+		loc.pos += amt
+		return err
+	})
+	return n, err
+}
+
+// writeAt writes p starting at off, and returns the number of bytes written,
+// and the amount written: bytes for BLOBs, characters for CLOBs.
+func (loc *LobLocator) writeAt(svc *C.OCISvcCtx, env *Env, lob *C.OCILobLocator, p []byte, off int64) (n int, amt int64, err error) {
+	if len(p) == 0 {
+		return 0, 0, nil
+	}
+	byteAmt, charAmt := C.oraub8(len(p)), C.oraub8(0)
+	if C.OCILobWrite2(
+		svc,                                     //OCISvcCtx          *svchp,
+		env.ocierr,                              //OCIError           *errhp,
+		lob,                                     //OCILobLocator      *locp,
+		&byteAmt,                                //oraub8             *byte_amtp,
+		&charAmt,                                //oraub8             *char_amtp,
+		C.oraub8(off)+1,                         //oraub8             offset, offset is 1-based
+		unsafe.Pointer(&p[0]),                   //void               *bufp,
+		C.oraub8(len(p)),                        //oraub8             buflen,
+		C.OCI_ONE_PIECE,                         //ub1                piece,
+		nil,                                     //void               *ctxp,
+		nil,                                     //OCICallbackLobWrite2 (cbfp)
+		C.ub2(atomic.LoadUint32(&csIDAl32UTF8)), //ub2                csid,
+		lobCharsetForm(env, lob),                //ub1                csfrm );
+	) == C.OCI_ERROR {
+		return 0, 0, env.ociError("OCILobWrite2")
+	}
+	n = int(byteAmt)
+	return n, loc.amount(p[:n]), nil
+}
+
+// Append writes p at the end of the LOB.
+func (loc *LobLocator) Append(p []byte) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	err = loc.do(func(svc *C.OCISvcCtx, env *Env, lob *C.OCILobLocator) error {
+		byteAmt, charAmt := C.oraub8(len(p)), C.oraub8(0)
+		if C.OCILobWriteAppend2(
+			svc,                                     //OCISvcCtx          *svchp,
+			env.ocierr,                              //OCIError           *errhp,
+			lob,                                     //OCILobLocator      *locp,
+			&byteAmt,                                //oraub8             *byte_amtp,
+			&charAmt,                                //oraub8             *char_amtp,
+			unsafe.Pointer(&p[0]),                   //void               *bufp,
+			C.oraub8(len(p)),                        //oraub8             bufl,
+			C.OCI_ONE_PIECE,                         //ub1                piece,
+			nil,                                     //void               *ctxp,
+			nil,                                     //OCICallbackLobWrite2 (cbfp)
+			C.ub2(atomic.LoadUint32(&csIDAl32UTF8)), //ub2                csid,
+			lobCharsetForm(env, lob),                //ub1                csfrm );
+		) == C.OCI_ERROR {
+			return env.ociError("OCILobWriteAppend2")
+		}
+		n = int(byteAmt)
+		return nil
+	})
+	return n, err
+}
+
+// Seek sets the position of the next Read or Write, in bytes for BLOBs,
+// in characters for CLOBs. io.SeekEnd is relative to Size.
+func (loc *LobLocator) Seek(offset int64, whence int) (int64, error) {
+	var base int64
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		loc.mu.Lock()
+		base = loc.pos
+		loc.mu.Unlock()
+	case io.SeekEnd:
+		size, err := loc.Size()
+		if err != nil {
+			return 0, err
+		}
+		base = size
+	default:
+		return 0, errF("invalid whence %d", whence)
+	}
+	if base+offset < 0 {
+		return 0, errF("negative position %d", base+offset)
+	}
+	loc.mu.Lock()
+	loc.pos = base + offset
+	loc.mu.Unlock()
+	return base + offset, nil
+}
+
+// Trim removes n bytes (BLOB) or characters (CLOB) from the end of the LOB.
+func (loc *LobLocator) Trim(n int64) error {
+	if n < 0 {
+		return errF("negative amount %d", n)
+	}
+	size, err := loc.Size()
+	if err != nil {
+		return err
+	}
+	if n > size {
+		n = size
+	}
+	return loc.Truncate(size - n)
+}
+
+// Truncate changes the size of the LOB, like os.File.Truncate.
+//
+// It grows the LOB by padding it with zero bytes (BLOB) or spaces (CLOB).
+func (loc *LobLocator) Truncate(size int64) error {
+	if size < 0 {
+		return errF("negative size %d", size)
+	}
+	current, err := loc.Size()
+	if err != nil || size == current {
+		return err
+	}
+	if size > current {
+		pad := []byte{0}
+		if loc.C {
+			pad[0] = ' '
+		}
+		_, err = loc.WriteAt(pad, size-1)
+		return err
+	}
+	return loc.do(func(svc *C.OCISvcCtx, env *Env, lob *C.OCILobLocator) error {
+		if C.OCILobTrim2(
+			svc,            //OCISvcCtx          *svchp,
+			env.ocierr,     //OCIError           *errhp,
+			lob,            //OCILobLocator      *locp,
+			C.oraub8(size), //oraub8             newlen );
+		) == C.OCI_ERROR {
+			return env.ociError("OCILobTrim2")
+		}
+		return nil
+	})
+}
+
+// amount returns the length of p in the units of the LOB.
+func (loc *LobLocator) amount(p []byte) int64 {
+	if loc.C {
+		return int64(utf8.RuneCount(p))
+	}
+	return int64(len(p))
+}
+
+// do calls f with the locator locked, in a call limited by the call timeout.
+func (loc *LobLocator) do(f func(svc *C.OCISvcCtx, env *Env, lob *C.OCILobLocator) error) error {
+	if loc == nil {
+		return er("LobLocator may not be nil.")
+	}
+	loc.mu.Lock()
+	defer loc.mu.Unlock()
+	if loc.lob == nil {
+		return er("LobLocator is NULL or freed.")
+	}
+	ses := loc.ses
+	if err := ses.checkClosed(); err != nil {
+		return errE(err)
+	}
+	ses.RLock()
+	svc, env := ses.ocisvcctx, ses.srv.env
+	ses.RUnlock()
+	call, err := ses.startCall(context.Background(), loc.timeout)
+	if err != nil {
+		return err
+	}
+	return call.end(f(svc, env, loc.lob))
+}
+
+// newLobLocator returns a LobLocator owning lob.
+func newLobLocator(ses *Ses, lob *C.OCILobLocator, isClob bool, timeout time.Duration) *LobLocator {
+	return &LobLocator{C: isClob, ses: ses, lob: lob, timeout: timeout}
+}
@@ -0,0 +1,132 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+var _ = io.WriteCloser((*lobWriter)(nil))
+
+// Writer returns an io.WriteCloser that writes straight to the LOB, starting
+// at the current position of the LobLocator (see Seek).
+//
+// The writes are buffered, and sent to the server in pieces of a multiple of
+// ChunkSize, so an io.Copy or a gzip.Writer can stream any amount of data
+// into the LOB, without a temporary LOB. For CLOBs, the written bytes must be
+// UTF-8; a character split between two writes is kept till the next one.
+//
+// Unless opened already, the LOB is opened till Close, which flushes the
+// buffer. The LOB is not truncated: call Truncate(0) first to replace its
+// content.
+func (loc *LobLocator) Writer() (io.WriteCloser, error) {
+	chunk, err := loc.ChunkSize()
+	if err != nil {
+		return nil, err
+	}
+	if chunk <= 0 || chunk > lobChunkSize {
+		chunk = lobChunkSize
+	}
+	loc.mu.Lock()
+	opened := loc.opened
+	loc.mu.Unlock()
+	lw := &lobWriter{loc: loc}
+	if !opened {
+		if err = loc.Open(false); err != nil {
+			return nil, err
+		}
+		lw.opened = true
+	}
+	size := (lobChunkSize / chunk) * chunk
+	lw.arr = lobChunkPool.Get().(*[lobChunkSize]byte)
+	lw.buf = lw.arr[:0:size]
+	return lw, nil
+}
+
+type lobWriter struct {
+	loc    *LobLocator
+	arr    *[lobChunkSize]byte
+	buf    []byte
+	opened bool // the LOB is opened by the writer
+	err    error
+}
+
+// Write buffers p, sending the full buffer to the LOB.
+func (lw *lobWriter) Write(p []byte) (n int, err error) {
+	if lw.err != nil {
+		return 0, lw.err
+	}
+	for len(p) > 0 {
+		k := copy(lw.buf[len(lw.buf):cap(lw.buf)], p)
+		lw.buf = lw.buf[:len(lw.buf)+k]
+		n, p = n+k, p[k:]
+		if len(lw.buf) == cap(lw.buf) {
+			if err = lw.flush(false); err != nil {
+				lw.err = err
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// flush writes the buffer to the LOB, except the bytes of an incomplete last
+// character of a CLOB, unless final.
+func (lw *lobWriter) flush(final bool) error {
+	k := len(lw.buf)
+	if lw.loc.C && !final {
+		k = completeRunes(lw.buf)
+	}
+	for data := lw.buf[:k]; len(data) > 0; {
+		n, err := lw.loc.Write(data)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return io.ErrShortWrite
+		}
+		data = data[n:]
+	}
+	// keep the incomplete character
+	lw.buf = lw.buf[:copy(lw.buf, lw.buf[k:])]
+	return nil
+}
+
+// Close flushes the buffer, and closes the LOB if opened by Writer.
+func (lw *lobWriter) Close() error {
+	if lw.arr == nil {
+		return lw.err
+	}
+	err := lw.err
+	if err == nil {
+		err = lw.flush(true)
+	}
+	if lw.opened {
+		if closeErr := lw.loc.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	lobChunkPool.Put(lw.arr)
+	lw.arr, lw.buf = nil, nil
+	if lw.err == nil {
+		lw.err = er("LOB writer is closed.")
+	}
+	return err
+}
+
+// completeRunes returns the length of p without an incomplete UTF-8 sequence
+// at its end.
+func completeRunes(p []byte) int {
+	for i := len(p) - 1; i >= 0 && i >= len(p)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(p[i]) {
+			if !utf8.FullRune(p[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(p)
+}
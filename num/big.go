@@ -0,0 +1,188 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package num
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// BigFloatPrec is the precision of the big.Float values returned by
+// OCINum.BigFloat: enough for the 40 decimal digits of an OCINumber.
+const BigFloatPrec = 133
+
+// maxDigits is the number of decimal digits an OCINumber can hold.
+const maxDigits = 40
+
+var ErrOutOfRange = errors.New("number out of range")
+
+// BigInt returns the number truncated to an integer.
+func (num OCINum) BigInt() (*big.Int, error) {
+	r, err := num.BigRat()
+	if err != nil {
+		return nil, err
+	}
+	if r.IsInt() {
+		return new(big.Int).Set(r.Num()), nil
+	}
+	return new(big.Int).Quo(r.Num(), r.Denom()), nil
+}
+
+// BigFloat returns the number as a big.Float with BigFloatPrec precision.
+func (num OCINum) BigFloat() (*big.Float, error) {
+	f, _, err := big.ParseFloat(num.String(), 10, BigFloatPrec, big.ToNearestEven)
+	return f, errors.Wrap(err, num.String())
+}
+
+// BigRat returns the exact value of the number.
+func (num OCINum) BigRat() (*big.Rat, error) {
+	s := num.String()
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, errors.Wrap(ErrBadCharacter, s)
+	}
+	return r, nil
+}
+
+// SetBigInt sets the OCINum to x.
+//
+// Returns ErrTooLong if x has more than 40 significant digits (39 if it has
+// an odd number of digits).
+func (num *OCINum) SetBigInt(x *big.Int) error {
+	s := x.String()
+	var neg bool
+	if s[0] == '-' {
+		neg, s = true, s[1:]
+	}
+	digits := strings.TrimRight(s, "0")
+	if max := significantDigits(len(s)); len(digits) > max {
+		return errors.Wrapf(ErrTooLong, "got %d significant digits, max %d", len(digits), max)
+	}
+	return num.setDecimal(neg, digits, len(s))
+}
+
+// SetBigFloat sets the OCINum to x, rounded to 40 significant digits (39 if
+// the decimal exponent is even, as the base-100 digits need a leading zero).
+func (num *OCINum) SetBigFloat(x *big.Float) error {
+	if x.IsInf() {
+		return errors.Wrap(ErrOutOfRange, x.String())
+	}
+	return num.setScientific(scientific(x))
+}
+
+// SetBigRat sets the OCINum to x, rounded as SetBigFloat.
+func (num *OCINum) SetBigRat(x *big.Rat) error {
+	if x.IsInt() {
+		return num.SetBigInt(x.Num())
+	}
+	f := new(big.Float).SetPrec(4 * BigFloatPrec).SetRat(x)
+	return num.setScientific(scientific(f))
+}
+
+// scientific returns x in the -d.ddde±dd format of big.Float.Text: the
+// shortest exact representation, unless it has more significant digits than
+// an OCINumber can hold.
+func scientific(x *big.Float) string {
+	s := x.Text('e', -1)
+	if fits(s) {
+		return s
+	}
+	if s = x.Text('e', maxDigits-1); fits(s) {
+		return s
+	}
+	// rounding may carry into a new leading digit, changing the exponent,
+	// so one digit less always fits
+	return x.Text('e', maxDigits-2)
+}
+
+// fits reports whether the significant digits of s, in the format of
+// big.Float.Text('e'), fit into an OCINumber.
+func fits(s string) bool {
+	i := strings.IndexByte(s, 'e')
+	if i < 0 {
+		return false
+	}
+	exp, err := strconv.Atoi(s[i+1:])
+	if err != nil {
+		return false
+	}
+	digits := strings.TrimRight(strings.Replace(strings.TrimPrefix(s[:i], "-"), ".", "", 1), "0")
+	return len(digits) <= significantDigits(exp+1)
+}
+
+// significantDigits returns the number of significant digits an OCINumber
+// holds, with the decimal point exp digits after the first one (as in
+// setDecimal): the base-100 digits need a leading zero for an odd exp.
+func significantDigits(exp int) int {
+	if exp%2 != 0 {
+		return maxDigits - 1
+	}
+	return maxDigits
+}
+
+// setScientific sets the OCINum to s, in the -d.ddde±dd format of big.Float.Text.
+func (num *OCINum) setScientific(s string) error {
+	i := strings.IndexByte(s, 'e')
+	if i < 0 {
+		return errors.Wrap(ErrBadCharacter, s)
+	}
+	exp, err := strconv.Atoi(s[i+1:])
+	if err != nil {
+		return errors.Wrap(err, s)
+	}
+	s = s[:i]
+	var neg bool
+	if s[0] == '-' {
+		neg, s = true, s[1:]
+	}
+	digits := strings.TrimRight(strings.Replace(s, ".", "", 1), "0")
+	return num.setDecimal(neg, digits, exp+1)
+}
+
+// setDecimal sets the OCINum to ±0.digits * 10^exp.
+// The digits must not have leading or trailing zeros.
+func (num *OCINum) setDecimal(neg bool, digits string, exp int) error {
+	if digits == "" {
+		*num = append((*num)[:0], 128)
+		return nil
+	}
+	// align to base-100 digits
+	if exp%2 != 0 {
+		digits = "0" + digits
+		exp++
+	}
+	if len(digits)%2 != 0 {
+		digits += "0"
+	}
+	exp100 := exp/2 - 1
+	if exp100 < -65 || exp100 > 62 {
+		return errors.Wrapf(ErrOutOfRange, "0.%se%d", digits, exp)
+	}
+	if len(digits) > 2*20 {
+		return errors.Wrapf(ErrTooLong, "got %d digits", len(digits))
+	}
+	b := append((*num)[:0], byte(exp100+65))
+	for i := 0; i < len(digits); i += 2 {
+		d := 10*(digits[i]-'0') + digits[i+1] - '0'
+		if neg {
+			b = append(b, 101-d)
+		} else {
+			b = append(b, d+1)
+		}
+	}
+	if neg {
+		b[0] = ^b[0] & 0x7f
+		if len(b) < 21 {
+			b = append(b, 102)
+		}
+	} else {
+		b[0] |= 1 << 7
+	}
+	*num = b
+	return nil
+}
@@ -0,0 +1,206 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package num
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestSetBig(t *testing.T) {
+	for _, elt := range testNums {
+		if elt.await == "-0" {
+			continue
+		}
+		r, ok := new(big.Rat).SetString(elt.await)
+		if !ok {
+			t.Fatalf("bad test number %q", elt.await)
+		}
+		var got OCINum
+		if err := got.SetBigRat(r); err != nil {
+			t.Errorf("%s: SetBigRat: %v", elt.await, err)
+			continue
+		}
+		if !bytes.Equal(got, elt.num) {
+			t.Errorf("%s: SetBigRat got %v, wanted %v", elt.await, []byte(got), elt.num)
+		}
+		f, _, err := big.ParseFloat(elt.await, 10, BigFloatPrec, big.ToNearestEven)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = got.SetBigFloat(f); err != nil {
+			t.Errorf("%s: SetBigFloat: %v", elt.await, err)
+			continue
+		}
+		if !bytes.Equal(got, elt.num) {
+			t.Errorf("%s: SetBigFloat got %v, wanted %v", elt.await, []byte(got), elt.num)
+		}
+
+		back, err := OCINum(elt.num).BigRat()
+		if err != nil {
+			t.Errorf("%s: BigRat: %v", elt.await, err)
+			continue
+		}
+		if back.Cmp(r) != 0 {
+			t.Errorf("%s: BigRat got %s", elt.await, back.RatString())
+		}
+	}
+}
+
+func TestBigInt(t *testing.T) {
+	for _, s := range []string{
+		"0", "1", "-1", "100", "-12345",
+		"12345678901234567890123456789012345678",
+		"-99999999999999999999999999999999999999",
+		"1" + strings.Repeat("0", 100),
+	} {
+		x, _ := new(big.Int).SetString(s, 10)
+		var n OCINum
+		if err := n.SetBigInt(x); err != nil {
+			t.Errorf("%s: %v", s, err)
+			continue
+		}
+		if n.String() != s {
+			t.Errorf("%s: got %s", s, n.String())
+		}
+		got, err := n.BigInt()
+		if err != nil {
+			t.Errorf("%s: %v", s, err)
+			continue
+		}
+		if got.Cmp(x) != 0 {
+			t.Errorf("%s: got %s", s, got)
+		}
+	}
+
+	// truncated towards zero
+	var n OCINum
+	if err := n.SetString("-12.75"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := n.BigInt(); err != nil || got.Int64() != -12 {
+		t.Errorf("got %v (%v), wanted -12", got, err)
+	}
+
+	x, _ := new(big.Int).SetString(strings.Repeat("1", 41), 10)
+	if err := n.SetBigInt(x); err == nil {
+		t.Errorf("41 digits: got %v, wanted error", n)
+	}
+}
+
+func TestBigFloat(t *testing.T) {
+	for _, tc := range [][2]string{
+		{"0.1", "0.1"},
+		{"-2.5", "-2.5"},
+		{"123.0000000001", "123.0000000001"},
+		{"1e-30", "0." + strings.Repeat("0", 29) + "1"},
+	} {
+		f, _, err := big.ParseFloat(tc[0], 10, 64, big.ToNearestEven)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var n OCINum
+		if err = n.SetBigFloat(f); err != nil {
+			t.Errorf("%s: %v", tc[0], err)
+			continue
+		}
+		if n.String() != tc[1] {
+			t.Errorf("%s: got %s, wanted %s", tc[0], n.String(), tc[1])
+		}
+		back, err := n.BigFloat()
+		if err != nil {
+			t.Errorf("%s: %v", tc[0], err)
+			continue
+		}
+		if back.Text('g', 20) != f.Text('g', 20) {
+			t.Errorf("%s: got %s", tc[0], back.Text('g', 20))
+		}
+	}
+
+	// 1/3 is rounded to 40 digits
+	var n OCINum
+	if err := n.SetBigRat(big.NewRat(1, 3)); err != nil {
+		t.Fatal(err)
+	}
+	if want := "0." + strings.Repeat("3", 40); n.String() != want {
+		t.Errorf("1/3: got %s, wanted %s", n.String(), want)
+	}
+}
+
+func TestBigRound(t *testing.T) {
+	threes := func(n int) string { return strings.Repeat("3", n) }
+	for _, tc := range []struct {
+		x    *big.Rat
+		want string
+	}{
+		// an even decimal exponent (3.3e0, 3.3e-2) leaves room for 39 digits
+		{big.NewRat(10, 3), "3." + threes(38)},
+		{big.NewRat(-10, 3), "-3." + threes(38)},
+		{big.NewRat(1000, 3), "333." + threes(36)},
+		{big.NewRat(1, 30), "0.0" + threes(39)},
+		// an odd one (3.3e-1, 3.3e1) for 40
+		{big.NewRat(1, 3), "0." + threes(40)},
+		{big.NewRat(-1, 3), "-0." + threes(40)},
+		{big.NewRat(100, 3), "33." + threes(38)},
+		{big.NewRat(-100, 3), "-33." + threes(38)},
+		// carrying into a new digit
+		{big.NewRat(2, 3), "0." + strings.Repeat("6", 39) + "7"},
+	} {
+		var n OCINum
+		if err := n.SetBigRat(tc.x); err != nil {
+			t.Errorf("%s: SetBigRat: %v", tc.x, err)
+			continue
+		}
+		if got := n.String(); got != tc.want {
+			t.Errorf("%s: SetBigRat got %s, wanted %s", tc.x, got, tc.want)
+		}
+
+		f := new(big.Float).SetPrec(200).SetRat(tc.x)
+		if err := n.SetBigFloat(f); err != nil {
+			t.Errorf("%s: SetBigFloat: %v", tc.x, err)
+			continue
+		}
+		if got := n.String(); got != tc.want {
+			t.Errorf("%s: SetBigFloat got %s, wanted %s", tc.x, got, tc.want)
+		}
+	}
+
+	// 45 digits, with an odd and an even exponent
+	for _, tc := range [][2]string{
+		{"3." + strings.Repeat("3", 44), "3." + strings.Repeat("3", 38)},
+		{"-33." + strings.Repeat("3", 43), "-33." + strings.Repeat("3", 38)},
+		{"9" + strings.Repeat("9", 44), "1" + strings.Repeat("0", 45)},
+	} {
+		f, _, err := big.ParseFloat(tc[0], 10, 200, big.ToNearestEven)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var n OCINum
+		if err = n.SetBigFloat(f); err != nil {
+			t.Errorf("%s: %v", tc[0], err)
+			continue
+		}
+		if got := n.String(); got != tc[1] {
+			t.Errorf("%s: got %s, wanted %s", tc[0], got, tc[1])
+		}
+	}
+
+	// 40 digit integers fit with an even number of digits only
+	for s, ok := range map[string]bool{
+		strings.Repeat("1", 40):             true,
+		strings.Repeat("1", 40) + "0":       false,
+		"-" + strings.Repeat("1", 39) + "0": true,
+	} {
+		x, _ := new(big.Int).SetString(s, 10)
+		var n OCINum
+		if err := n.SetBigInt(x); (err == nil) != ok {
+			t.Errorf("%s: got %v, wanted ok=%t", s, err, ok)
+		} else if ok && n.String() != s {
+			t.Errorf("%s: got %s", s, n.String())
+		}
+	}
+}
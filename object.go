@@ -0,0 +1,1343 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <stdlib.h>
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// ObjectKind is the kind of an Oracle user-defined type.
+type ObjectKind int
+
+const (
+	// ObjectKindObject is an OBJECT type (CREATE TYPE ... AS OBJECT).
+	ObjectKindObject ObjectKind = iota
+	// ObjectKindVarray is a VARRAY collection type.
+	ObjectKindVarray
+	// ObjectKindTable is a nested TABLE collection type.
+	ObjectKindTable
+	// ObjectKindRecord is a PL/SQL RECORD type, declared in a package.
+	ObjectKindRecord
+)
+
+func (k ObjectKind) String() string {
+	switch k {
+	case ObjectKindObject:
+		return "OBJECT"
+	case ObjectKindVarray:
+		return "VARRAY"
+	case ObjectKindTable:
+		return "TABLE"
+	case ObjectKindRecord:
+		return "RECORD"
+	}
+	return ""
+}
+
+// ObjectType describes an Oracle user-defined OBJECT, VARRAY or nested TABLE type,
+// or a PL/SQL RECORD or collection type.
+//
+// An ObjectType is obtained by Ses.DescribeType, and is valid only while
+// the describing Ses is open.
+type ObjectType struct {
+	Schema string
+	Name   string
+	Kind   ObjectKind
+
+	// Attributes of an OBJECT type, in declaration order.
+	Attributes []ObjectAttribute
+	// Elem describes the element of a VARRAY or nested TABLE type.
+	Elem *ObjectAttribute
+
+	tdo      *C.OCIType
+	typeCode C.OCITypeCode
+}
+
+// IsCollection returns true when the type is a VARRAY or nested TABLE.
+func (t *ObjectType) IsCollection() bool {
+	return t.Kind == ObjectKindVarray || t.Kind == ObjectKindTable
+}
+
+// FullName returns the schema qualified name of the type.
+func (t *ObjectType) FullName() string {
+	if t.Schema == "" {
+		return t.Name
+	}
+	return t.Schema + "." + t.Name
+}
+
+// AttributeIndex returns the index of the named attribute, or -1.
+// The name is compared case-insensitively.
+func (t *ObjectType) AttributeIndex(name string) int {
+	for i, a := range t.Attributes {
+		if strings.EqualFold(a.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// NewObject returns a new Object of the type.
+//
+// The optional src initializes the Object: a struct (or pointer to struct),
+// or a map[string]interface{} fills the attributes of an OBJECT type,
+// a slice or array fills the elements of a collection type.
+// Struct fields are matched to attributes by their `db` tag,
+// or by field name, case-insensitively.
+func (t *ObjectType) NewObject(src interface{}) (*Object, error) {
+	obj := &Object{Type: t}
+	if !t.IsCollection() {
+		obj.Attrs = make([]interface{}, len(t.Attributes))
+	}
+	if src == nil {
+		return obj, nil
+	}
+	switch x := src.(type) {
+	case *Object:
+		if x == nil {
+			obj.IsNull = true
+			return obj, nil
+		}
+		return x, nil
+	case Object:
+		return &x, nil
+	case map[string]interface{}:
+		if t.IsCollection() {
+			return nil, errF("cannot fill collection %s from a map", t.FullName())
+		}
+		for k, v := range x {
+			if err := obj.Set(k, v); err != nil {
+				return nil, err
+			}
+		}
+		return obj, nil
+	}
+	rv := reflect.ValueOf(src)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			obj.IsNull = true
+			return obj, nil
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if !t.IsCollection() {
+			return nil, errF("cannot fill %s %s from %T", t.Kind, t.FullName(), src)
+		}
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			obj.IsNull = true
+			return obj, nil
+		}
+		obj.Elems = make([]interface{}, rv.Len())
+		for i := range obj.Elems {
+			obj.Elems[i] = rv.Index(i).Interface()
+		}
+		return obj, nil
+	case reflect.Struct:
+		if t.IsCollection() {
+			return nil, errF("cannot fill collection %s from %T", t.FullName(), src)
+		}
+		rt := rv.Type()
+		for i := 0; i < rt.NumField(); i++ {
+			name, ok := objectFieldName(rt.Field(i))
+			if !ok {
+				continue
+			}
+			j := t.AttributeIndex(name)
+			if j < 0 {
+				continue
+			}
+			obj.Attrs[j] = rv.Field(i).Interface()
+		}
+		return obj, nil
+	}
+	return nil, errF("cannot fill %s %s from %T", t.Kind, t.FullName(), src)
+}
+
+// Record binds a Go struct as a PL/SQL RECORD or OBJECT parameter of Type.
+//
+// Value is a struct for IN parameters, and a pointer to a struct for
+// OUT and IN OUT parameters; the pointed struct is filled after execution.
+// Struct fields are matched to attributes as with ObjectType.NewObject.
+//
+// Binding PL/SQL RECORD types needs Oracle 12.1 or newer.
+type Record struct {
+	Type  *ObjectType
+	Value interface{}
+}
+
+// ObjectAttribute describes an attribute of an OBJECT type,
+// or the element of a collection type.
+type ObjectAttribute struct {
+	Name     string
+	TypeName string
+	// Type is set for attributes which are objects or collections themselves.
+	Type      *ObjectType
+	Precision int
+	Scale     int
+
+	typeCode C.OCITypeCode
+}
+
+// Object is an instance of an Oracle OBJECT, VARRAY or nested TABLE type.
+//
+// Attribute values of an OBJECT are held in Attrs, in the order of
+// Type.Attributes; elements of a collection are held in Elems.
+// Nested objects and collections are represented as *Object.
+// NULL attributes and elements are nil.
+//
+// An *Object may be passed as a parameter for IN OUT and OUT binds; Type
+// must be set in either case.
+type Object struct {
+	Type   *ObjectType
+	IsNull bool
+	Attrs  []interface{}
+	Elems  []interface{}
+}
+
+// Get returns the value of the named attribute.
+func (o *Object) Get(name string) (interface{}, error) {
+	if o.Type == nil {
+		return nil, errNew("Object.Type is nil")
+	}
+	i := o.Type.AttributeIndex(name)
+	if i < 0 {
+		return nil, errF("%s has no attribute %q", o.Type.FullName(), name)
+	}
+	if i >= len(o.Attrs) {
+		return nil, nil
+	}
+	return o.Attrs[i], nil
+}
+
+// Set sets the value of the named attribute.
+func (o *Object) Set(name string, value interface{}) error {
+	if o.Type == nil {
+		return errNew("Object.Type is nil")
+	}
+	i := o.Type.AttributeIndex(name)
+	if i < 0 {
+		return errF("%s has no attribute %q", o.Type.FullName(), name)
+	}
+	if len(o.Attrs) < len(o.Type.Attributes) {
+		o.Attrs = append(o.Attrs, make([]interface{}, len(o.Type.Attributes)-len(o.Attrs))...)
+	}
+	o.Attrs[i] = value
+	o.IsNull = false
+	return nil
+}
+
+// Len returns the number of elements of a collection.
+func (o *Object) Len() int {
+	return len(o.Elems)
+}
+
+// Append appends elements to a collection.
+func (o *Object) Append(values ...interface{}) {
+	o.Elems = append(o.Elems, values...)
+	o.IsNull = false
+}
+
+// Map returns the attributes of the object keyed by attribute name.
+// Nested objects are returned as maps, collections as []interface{}.
+func (o *Object) Map() map[string]interface{} {
+	if o == nil || o.IsNull || o.Type == nil {
+		return nil
+	}
+	m := make(map[string]interface{}, len(o.Attrs))
+	for i, a := range o.Type.Attributes {
+		if i < len(o.Attrs) {
+			m[a.Name] = objectPlain(o.Attrs[i])
+		}
+	}
+	return m
+}
+
+func objectPlain(v interface{}) interface{} {
+	o, ok := v.(*Object)
+	if !ok {
+		return v
+	}
+	if o == nil || o.IsNull {
+		return nil
+	}
+	if o.Type != nil && o.Type.IsCollection() {
+		elems := make([]interface{}, len(o.Elems))
+		for i, e := range o.Elems {
+			elems[i] = objectPlain(e)
+		}
+		return elems
+	}
+	return o.Map()
+}
+
+// Decode stores the object into dst, which must be a pointer to a struct
+// (for OBJECT types) or a pointer to a slice (for collections).
+//
+// Struct fields are matched to attributes by their `db` tag, or by field name,
+// case-insensitively. Nested objects and collections are decoded recursively.
+func (o *Object) Decode(dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errF("Decode needs a non-nil pointer, got %T", dst)
+	}
+	return o.decode(rv.Elem())
+}
+
+func (o *Object) decode(rv reflect.Value) error {
+	if o == nil || o.IsNull {
+		rv.Set(reflect.Zero(rv.Type()))
+		return nil
+	}
+	switch rv.Type() {
+	case reflect.TypeOf(Object{}):
+		rv.Set(reflect.ValueOf(*o))
+		return nil
+	case reflect.TypeOf(o):
+		rv.Set(reflect.ValueOf(o))
+		return nil
+	}
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return o.decode(rv.Elem())
+	case reflect.Interface:
+		rv.Set(reflect.ValueOf(objectPlain(o)))
+		return nil
+	case reflect.Slice:
+		if o.Type == nil || !o.Type.IsCollection() {
+			return errF("cannot decode %s into %s", o.typeName(), rv.Type())
+		}
+		s := reflect.MakeSlice(rv.Type(), len(o.Elems), len(o.Elems))
+		for i, e := range o.Elems {
+			if err := assignObjectValue(s.Index(i), e); err != nil {
+				return errF("%s[%d]: %v", o.Type.FullName(), i, err)
+			}
+		}
+		rv.Set(s)
+		return nil
+	case reflect.Map:
+		rt := rv.Type()
+		if rt.Key().Kind() != reflect.String || o.Type == nil || o.Type.IsCollection() {
+			break
+		}
+		m := reflect.MakeMap(rt)
+		for i, a := range o.Type.Attributes {
+			if i >= len(o.Attrs) {
+				break
+			}
+			elem := reflect.New(rt.Elem()).Elem()
+			if err := assignObjectValue(elem, o.Attrs[i]); err != nil {
+				return errF("%s.%s: %v", o.Type.FullName(), a.Name, err)
+			}
+			m.SetMapIndex(reflect.ValueOf(a.Name).Convert(rt.Key()), elem)
+		}
+		rv.Set(m)
+		return nil
+	case reflect.Struct:
+		if o.Type == nil || o.Type.IsCollection() {
+			return errF("cannot decode %s into %s", o.typeName(), rv.Type())
+		}
+		rt := rv.Type()
+		for i := 0; i < rt.NumField(); i++ {
+			name, ok := objectFieldName(rt.Field(i))
+			if !ok {
+				continue
+			}
+			j := o.Type.AttributeIndex(name)
+			if j < 0 || j >= len(o.Attrs) {
+				continue
+			}
+			if err := assignObjectValue(rv.Field(i), o.Attrs[j]); err != nil {
+				return errF("%s.%s: %v", o.Type.FullName(), o.Type.Attributes[j].Name, err)
+			}
+		}
+		return nil
+	}
+	return errF("cannot decode %s into %s", o.typeName(), rv.Type())
+}
+
+// typeName returns the full name of the type of o, for error messages.
+func (o *Object) typeName() string {
+	if o.Type == nil {
+		return "object of unknown type"
+	}
+	return o.Type.FullName()
+}
+
+// objectFieldName returns the attribute name for the struct field,
+// based on the `db` tag, and false if the field is to be skipped.
+func objectFieldName(f reflect.StructField) (string, bool) {
+	if f.PkgPath != "" { // unexported
+		return "", false
+	}
+	tag := f.Tag.Get("db")
+	if tag == "" {
+		return f.Name, true
+	}
+	name := strings.TrimSpace(strings.SplitN(tag, ",", 2)[0])
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		return f.Name, true
+	}
+	return name, true
+}
+
+// assignObjectValue sets dst to v, converting as needed.
+func assignObjectValue(dst reflect.Value, v interface{}) error {
+	if v == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	switch x := v.(type) {
+	case *Object:
+		return x.decode(dst)
+	case OCINum:
+		switch dst.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i, err := strconv.ParseInt(x.String(), 10, 64)
+			if err != nil {
+				return err
+			}
+			dst.SetInt(i)
+			return nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			u, err := strconv.ParseUint(x.String(), 10, 64)
+			if err != nil {
+				return err
+			}
+			dst.SetUint(u)
+			return nil
+		case reflect.Float32, reflect.Float64:
+			f, err := strconv.ParseFloat(x.String(), 64)
+			if err != nil {
+				return err
+			}
+			dst.SetFloat(f)
+			return nil
+		case reflect.String:
+			dst.SetString(x.String())
+			return nil
+		}
+	}
+	rv := reflect.ValueOf(v)
+	if dst.Kind() == reflect.Ptr {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return assignObjectValue(dst.Elem(), v)
+	}
+	if rv.Type().AssignableTo(dst.Type()) {
+		dst.Set(rv)
+		return nil
+	}
+	if rv.Type().ConvertibleTo(dst.Type()) {
+		dst.Set(rv.Convert(dst.Type()))
+		return nil
+	}
+	return errF("cannot assign %T to %s", v, dst.Type())
+}
+
+// DescribeType describes the named OBJECT, VARRAY or nested TABLE type,
+// or PL/SQL RECORD or collection type declared in a package.
+//
+// The name may be qualified with a schema name ("SCHEMA.TYPE_NAME"); PL/SQL
+// types are named with their package ("[SCHEMA.]PACKAGE.TYPE_NAME"), and
+// need Oracle 12.1 or newer.
+// Unquoted names are converted to upper case.
+// Described types are cached for the lifetime of the Ses.
+func (ses *Ses) DescribeType(name string) (typ *ObjectType, err error) {
+	ses.log(_drv.Cfg().Log.Ses.DescribeType, name)
+	if err = ses.checkClosed(); err != nil {
+		return nil, errE(err)
+	}
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = objectName(p)
+	}
+	switch len(parts) {
+	case 1:
+		return ses.describeType("", parts[0])
+	case 2:
+		// SCHEMA.TYPE or PACKAGE.TYPE
+		if typ, err = ses.describeType(parts[0], parts[1]); err == nil || !ses.hasPlsTypes() {
+			return typ, err
+		}
+		return ses.describeType("", parts[0]+"."+parts[1])
+	case 3:
+		return ses.describeType(parts[0], parts[1]+"."+parts[2])
+	}
+	return nil, errF("invalid type name %q", name)
+}
+
+func objectName(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > 1 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return strings.ToUpper(s)
+}
+
+// describeType describes the type, and caches the description.
+// The name is "PACKAGE.TYPE" for PL/SQL types.
+func (ses *Ses) describeType(schema, name string) (typ *ObjectType, err error) {
+	key := schema + "." + name
+	ses.RLock()
+	typ = ses.objTypes[key]
+	ses.RUnlock()
+	if typ != nil {
+		return typ, nil
+	}
+
+	env := ses.Env()
+	typ = &ObjectType{Schema: schema, Name: name}
+	var r C.sword
+	if strings.IndexByte(name, '.') >= 0 {
+		fullName := name
+		if schema != "" {
+			fullName = key
+		}
+		cFullName := C.CString(fullName)
+		r = C.typeByFullName(
+			env.ocienv,                              //OCIEnv          *env,
+			env.ocierr,                              //OCIError        *err,
+			ses.ocisvcctx,                           //const OCISvcCtx *svc,
+			(*C.OraText)(unsafe.Pointer(cFullName)), //const oratext   *full_type_name,
+			C.ub4(len(fullName)),                    //ub4             full_type_name_length,
+			&typ.tdo)                                //OCIType         **tdo );
+		C.free(unsafe.Pointer(cFullName))
+		if r == C.OCI_ERROR {
+			if C.HAS_PLSQL_TYPES == 0 {
+				return nil, errF("describing PL/SQL type %s needs Oracle 12.1 client", fullName)
+			}
+			return nil, errE(env.ociError("OCITypeByFullName(" + fullName + ")"))
+		}
+	} else {
+		var cSchema *C.char
+		if schema != "" {
+			cSchema = C.CString(schema)
+			defer C.free(unsafe.Pointer(cSchema))
+		}
+		cName := C.CString(name)
+		defer C.free(unsafe.Pointer(cName))
+		r = C.OCITypeByName(
+			env.ocienv,                            //OCIEnv          *env,
+			env.ocierr,                            //OCIError        *err,
+			ses.ocisvcctx,                         //const OCISvcCtx *svc,
+			(*C.OraText)(unsafe.Pointer(cSchema)), //const oratext   *schema_name,
+			C.ub4(len(schema)),                    //ub4             s_length,
+			(*C.OraText)(unsafe.Pointer(cName)),   //const oratext   *type_name,
+			C.ub4(len(name)),                      //ub4             t_length,
+			nil,                                   //const oratext   *version_name,
+			0,                                     //ub4             v_length,
+			C.OCI_DURATION_SESSION,                //OCIDuration     pin_duration,
+			C.OCI_TYPEGET_ALL,                     //OCITypeGetOpt   get_option,
+			&typ.tdo)                              //OCIType         **tdo );
+		if r == C.OCI_ERROR {
+			return nil, errE(env.ociError("OCITypeByName(" + key + ")"))
+		}
+	}
+
+	dsc, err := env.allocOciHandle(C.OCI_HTYPE_DESCRIBE)
+	if err != nil {
+		return nil, errE(err)
+	}
+	defer env.freeOciHandle(dsc, C.OCI_HTYPE_DESCRIBE)
+	r = C.OCIDescribeAny(
+		ses.ocisvcctx,           //OCISvcCtx     *svchp,
+		env.ocierr,              //OCIError      *errhp,
+		unsafe.Pointer(typ.tdo), //void          *objptr,
+		0,                       //ub4           objnm_len,
+		C.OCI_OTYPE_PTR,         //ub1           objptr_typ,
+		C.OCI_DEFAULT,           //ub1           info_level,
+		C.OCI_PTYPE_TYPE,        //ub1           objtyp,
+		(*C.OCIDescribe)(dsc))   //OCIDescribe   *dschp );
+	if r == C.OCI_ERROR {
+		return nil, errE(env.ociError("OCIDescribeAny(" + key + ")"))
+	}
+	var param unsafe.Pointer
+	if err = env.getAttr(dsc, C.OCI_HTYPE_DESCRIBE, unsafe.Pointer(&param), nil, C.OCI_ATTR_PARAM); err != nil {
+		return nil, errE(err)
+	}
+	if typ.Schema == "" {
+		if typ.Schema, err = env.paramString(param, C.OCI_ATTR_SCHEMA_NAME); err != nil {
+			return nil, errE(err)
+		}
+	}
+	if err = env.getAttr(param, C.OCI_DTYPE_PARAM, unsafe.Pointer(&typ.typeCode), nil, C.OCI_ATTR_TYPECODE); err != nil {
+		return nil, errE(err)
+	}
+	if typ.typeCode == C.OCI_TYPECODE_RECORD {
+		typ.Kind = ObjectKindRecord
+	}
+
+	// cache before describing attributes, to allow recursive types
+	ses.Lock()
+	if ses.objTypes == nil {
+		ses.objTypes = make(map[string]*ObjectType)
+	}
+	ses.objTypes[key] = typ
+	ses.Unlock()
+	defer func() {
+		if err != nil {
+			ses.Lock()
+			delete(ses.objTypes, key)
+			ses.Unlock()
+		}
+	}()
+
+	if typ.typeCode == C.OCI_TYPECODE_NAMEDCOLLECTION {
+		var collTypeCode C.OCITypeCode
+		if err = env.getAttr(param, C.OCI_DTYPE_PARAM, unsafe.Pointer(&collTypeCode), nil, C.OCI_ATTR_COLLECTION_TYPECODE); err != nil {
+			return nil, errE(err)
+		}
+		typ.Kind = ObjectKindTable
+		if collTypeCode == C.OCI_TYPECODE_VARRAY {
+			typ.Kind = ObjectKindVarray
+		}
+		var elemParam unsafe.Pointer
+		if err = env.getAttr(param, C.OCI_DTYPE_PARAM, unsafe.Pointer(&elemParam), nil, C.OCI_ATTR_COLLECTION_ELEMENT); err != nil {
+			return nil, errE(err)
+		}
+		var elem ObjectAttribute
+		if elem, err = ses.describeAttribute(elemParam, false); err != nil {
+			return nil, err
+		}
+		typ.Elem = &elem
+		return typ, nil
+	}
+
+	var numAttrs C.ub2
+	if err = env.getAttr(param, C.OCI_DTYPE_PARAM, unsafe.Pointer(&numAttrs), nil, C.OCI_ATTR_NUM_TYPE_ATTRS); err != nil {
+		return nil, errE(err)
+	}
+	var listParam unsafe.Pointer
+	if err = env.getAttr(param, C.OCI_DTYPE_PARAM, unsafe.Pointer(&listParam), nil, C.OCI_ATTR_LIST_TYPE_ATTRS); err != nil {
+		return nil, errE(err)
+	}
+	typ.Attributes = make([]ObjectAttribute, int(numAttrs))
+	for i := range typ.Attributes {
+		var attrParam unsafe.Pointer
+		// attribute position is 1-based
+		r := C.OCIParamGet(
+			listParam,         //const void        *hndlp,
+			C.OCI_DTYPE_PARAM, //ub4               htype,
+			env.ocierr,        //OCIError          *errhp,
+			&attrParam,        //void              **parmdpp,
+			C.ub4(i+1))        //ub4               pos );
+		if r == C.OCI_ERROR {
+			return nil, errE(env.ociError())
+		}
+		typ.Attributes[i], err = ses.describeAttribute(attrParam, true)
+		C.OCIDescriptorFree(attrParam, C.OCI_DTYPE_PARAM)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return typ, nil
+}
+
+// describeAttribute describes a type attribute or collection element parameter.
+func (ses *Ses) describeAttribute(param unsafe.Pointer, hasName bool) (attr ObjectAttribute, err error) {
+	env := ses.Env()
+	if hasName {
+		if attr.Name, err = env.paramString(param, C.OCI_ATTR_NAME); err != nil {
+			return attr, errE(err)
+		}
+	}
+	if err = env.getAttr(param, C.OCI_DTYPE_PARAM, unsafe.Pointer(&attr.typeCode), nil, C.OCI_ATTR_TYPECODE); err != nil {
+		return attr, errE(err)
+	}
+	if attr.TypeName, err = env.paramString(param, C.OCI_ATTR_TYPE_NAME); err != nil {
+		return attr, errE(err)
+	}
+	switch attr.typeCode {
+	case C.OCI_TYPECODE_NUMBER, C.OCI_TYPECODE_INTEGER, C.OCI_TYPECODE_SMALLINT,
+		C.OCI_TYPECODE_DECIMAL, C.OCI_TYPECODE_FLOAT, C.OCI_TYPECODE_REAL, C.OCI_TYPECODE_DOUBLE:
+		var precision C.sb2
+		var scale C.sb1
+		if err = env.getAttr(param, C.OCI_DTYPE_PARAM, unsafe.Pointer(&precision), nil, C.OCI_ATTR_PRECISION); err != nil {
+			return attr, errE(err)
+		}
+		if err = env.getAttr(param, C.OCI_DTYPE_PARAM, unsafe.Pointer(&scale), nil, C.OCI_ATTR_SCALE); err != nil {
+			return attr, errE(err)
+		}
+		attr.Precision, attr.Scale = int(precision), int(scale)
+		if attr.typeCode == C.OCI_TYPECODE_INTEGER || attr.typeCode == C.OCI_TYPECODE_SMALLINT {
+			attr.Scale = 0
+		}
+	case C.OCI_TYPECODE_OBJECT, C.OCI_TYPECODE_NAMEDCOLLECTION,
+		C.OCI_TYPECODE_VARRAY, C.OCI_TYPECODE_TABLE, C.OCI_TYPECODE_RECORD:
+		schema, err := env.paramString(param, C.OCI_ATTR_SCHEMA_NAME)
+		if err != nil {
+			return attr, errE(err)
+		}
+		name := attr.TypeName
+		if ses.hasPlsTypes() {
+			pkg, err := env.paramString(param, C.OCI_ATTR_PACKAGE_NAME)
+			if err != nil {
+				return attr, errE(err)
+			}
+			if pkg != "" {
+				name = pkg + "." + name
+			}
+		}
+		if attr.Type, err = ses.describeType(schema, name); err != nil {
+			return attr, err
+		}
+	}
+	return attr, nil
+}
+
+// getAttr gets an attribute of a handle or descriptor. No locking occurs.
+func (env *Env) getAttr(target unsafe.Pointer, targetType C.ub4, attrup unsafe.Pointer, attrSizep *C.ub4, attrType C.ub4) error {
+	r := C.OCIAttrGet(
+		target,     //const void     *trgthndlp,
+		targetType, //ub4            trghndltyp,
+		attrup,     //void           *attributep,
+		attrSizep,  //ub4            *sizep,
+		attrType,   //ub4            attrtype,
+		env.ocierr) //OCIError       *errhp );
+	if r == C.OCI_ERROR {
+		return env.ociError()
+	}
+	return nil
+}
+
+// paramString gets a string attribute of a parameter descriptor.
+func (env *Env) paramString(param unsafe.Pointer, attrType C.ub4) (string, error) {
+	var s *C.char
+	var n C.ub4
+	if err := env.getAttr(param, C.OCI_DTYPE_PARAM, unsafe.Pointer(&s), &n, attrType); err != nil {
+		return "", err
+	}
+	if s == nil {
+		return "", nil
+	}
+	return C.GoStringN(s, C.int(n)), nil
+}
+
+// objectFromInstance converts an OCI object or collection instance to an *Object.
+func (ses *Ses) objectFromInstance(typ *ObjectType, instance, nullStruct unsafe.Pointer) (*Object, error) {
+	obj := &Object{Type: typ}
+	if instance == nil || (nullStruct != nil && *(*C.OCIInd)(nullStruct) == C.OCI_IND_NULL) {
+		obj.IsNull = true
+		return obj, nil
+	}
+	env := ses.Env()
+	if typ.IsCollection() {
+		coll := (*C.OCIColl)(instance)
+		var size C.sb4
+		if r := C.OCICollSize(env.ocienv, env.ocierr, coll, &size); r == C.OCI_ERROR {
+			return nil, errE(env.ociError())
+		}
+		obj.Elems = make([]interface{}, 0, int(size))
+		for i := C.sb4(0); i < size; i++ {
+			var exists C.boolean
+			var elem, elemInd unsafe.Pointer
+			r := C.OCICollGetElem(
+				env.ocienv, //OCIEnv             *env,
+				env.ocierr, //OCIError           *err,
+				coll,       //const OCIColl      *coll,
+				i,          //sb4                index,
+				&exists,    //boolean            *exists,
+				&elem,      //void               **elem,
+				&elemInd)   //void               **elemind );
+			if r == C.OCI_ERROR {
+				return nil, errE(env.ociError())
+			}
+			if exists == 0 { // deleted element of a nested table
+				continue
+			}
+			var ind C.OCIInd
+			if elemInd != nil {
+				ind = *(*C.OCIInd)(elemInd)
+			}
+			v, err := ses.objectValue(typ.Elem, ind, elemInd, elem)
+			if err != nil {
+				return nil, err
+			}
+			obj.Elems = append(obj.Elems, v)
+		}
+		return obj, nil
+	}
+
+	obj.Attrs = make([]interface{}, len(typ.Attributes))
+	for i := range typ.Attributes {
+		attr := &typ.Attributes[i]
+		cName := C.CString(attr.Name)
+		names := [1]*C.OraText{(*C.OraText)(unsafe.Pointer(cName))}
+		lengths := [1]C.ub4{C.ub4(len(attr.Name))}
+		var ind C.OCIInd
+		var attrNullStruct, value unsafe.Pointer
+		var attrTdo *C.OCIType
+		r := C.OCIObjectGetAttr(
+			env.ocienv,      //OCIEnv          *env,
+			env.ocierr,      //OCIError        *err,
+			instance,        //void            *instance,
+			nullStruct,      //void            *null_struct,
+			typ.tdo,         //struct OCIType  *tdo,
+			&names[0],       //const oratext   **names,
+			&lengths[0],     //const ub4       *lengths,
+			1,               //const ub4       name_count,
+			nil,             //const ub4       *indexes,
+			0,               //const ub4       index_count,
+			&ind,            //OCIInd          *attr_null_status,
+			&attrNullStruct, //void            **attr_null_struct,
+			&value,          //void            **attr_value,
+			&attrTdo)        //struct OCIType  **attr_tdo );
+		C.free(unsafe.Pointer(cName))
+		if r == C.OCI_ERROR {
+			return nil, errE(env.ociError())
+		}
+		v, err := ses.objectValue(attr, ind, attrNullStruct, value)
+		if err != nil {
+			return nil, errF("%s.%s: %v", typ.FullName(), attr.Name, err)
+		}
+		obj.Attrs[i] = v
+	}
+	return obj, nil
+}
+
+// objectValue converts an attribute or element value to a Go value.
+func (ses *Ses) objectValue(attr *ObjectAttribute, ind C.OCIInd, nullStruct, value unsafe.Pointer) (interface{}, error) {
+	if ind == C.OCI_IND_NULL || value == nil {
+		return nil, nil
+	}
+	env := ses.Env()
+	switch attr.typeCode {
+	case C.OCI_TYPECODE_NUMBER, C.OCI_TYPECODE_INTEGER, C.OCI_TYPECODE_SMALLINT,
+		C.OCI_TYPECODE_DECIMAL, C.OCI_TYPECODE_FLOAT, C.OCI_TYPECODE_REAL, C.OCI_TYPECODE_DOUBLE:
+		num := (*C.OCINumber)(value)
+		gct := ses.Cfg().numericColumnType(attr.Precision, attr.Scale)
+		switch gct {
+		case I64, OraI64:
+			return env.OCINumberToInt(num, 8)
+		case I32, OraI32:
+			i, err := env.OCINumberToInt(num, 4)
+			return int32(i), err
+		case I16, OraI16:
+			i, err := env.OCINumberToInt(num, 2)
+			return int16(i), err
+		case I8, OraI8:
+			i, err := env.OCINumberToInt(num, 1)
+			return int8(i), err
+		case U64, OraU64:
+			return env.OCINumberToUint(num, 8)
+		case U32, OraU32:
+			u, err := env.OCINumberToUint(num, 4)
+			return uint32(u), err
+		case U16, OraU16:
+			u, err := env.OCINumberToUint(num, 2)
+			return uint16(u), err
+		case U8, OraU8:
+			u, err := env.OCINumberToUint(num, 1)
+			return uint8(u), err
+		case F64, OraF64:
+			return env.OCINumberToFloat(num, 8)
+		case F32, OraF32:
+			f, err := env.OCINumberToFloat(num, 4)
+			return float32(f), err
+		case S:
+			b, err := env.numberToText(nil, *num)
+			return string(b), err
+		}
+		var n OCINum
+		n.FromC(*num)
+		return n, nil
+	case C.OCI_TYPECODE_BFLOAT:
+		return float32(*(*C.float)(value)), nil
+	case C.OCI_TYPECODE_BDOUBLE:
+		return float64(*(*C.double)(value)), nil
+	case C.OCI_TYPECODE_VARCHAR2, C.OCI_TYPECODE_VARCHAR, C.OCI_TYPECODE_CHAR,
+		C.OCI_TYPECODE_NVARCHAR2, C.OCI_TYPECODE_NCHAR:
+		s := *(**C.OCIString)(value)
+		p := C.OCIStringPtr(env.ocienv, s)
+		if p == nil {
+			return "", nil
+		}
+		str := C.GoStringN((*C.char)(unsafe.Pointer(p)), C.int(C.OCIStringSize(env.ocienv, s)))
+		if (attr.typeCode == C.OCI_TYPECODE_CHAR || attr.typeCode == C.OCI_TYPECODE_NCHAR) &&
+			ses.Cfg().RTrimChar {
+			str = strings.TrimRight(str, " ")
+		}
+		return str, nil
+	case C.OCI_TYPECODE_RAW:
+		raw := *(**C.OCIRaw)(value)
+		p := C.OCIRawPtr(env.ocienv, raw)
+		if p == nil {
+			return []byte{}, nil
+		}
+		return C.GoBytes(unsafe.Pointer(p), C.int(C.OCIRawSize(env.ocienv, raw))), nil
+	case C.OCI_TYPECODE_DATE:
+		d := (*C.OCIDate)(value)
+		tz, err := ses.Timezone()
+		if err != nil {
+			return nil, err
+		}
+		return time.Date(int(d.OCIDateYYYY), time.Month(d.OCIDateMM), int(d.OCIDateDD),
+			int(d.OCIDateTime.OCITimeHH), int(d.OCIDateTime.OCITimeMI), int(d.OCIDateTime.OCITimeSS),
+			0, tz), nil
+	case C.OCI_TYPECODE_TIMESTAMP, C.OCI_TYPECODE_TIMESTAMP_TZ, C.OCI_TYPECODE_TIMESTAMP_LTZ:
+		return getTime(env, *(**C.OCIDateTime)(value))
+	case C.OCI_TYPECODE_BOOLEAN:
+		return *(*C.boolean)(value) != 0, nil
+	case C.OCI_TYPECODE_BINARY_INTEGER, C.OCI_TYPECODE_PLS_INTEGER:
+		return int64(*(*C.sb4)(value)), nil
+	case C.OCI_TYPECODE_OBJECT, C.OCI_TYPECODE_RECORD:
+		return ses.objectFromInstance(attr.Type, value, nullStruct)
+	case C.OCI_TYPECODE_NAMEDCOLLECTION, C.OCI_TYPECODE_VARRAY, C.OCI_TYPECODE_TABLE:
+		return ses.objectFromInstance(attr.Type, unsafe.Pointer(*(**C.OCIColl)(value)), nullStruct)
+	}
+	return nil, errF("unsupported attribute type %s (typecode %d)", attr.TypeName, attr.typeCode)
+}
+
+// newObjectInstance creates a new OCI instance of the object's type
+// and fills it from the object. The instance must be freed with freeObjectInstance.
+func (ses *Ses) newObjectInstance(obj *Object) (instance, nullStruct unsafe.Pointer, err error) {
+	typ := obj.Type
+	if typ == nil {
+		return nil, nil, errNew("Object.Type is required")
+	}
+	env := ses.Env()
+	r := C.OCIObjectNew(
+		env.ocienv,             //OCIEnv          *env,
+		env.ocierr,             //OCIError        *err,
+		ses.ocisvcctx,          //const OCISvcCtx *svc,
+		typ.typeCode,           //OCITypeCode     typecode,
+		typ.tdo,                //OCIType         *tdo,
+		nil,                    //void            *table,
+		C.OCI_DURATION_SESSION, //OCIDuration     duration,
+		1,                      //boolean         value,
+		&instance)              //void            **instance );
+	if r == C.OCI_ERROR {
+		return nil, nil, errE(env.ociError())
+	}
+	defer func() {
+		if err != nil {
+			ses.freeObjectInstance(instance)
+			instance, nullStruct = nil, nil
+		}
+	}()
+	if r := C.OCIObjectGetInd(env.ocienv, env.ocierr, instance, &nullStruct); r == C.OCI_ERROR {
+		return nil, nil, errE(env.ociError())
+	}
+	if obj.IsNull {
+		*(*C.OCIInd)(nullStruct) = C.OCI_IND_NULL
+		return instance, nullStruct, nil
+	}
+	*(*C.OCIInd)(nullStruct) = C.OCI_IND_NOTNULL
+
+	if typ.IsCollection() {
+		for i, e := range obj.Elems {
+			value, ind, elemNullStruct, free, err := ses.objectOCIValue(typ.Elem, e)
+			if err != nil {
+				return nil, nil, errF("%s[%d]: %v", typ.FullName(), i, err)
+			}
+			if elemNullStruct == nil {
+				elemNullStruct = unsafe.Pointer(&ind)
+			}
+			r := C.OCICollAppend(
+				env.ocienv,             //OCIEnv           *env,
+				env.ocierr,             //OCIError         *err,
+				value,                  //const void       *elem,
+				elemNullStruct,         //const void       *elemind,
+				(*C.OCIColl)(instance)) //OCIColl          *coll );
+			free()
+			if r == C.OCI_ERROR {
+				return nil, nil, errE(env.ociError())
+			}
+		}
+		return instance, nullStruct, nil
+	}
+
+	for i := range typ.Attributes {
+		attr := &typ.Attributes[i]
+		var v interface{}
+		if i < len(obj.Attrs) {
+			v = obj.Attrs[i]
+		}
+		value, ind, attrNullStruct, free, err := ses.objectOCIValue(attr, v)
+		if err != nil {
+			return nil, nil, errF("%s.%s: %v", typ.FullName(), attr.Name, err)
+		}
+		cName := C.CString(attr.Name)
+		names := [1]*C.OraText{(*C.OraText)(unsafe.Pointer(cName))}
+		lengths := [1]C.ub4{C.ub4(len(attr.Name))}
+		r := C.OCIObjectSetAttr(
+			env.ocienv,     //OCIEnv          *env,
+			env.ocierr,     //OCIError        *err,
+			instance,       //void            *instance,
+			nullStruct,     //void            *null_struct,
+			typ.tdo,        //struct OCIType  *tdo,
+			&names[0],      //const oratext   **names,
+			&lengths[0],    //const ub4       *lengths,
+			1,              //const ub4       name_count,
+			nil,            //const ub4       *indexes,
+			0,              //const ub4       index_count,
+			ind,            //const OCIInd    null_status,
+			attrNullStruct, //const void      *attr_null_struct,
+			value)          //const void      *attr_value );
+		C.free(unsafe.Pointer(cName))
+		free()
+		if r == C.OCI_ERROR {
+			return nil, nil, errE(env.ociError())
+		}
+	}
+	return instance, nullStruct, nil
+}
+
+// freeObjectInstance frees an instance created by newObjectInstance, or fetched.
+func (ses *Ses) freeObjectInstance(instance unsafe.Pointer) {
+	if instance == nil {
+		return
+	}
+	env := ses.Env()
+	C.OCIObjectFree(env.ocienv, env.ocierr, instance, C.OCI_OBJECTFREE_FORCE)
+}
+
+// objectOCIValue converts v to the OCI representation of the attribute or element.
+// The returned value is copied by OCI, so free must be called right after its use.
+func (ses *Ses) objectOCIValue(attr *ObjectAttribute, v interface{}) (value unsafe.Pointer, ind C.OCIInd, nullStruct unsafe.Pointer, free func(), err error) {
+	free = func() {}
+	ind = C.OCI_IND_NOTNULL
+	env := ses.Env()
+	switch x := v.(type) {
+	case nil:
+		ind = C.OCI_IND_NULL
+	case String:
+		if x.IsNull {
+			ind = C.OCI_IND_NULL
+		} else {
+			v = x.Value
+		}
+	case Time:
+		if x.IsNull {
+			ind = C.OCI_IND_NULL
+		} else {
+			v = x.Value
+		}
+	case Int64:
+		if x.IsNull {
+			ind = C.OCI_IND_NULL
+		} else {
+			v = x.Value
+		}
+	case Float64:
+		if x.IsNull {
+			ind = C.OCI_IND_NULL
+		} else {
+			v = x.Value
+		}
+	case *Object:
+		if x == nil {
+			ind = C.OCI_IND_NULL
+		}
+	}
+
+	switch attr.typeCode {
+	case C.OCI_TYPECODE_NUMBER, C.OCI_TYPECODE_INTEGER, C.OCI_TYPECODE_SMALLINT,
+		C.OCI_TYPECODE_DECIMAL, C.OCI_TYPECODE_FLOAT, C.OCI_TYPECODE_REAL, C.OCI_TYPECODE_DOUBLE:
+		num := (*C.OCINumber)(C.malloc(C.sizeof_OCINumber))
+		free = func() { C.free(unsafe.Pointer(num)) }
+		value = unsafe.Pointer(num)
+		if ind == C.OCI_IND_NULL {
+			C.OCINumberSetZero(env.ocierr, num)
+			return value, ind, nil, free, nil
+		}
+		switch x := v.(type) {
+		case int64:
+			err = env.OCINumberFromInt(num, x, 8)
+		case int:
+			err = env.OCINumberFromInt(num, int64(x), 8)
+		case int32:
+			err = env.OCINumberFromInt(num, int64(x), 8)
+		case int16:
+			err = env.OCINumberFromInt(num, int64(x), 8)
+		case int8:
+			err = env.OCINumberFromInt(num, int64(x), 8)
+		case uint64:
+			err = env.OCINumberFromUint(num, x, 8)
+		case uint:
+			err = env.OCINumberFromUint(num, uint64(x), 8)
+		case uint32:
+			err = env.OCINumberFromUint(num, uint64(x), 8)
+		case uint16:
+			err = env.OCINumberFromUint(num, uint64(x), 8)
+		case uint8:
+			err = env.OCINumberFromUint(num, uint64(x), 8)
+		case float64:
+			err = env.OCINumberFromFloat(num, x, 8)
+		case float32:
+			err = env.OCINumberFromFloat(num, float64(x), 8)
+		case OCINum:
+			x.ToC(num)
+		case string:
+			var n OCINum
+			if err = n.SetString(x); err == nil {
+				n.ToC(num)
+			}
+		default:
+			err = errF("cannot convert %T to %s", v, attr.TypeName)
+		}
+		if err != nil {
+			free()
+			return nil, ind, nil, nil, err
+		}
+		return value, ind, nil, free, nil
+
+	case C.OCI_TYPECODE_BINARY_INTEGER, C.OCI_TYPECODE_PLS_INTEGER:
+		var i int64
+		switch x := v.(type) {
+		case nil:
+		case int64:
+			i = x
+		case int:
+			i = int64(x)
+		case int32:
+			i = int64(x)
+		default:
+			if ind != C.OCI_IND_NULL {
+				return nil, ind, nil, nil, errF("cannot convert %T to %s", v, attr.TypeName)
+			}
+		}
+		p := (*C.sb4)(C.malloc(C.sizeof_sb4))
+		*p = C.sb4(i)
+		return unsafe.Pointer(p), ind, nil, func() { C.free(unsafe.Pointer(p)) }, nil
+
+	case C.OCI_TYPECODE_BFLOAT, C.OCI_TYPECODE_BDOUBLE:
+		var f float64
+		switch x := v.(type) {
+		case nil:
+		case float64:
+			f = x
+		case float32:
+			f = float64(x)
+		case int64:
+			f = float64(x)
+		case int:
+			f = float64(x)
+		default:
+			if ind != C.OCI_IND_NULL {
+				return nil, ind, nil, nil, errF("cannot convert %T to %s", v, attr.TypeName)
+			}
+		}
+		if attr.typeCode == C.OCI_TYPECODE_BFLOAT {
+			p := (*C.float)(C.malloc(C.sizeof_float))
+			*p = C.float(f)
+			return unsafe.Pointer(p), ind, nil, func() { C.free(unsafe.Pointer(p)) }, nil
+		}
+		p := (*C.double)(C.malloc(C.sizeof_double))
+		*p = C.double(f)
+		return unsafe.Pointer(p), ind, nil, func() { C.free(unsafe.Pointer(p)) }, nil
+
+	case C.OCI_TYPECODE_VARCHAR2, C.OCI_TYPECODE_VARCHAR, C.OCI_TYPECODE_CHAR,
+		C.OCI_TYPECODE_NVARCHAR2, C.OCI_TYPECODE_NCHAR:
+		var s string
+		switch x := v.(type) {
+		case nil:
+		case string:
+			s = x
+		case []byte:
+			s = string(x)
+		case fmt.Stringer:
+			s = x.String()
+		default:
+			if ind != C.OCI_IND_NULL {
+				return nil, ind, nil, nil, errF("cannot convert %T to %s", v, attr.TypeName)
+			}
+		}
+		var str *C.OCIString
+		cs := C.CString(s)
+		r := C.OCIStringAssignText(env.ocienv, env.ocierr,
+			(*C.OraText)(unsafe.Pointer(cs)), C.ub4(len(s)), &str)
+		C.free(unsafe.Pointer(cs))
+		if r == C.OCI_ERROR {
+			return nil, ind, nil, nil, env.ociError()
+		}
+		return unsafe.Pointer(str), ind, nil, func() { C.OCIStringResize(env.ocienv, env.ocierr, 0, &str) }, nil
+
+	case C.OCI_TYPECODE_RAW:
+		var b []byte
+		switch x := v.(type) {
+		case nil:
+		case []byte:
+			b = x
+		case Raw:
+			b = x.Value
+			if x.IsNull {
+				ind = C.OCI_IND_NULL
+			}
+		default:
+			if ind != C.OCI_IND_NULL {
+				return nil, ind, nil, nil, errF("cannot convert %T to %s", v, attr.TypeName)
+			}
+		}
+		var raw *C.OCIRaw
+		var p *C.ub1
+		if len(b) > 0 {
+			p = (*C.ub1)(C.CBytes(b))
+		}
+		r := C.OCIRawAssignBytes(env.ocienv, env.ocierr, p, C.ub4(len(b)), &raw)
+		if p != nil {
+			C.free(unsafe.Pointer(p))
+		}
+		if r == C.OCI_ERROR {
+			return nil, ind, nil, nil, env.ociError()
+		}
+		return unsafe.Pointer(raw), ind, nil, func() { C.OCIRawResize(env.ocienv, env.ocierr, 0, &raw) }, nil
+
+	case C.OCI_TYPECODE_DATE:
+		var t time.Time
+		switch x := v.(type) {
+		case nil:
+		case time.Time:
+			t = x
+		case Date:
+			t = x.Get()
+			if x.IsNull() {
+				ind = C.OCI_IND_NULL
+			}
+		default:
+			if ind != C.OCI_IND_NULL {
+				return nil, ind, nil, nil, errF("cannot convert %T to %s", v, attr.TypeName)
+			}
+		}
+		d := (*C.OCIDate)(C.malloc(C.sizeof_OCIDate))
+		*d = C.OCIDate{}
+		if ind != C.OCI_IND_NULL {
+			if tz, err := ses.Timezone(); err == nil {
+				t = t.In(tz)
+			}
+			d.OCIDateYYYY = C.sb2(t.Year())
+			d.OCIDateMM = C.ub1(t.Month())
+			d.OCIDateDD = C.ub1(t.Day())
+			d.OCIDateTime.OCITimeHH = C.ub1(t.Hour())
+			d.OCIDateTime.OCITimeMI = C.ub1(t.Minute())
+			d.OCIDateTime.OCITimeSS = C.ub1(t.Second())
+		} else {
+			d.OCIDateYYYY, d.OCIDateMM, d.OCIDateDD = 1, 1, 1
+		}
+		return unsafe.Pointer(d), ind, nil, func() { C.free(unsafe.Pointer(d)) }, nil
+
+	case C.OCI_TYPECODE_TIMESTAMP, C.OCI_TYPECODE_TIMESTAMP_TZ, C.OCI_TYPECODE_TIMESTAMP_LTZ:
+		var t time.Time
+		switch x := v.(type) {
+		case nil:
+		case time.Time:
+			t = x
+		default:
+			if ind != C.OCI_IND_NULL {
+				return nil, ind, nil, nil, errF("cannot convert %T to %s", v, attr.TypeName)
+			}
+		}
+		if ind == C.OCI_IND_NULL {
+			t = time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
+		}
+		dtype := C.ub4(C.OCI_DTYPE_TIMESTAMP)
+		switch attr.typeCode {
+		case C.OCI_TYPECODE_TIMESTAMP_TZ:
+			dtype = C.OCI_DTYPE_TIMESTAMP_TZ
+		case C.OCI_TYPECODE_TIMESTAMP_LTZ:
+			dtype = C.OCI_DTYPE_TIMESTAMP_LTZ
+		}
+		var dt *C.OCIDateTime
+		r := C.OCIDescriptorAlloc(
+			unsafe.Pointer(env.ocienv),             //CONST dvoid   *parenth,
+			(*unsafe.Pointer)(unsafe.Pointer(&dt)), //dvoid         **descpp,
+			dtype,                                  //ub4           type,
+			0,                                      //size_t        xtramem_sz,
+			nil)                                    //dvoid         **usrmempp);
+		if r == C.OCI_ERROR {
+			return nil, ind, nil, nil, env.ociError()
+		}
+		free = func() { C.OCIDescriptorFree(unsafe.Pointer(dt), dtype) }
+		var zonep *C.OraText
+		var zone []byte
+		if dtype != C.OCI_DTYPE_TIMESTAMP {
+			zone = zoneOffset(zone, t)
+			zonep = (*C.OraText)(unsafe.Pointer(&zone[0]))
+		}
+		r = C.OCIDateTimeConstruct(
+			unsafe.Pointer(env.ocienv), //dvoid         *hndl,
+			env.ocierr,                 //OCIError      *err,
+			dt,                         //OCIDateTime   *datetime,
+			C.sb2(t.Year()),            //sb2           year,
+			C.ub1(int32(t.Month())),    //ub1           month,
+			C.ub1(t.Day()),             //ub1           day,
+			C.ub1(t.Hour()),            //ub1           hour,
+			C.ub1(t.Minute()),          //ub1           min,
+			C.ub1(t.Second()),          //ub1           sec,
+			C.ub4(t.Nanosecond()),      //ub4           fsec,
+			zonep,                      //OraText       *timezone,
+			C.size_t(len(zone)))        //size_t        timezone_length );
+		if r == C.OCI_ERROR {
+			free()
+			return nil, ind, nil, nil, env.ociError()
+		}
+		return unsafe.Pointer(dt), ind, nil, free, nil
+
+	case C.OCI_TYPECODE_BOOLEAN:
+		var b bool
+		switch x := v.(type) {
+		case nil:
+		case bool:
+			b = x
+		case Bool:
+			b = x.Value
+			if x.IsNull {
+				ind = C.OCI_IND_NULL
+			}
+		default:
+			if ind != C.OCI_IND_NULL {
+				return nil, ind, nil, nil, errF("cannot convert %T to %s", v, attr.TypeName)
+			}
+		}
+		p := (*C.boolean)(C.malloc(C.sizeof_boolean))
+		*p = 0
+		if b {
+			*p = 1
+		}
+		return unsafe.Pointer(p), ind, nil, func() { C.free(unsafe.Pointer(p)) }, nil
+
+	case C.OCI_TYPECODE_OBJECT, C.OCI_TYPECODE_NAMEDCOLLECTION,
+		C.OCI_TYPECODE_VARRAY, C.OCI_TYPECODE_TABLE, C.OCI_TYPECODE_RECORD:
+		if attr.Type == nil {
+			return nil, ind, nil, nil, errF("no type information for %s", attr.TypeName)
+		}
+		obj, err := attr.Type.NewObject(v)
+		if err != nil {
+			return nil, ind, nil, nil, err
+		}
+		if ind == C.OCI_IND_NULL {
+			obj = &Object{Type: attr.Type, IsNull: true}
+		}
+		instance, nullStruct, err := ses.newObjectInstance(obj)
+		if err != nil {
+			return nil, ind, nil, nil, err
+		}
+		if obj.IsNull {
+			ind = C.OCI_IND_NULL
+		}
+		return instance, ind, nullStruct, func() { ses.freeObjectInstance(instance) }, nil
+	}
+	return nil, ind, nil, nil, errF("unsupported attribute type %s (typecode %d)", attr.TypeName, attr.typeCode)
+}
@@ -0,0 +1,53 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestObjectDecodeMap(t *testing.T) {
+	typ := &ObjectType{Name: "PAIR", Attributes: []ObjectAttribute{{Name: "A"}, {Name: "B"}}}
+
+	strs := &Object{Type: typ, Attrs: []interface{}{"x", "y"}}
+	var ms map[string]string
+	if err := strs.Decode(&ms); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"A": "x", "B": "y"}; !reflect.DeepEqual(ms, want) {
+		t.Errorf("got %v, wanted %v", ms, want)
+	}
+
+	ints := &Object{Type: typ, Attrs: []interface{}{int64(1), nil}}
+	var mi map[string]int
+	if err := ints.Decode(&mi); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]int{"A": 1, "B": 0}; !reflect.DeepEqual(mi, want) {
+		t.Errorf("got %v, wanted %v", mi, want)
+	}
+
+	var mb map[string][]int
+	if err := strs.Decode(&mb); err == nil {
+		t.Errorf("got %v, wanted error", mb)
+	}
+}
+
+func TestObjectDecodeNilType(t *testing.T) {
+	o := &Object{Attrs: []interface{}{"x"}}
+	var s struct{ A string }
+	if err := o.Decode(&s); err == nil {
+		t.Error("struct: wanted error")
+	}
+	var sl []string
+	if err := o.Decode(&sl); err == nil {
+		t.Error("slice: wanted error")
+	}
+	var m map[string]string
+	if err := o.Decode(&m); err == nil {
+		t.Error("map: wanted error")
+	}
+}
@@ -18,7 +18,6 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
-	"unsafe"
 )
 
 const (
@@ -103,16 +102,20 @@ func init() {
 	_drv.bndPools[bndIdxLob] = newPool(func() interface{} { return &bndLob{} })
 	_drv.bndPools[bndIdxLobPtr] = newPool(func() interface{} { return &bndLobPtr{} })
 	_drv.bndPools[bndIdxLobSlice] = newPool(func() interface{} { return &bndLobSlice{} })
+	_drv.bndPools[bndIdxLobLocator] = newPool(func() interface{} { return &bndLobLocator{} })
 	_drv.bndPools[bndIdxIntervalYM] = newPool(func() interface{} { return &bndIntervalYM{} })
 	_drv.bndPools[bndIdxIntervalYMSlice] = newPool(func() interface{} { return &bndIntervalYMSlice{} })
 	_drv.bndPools[bndIdxIntervalDS] = newPool(func() interface{} { return &bndIntervalDS{} })
 	_drv.bndPools[bndIdxIntervalDSSlice] = newPool(func() interface{} { return &bndIntervalDSSlice{} })
 	_drv.bndPools[bndIdxRset] = newPool(func() interface{} { return &bndRset{} })
 	_drv.bndPools[bndIdxBfile] = newPool(func() interface{} { return &bndBfile{} })
+	_drv.bndPools[bndIdxObject] = newPool(func() interface{} { return &bndObject{} })
+	_drv.bndPools[bndIdxPlsBool] = newPool(func() interface{} { return &bndPlsBool{} })
+	_drv.bndPools[bndIdxJSON] = newPool(func() interface{} { return &bndJSON{} })
 	_drv.bndPools[bndIdxNil] = newPool(func() interface{} { return &bndNil{} })
 
 	// init def pools
-	_drv.defPools = make([]*sync.Pool, defIdxRset+1)
+	_drv.defPools = make([]*sync.Pool, defIdxBigNum+1)
 	_drv.defPools[defIdxInt64] = newPool(func() interface{} { return &defInt64{} })
 	_drv.defPools[defIdxInt32] = newPool(func() interface{} { return &defInt32{} })
 	_drv.defPools[defIdxInt16] = newPool(func() interface{} { return &defInt16{} })
@@ -133,11 +136,15 @@ func init() {
 	_drv.defPools[defIdxLob] = newPool(func() interface{} { return &defLob{} })
 	_drv.defPools[defIdxRaw] = newPool(func() interface{} { return &defRaw{} })
 	_drv.defPools[defIdxLongRaw] = newPool(func() interface{} { return &defLongRaw{} })
+	_drv.defPools[defIdxLong] = newPool(func() interface{} { return &defLong{} })
 	_drv.defPools[defIdxBfile] = newPool(func() interface{} { return &defBfile{} })
 	_drv.defPools[defIdxIntervalYM] = newPool(func() interface{} { return &defIntervalYM{} })
 	_drv.defPools[defIdxIntervalDS] = newPool(func() interface{} { return &defIntervalDS{} })
 	_drv.defPools[defIdxRowid] = newPool(func() interface{} { return &defRowid{} })
 	_drv.defPools[defIdxRset] = newPool(func() interface{} { return &defRset{} })
+	_drv.defPools[defIdxObject] = newPool(func() interface{} { return &defObject{} })
+	_drv.defPools[defIdxJSON] = newPool(func() interface{} { return &defJSON{} })
+	_drv.defPools[defIdxBigNum] = newPool(func() interface{} { return &defBigNum{} })
 
 	var err error
 	if _drv.sqlPkgEnv, err = OpenEnv(); err != nil {
@@ -155,23 +162,30 @@ var csIDAl32UTF8 uint32
 
 // OpenEnv opens an Oracle environment.
 //
-// Optionally specify a cfg parameter. If cfg is nil, default cfg values are
-// applied.
-func OpenEnv() (env *Env, err error) {
+// Optionally specify an envCfg parameter, to choose the client character
+// sets. If envCfg is not specified, AL32UTF8 is used.
+func OpenEnv(envCfg ...EnvCfg) (env *Env, err error) {
 	cfg := _drv.Cfg()
 	log(cfg.Log.OpenEnv)
-	csid := C.ub2(atomic.LoadUint32(&csIDAl32UTF8))
-	if csid == 0 { // Get the code for AL32UTF8
-		var ocienv *C.OCIEnv
-		r := C.OCIEnvCreate(&ocienv, C.OCI_DEFAULT|C.OCI_THREADED, nil, nil, nil, nil, 0, nil)
-		if r == C.OCI_ERROR {
-			return nil, errF("Unable to create environment handle (Return code = %d).", r)
+	if atomic.LoadUint32(&csIDAl32UTF8) == 0 { // Get the code for AL32UTF8
+		csid, err := charsetID("AL32UTF8")
+		if err != nil {
+			return nil, err
 		}
-		csName := []byte("AL32UTF8\x00") // http://docs.oracle.com/cd/B10501_01/server.920/a96529/ch8.htm#14284
-		csid = C.OCINlsCharSetNameToId(unsafe.Pointer(ocienv), (*C.oratext)(&csName[0]))
-		C.OCIHandleFree(unsafe.Pointer(ocienv), C.OCI_HTYPE_ENV)
 		atomic.StoreUint32(&csIDAl32UTF8, uint32(csid))
 	}
+	var ec EnvCfg
+	if len(envCfg) > 0 {
+		ec = envCfg[0]
+	}
+	cs, err := newCharset(ec.Charset, ec.StrictCharset)
+	if err != nil {
+		return nil, err
+	}
+	ncs, err := newCharset(ec.NCharset, ec.StrictCharset)
+	if err != nil {
+		return nil, err
+	}
 	// OCI_DEFAULT  - The default value, which is non-UTF-16 encoding.
 	// OCI_THREADED - Uses threaded environment. Internal data structures not exposed to the user are protected from concurrent accesses by multiple threads.
 	// OCI_OBJECT   - Uses object features such as OCINumber, OCINumberToInt, OCINumberFromInt. These are used in oracle-go type conversions.
@@ -182,18 +196,19 @@ func OpenEnv() (env *Env, err error) {
 	r := C.OCIEnvNlsCreate(
 		&env.ocienv, //OCIEnv        **envhpp,
 		C.OCI_DEFAULT|C.OCI_OBJECT|C.OCI_THREADED, //ub4           mode,
-		nil,  //void          *ctxp,
-		nil,  //void          *(*malocfp)
-		nil,  //void          *(*ralocfp)
-		nil,  //void          (*mfreefp)
-		0,    //size_t        xtramemsz,
-		nil,  //void          **usrmempp
-		csid, //ub2           charset,
-		csid) //ub2           ncharset );
+		nil,    //void          *ctxp,
+		nil,    //void          *(*malocfp)
+		nil,    //void          *(*ralocfp)
+		nil,    //void          (*mfreefp)
+		0,      //size_t        xtramemsz,
+		nil,    //void          **usrmempp
+		cs.id,  //ub2           charset,
+		ncs.id) //ub2           ncharset );
 	_drv.RUnlock()
 	if r == C.OCI_ERROR {
 		return nil, errF("Unable to create environment handle (Return code = %d).", r)
 	}
+	env.charset, env.ncharset = cs, ncs
 	ocierr, err := env.allocOciHandle(C.OCI_HTYPE_ERROR) // alloc oci error handle
 	if err != nil {
 		return nil, errE(err)
@@ -203,7 +218,11 @@ func OpenEnv() (env *Env, err error) {
 	if env.id == 0 {
 		env.id = _drv.envId.nextId()
 	}
-	env.SetCfg(cfg.StmtCfg)
+	if ec.StmtCfg.IsZero() {
+		env.SetCfg(cfg.StmtCfg)
+	} else {
+		env.SetCfg(ec.StmtCfg)
+	}
 	_drv.RLock()
 	_drv.openEnvs.add(env)
 	_drv.RUnlock()
@@ -0,0 +1,322 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package oson
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/rana/ora.v4/num"
+)
+
+// errShort is panicked by the decoder on truncated data, and recovered in Decode.
+var errShort = errors.New("OSON data is truncated")
+
+// Decode returns the generic Go representation of the OSON data.
+func Decode(data []byte) (value interface{}, err error) {
+	if !IsOSON(data) {
+		return nil, ErrNotOSON
+	}
+	d := decoder{data: data, pos: 3}
+	defer func() {
+		if r := recover(); r != nil {
+			if r != errShort {
+				panic(r)
+			}
+			err = errShort
+		}
+	}()
+	return d.decode()
+}
+
+type decoder struct {
+	data       []byte
+	pos        int
+	treePos    int
+	relOffsets bool
+	idSize     int
+	names      []string
+}
+
+func (d *decoder) read(n int) []byte {
+	if n < 0 || d.pos+n > len(d.data) {
+		panic(errShort)
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b
+}
+
+func (d *decoder) uint8() uint8   { return d.read(1)[0] }
+func (d *decoder) uint16() uint16 { return binary.BigEndian.Uint16(d.read(2)) }
+func (d *decoder) uint32() uint32 { return binary.BigEndian.Uint32(d.read(4)) }
+
+func (d *decoder) uintN(size int) int {
+	switch size {
+	case 1:
+		return int(d.uint8())
+	case 2:
+		return int(d.uint16())
+	}
+	return int(d.uint32())
+}
+
+// decode parses the header, and the root node.
+func (d *decoder) decode() (interface{}, error) {
+	version := d.uint8()
+	if version != versionMaxFname255 && version != versionMaxFname65535 {
+		return nil, errors.Errorf("unsupported OSON version %d", version)
+	}
+	flags := d.uint16()
+	d.relOffsets = flags&flagRelOffsetMode != 0
+	if flags&flagIsScalar != 0 {
+		if flags&flagTreeSegUint32 != 0 {
+			d.read(4)
+		} else {
+			d.read(2)
+		}
+		d.treePos = d.pos
+		return d.node()
+	}
+
+	var numNames int
+	switch {
+	case flags&flagNumFnamesUint32 != 0:
+		d.idSize, numNames = 4, int(d.uint32())
+	case flags&flagNumFnamesUint16 != 0:
+		d.idSize, numNames = 2, int(d.uint16())
+	default:
+		d.idSize, numNames = 1, int(d.uint8())
+	}
+	offsetSize, namesSize := 2, 0
+	if flags&flagFnamesSegUint32 != 0 {
+		offsetSize, namesSize = 4, int(d.uint32())
+	} else {
+		namesSize = int(d.uint16())
+	}
+	var longOffsetSize, numLongNames, longNamesSize int
+	if version == versionMaxFname65535 {
+		longOffsetSize = 4
+		if d.uint16()&flagSecFnamesSegUint16 != 0 {
+			longOffsetSize = 2
+		}
+		numLongNames, longNamesSize = int(d.uint32()), int(d.uint32())
+	}
+	if flags&flagTreeSegUint32 != 0 {
+		d.read(4)
+	} else {
+		d.read(2)
+	}
+	d.uint16() // number of tiny nodes
+
+	d.names = make([]string, 0, numNames+numLongNames)
+	d.fieldNames(numNames, 1, offsetSize, namesSize, 1)
+	d.fieldNames(numLongNames, 2, longOffsetSize, longNamesSize, 2)
+	d.treePos = d.pos
+	return d.node()
+}
+
+// fieldNames reads the hash ids, offsets and names arrays of n field names.
+func (d *decoder) fieldNames(n, hashSize, offsetSize, segSize, lenSize int) {
+	if n == 0 {
+		return
+	}
+	d.read(n * hashSize)
+	offsets := d.read(n * offsetSize)
+	seg := d.read(segSize)
+	for i := 0; i < n; i++ {
+		var off int
+		if offsetSize == 2 {
+			off = int(binary.BigEndian.Uint16(offsets[2*i:]))
+		} else {
+			off = int(binary.BigEndian.Uint32(offsets[4*i:]))
+		}
+		if off+lenSize > len(seg) {
+			panic(errShort)
+		}
+		length := int(seg[off])
+		if lenSize == 2 {
+			length = int(binary.BigEndian.Uint16(seg[off:]))
+		}
+		off += lenSize
+		if off+length > len(seg) {
+			panic(errShort)
+		}
+		d.names = append(d.names, string(seg[off:off+length]))
+	}
+}
+
+// node decodes the node at the current position.
+func (d *decoder) node() (interface{}, error) {
+	typ := d.uint8()
+	if typ&containerMask != 0 {
+		return d.container(typ)
+	}
+	switch typ {
+	case typeNull:
+		return nil, nil
+	case typeTrue:
+		return true, nil
+	case typeFalse:
+		return false, nil
+	case typeDate, typeTimestamp7:
+		return decodeDate(d.read(7)), nil
+	case typeTimestamp:
+		b := d.read(11)
+		return decodeDate(b).Add(time.Duration(binary.BigEndian.Uint32(b[7:]))), nil
+	case typeTimestampTZ:
+		b := d.read(13)
+		t := decodeDate(b).Add(time.Duration(binary.BigEndian.Uint32(b[7:])))
+		offset := (int(b[11])-20)*3600 + (int(b[12])-60)*60
+		return t.In(time.FixedZone("", offset)), nil
+	case typeBinaryFloat:
+		bits := d.uint32()
+		if bits&(1<<31) != 0 {
+			bits &^= 1 << 31
+		} else {
+			bits = ^bits
+		}
+		return float64(math.Float32frombits(bits)), nil
+	case typeBinaryDouble:
+		bits := uint64(d.uint32())<<32 | uint64(d.uint32())
+		if bits&(1<<63) != 0 {
+			bits &^= 1 << 63
+		} else {
+			bits = ^bits
+		}
+		return math.Float64frombits(bits), nil
+	case typeStringUint8:
+		return string(d.read(int(d.uint8()))), nil
+	case typeStringUint16:
+		return string(d.read(int(d.uint16()))), nil
+	case typeStringUint32:
+		return string(d.read(int(d.uint32()))), nil
+	case typeNumberUint8:
+		return decodeNumber(d.read(int(d.uint8()))), nil
+	case typeID:
+		return append([]byte(nil), d.read(int(d.uint8()))...), nil
+	case typeBinaryUint16:
+		return append([]byte(nil), d.read(int(d.uint16()))...), nil
+	case typeBinaryUint32:
+		return append([]byte(nil), d.read(int(d.uint32()))...), nil
+	case typeIntervalDS:
+		b := d.read(11)
+		days := int64(binary.BigEndian.Uint32(b)) - 0x80000000
+		dur := time.Duration(days)*24*time.Hour +
+			time.Duration(int(b[4])-60)*time.Hour +
+			time.Duration(int(b[5])-60)*time.Minute +
+			time.Duration(int(b[6])-60)*time.Second +
+			time.Duration(int64(binary.BigEndian.Uint32(b[7:]))-0x80000000)
+		return dur, nil
+	}
+	switch typ & 0xf0 {
+	case 0x20, 0x60: // number with length in the node type
+		return decodeNumber(d.read(int(typ&0x0f) + 1)), nil
+	case 0x40, 0x50: // integer with length in the node type
+		return decodeNumber(d.read(int(typ & 0x0f))), nil
+	}
+	if typ&0xe0 == 0 { // string with length in the node type
+		return string(d.read(int(typ))), nil
+	}
+	return nil, errors.Wrapf(ErrUnsupported, "node type %#x", typ)
+}
+
+// container decodes an object or array.
+func (d *decoder) container(typ byte) (interface{}, error) {
+	containerOffset := d.pos - d.treePos - 1
+	isObject := typ&containerArray == 0
+	n, shared := d.numChildren(typ)
+	var idsPos, offsetsPos int
+	switch {
+	case shared:
+		// the field ids are shared with an earlier object
+		off := d.offset(typ)
+		offsetsPos = d.pos
+		d.pos = d.treePos + off
+		var sharedTyp byte
+		if sharedTyp = d.uint8(); sharedTyp&containerMask == 0 {
+			return nil, errors.Wrapf(ErrUnsupported, "shared node type %#x", sharedTyp)
+		}
+		n, _ = d.numChildren(sharedTyp)
+		idsPos = d.pos
+	case isObject:
+		idsPos = d.pos
+		offsetsPos = d.pos + n*d.idSize
+	default:
+		offsetsPos = d.pos
+	}
+
+	var m map[string]interface{}
+	var arr []interface{}
+	if isObject {
+		m = make(map[string]interface{}, n)
+	} else {
+		arr = make([]interface{}, n)
+	}
+	for i := 0; i < n; i++ {
+		var name string
+		if isObject {
+			d.pos = idsPos
+			id := d.uintN(d.idSize)
+			idsPos = d.pos
+			if id < 1 || id > len(d.names) {
+				return nil, errors.Errorf("bad field id %d", id)
+			}
+			name = d.names[id-1]
+		}
+		d.pos = offsetsPos
+		off := d.offset(typ)
+		if d.relOffsets {
+			off += containerOffset
+		}
+		offsetsPos = d.pos
+		d.pos = d.treePos + off
+		v, err := d.node()
+		if err != nil {
+			return nil, err
+		}
+		if isObject {
+			m[name] = v
+		} else {
+			arr[i] = v
+		}
+	}
+	if isObject {
+		return m, nil
+	}
+	return arr, nil
+}
+
+func (d *decoder) numChildren(typ byte) (n int, shared bool) {
+	switch typ & containerShared {
+	case 0:
+		return int(d.uint8()), false
+	case containerChildrenU16:
+		return int(d.uint16()), false
+	case containerChildrenU32:
+		return int(d.uint32()), false
+	}
+	return 0, true
+}
+
+func (d *decoder) offset(typ byte) int {
+	if typ&containerOffsetsU32 != 0 {
+		return int(d.uint32())
+	}
+	return int(d.uint16())
+}
+
+func decodeNumber(b []byte) json.Number {
+	return json.Number(num.OCINum(b).String())
+}
+
+func decodeDate(b []byte) time.Time {
+	return time.Date(
+		(int(b[0])-100)*100+int(b[1])-100, time.Month(b[2]), int(b[3]),
+		int(b[4])-1, int(b[5])-1, int(b[6])-1, 0, time.UTC)
+}
@@ -0,0 +1,435 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package oson
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/rana/ora.v4/num"
+)
+
+// Marshal returns the OSON encoding of v.
+func Marshal(v interface{}) ([]byte, error) {
+	x, err := normalize(v)
+	if err != nil {
+		return nil, err
+	}
+	var e encoder
+	flags := uint16(flagInlineLeaf)
+	var hashIDs, offsets, names []byte
+	switch x.(type) {
+	case map[string]interface{}, []interface{}:
+		flags |= flagHashIDUint8 | flagTinyNodesStat
+		if hashIDs, offsets, names, err = e.fieldNames(x); err != nil {
+			return nil, err
+		}
+		if len(e.ids) > math.MaxUint16 {
+			flags |= flagNumFnamesUint32
+		} else if len(e.ids) > math.MaxUint8 {
+			flags |= flagNumFnamesUint16
+		}
+		if len(names) > math.MaxUint16 {
+			flags |= flagFnamesSegUint32
+		}
+	default:
+		flags |= flagIsScalar
+	}
+	if err = e.encode(x); err != nil {
+		return nil, err
+	}
+	if len(e.tree) > math.MaxUint16 {
+		flags |= flagTreeSegUint32
+	}
+
+	buf := make([]byte, 0, 16+len(hashIDs)+len(offsets)+len(names)+len(e.tree))
+	buf = append(buf, magic1, magic2, magic3, versionMaxFname255)
+	buf = appendUint16(buf, flags)
+	if flags&flagIsScalar == 0 {
+		switch {
+		case flags&flagNumFnamesUint32 != 0:
+			buf = appendUint32(buf, uint32(len(e.ids)))
+		case flags&flagNumFnamesUint16 != 0:
+			buf = appendUint16(buf, uint16(len(e.ids)))
+		default:
+			buf = append(buf, byte(len(e.ids)))
+		}
+		if flags&flagFnamesSegUint32 != 0 {
+			buf = appendUint32(buf, uint32(len(names)))
+		} else {
+			buf = appendUint16(buf, uint16(len(names)))
+		}
+	}
+	if flags&flagTreeSegUint32 != 0 {
+		buf = appendUint32(buf, uint32(len(e.tree)))
+	} else {
+		buf = appendUint16(buf, uint16(len(e.tree)))
+	}
+	if flags&flagIsScalar == 0 {
+		buf = appendUint16(buf, 0) // number of tiny nodes
+		buf = append(buf, hashIDs...)
+		buf = append(buf, offsets...)
+		buf = append(buf, names...)
+	}
+	return append(buf, e.tree...), nil
+}
+
+type encoder struct {
+	ids    map[string]uint32
+	idSize int
+	tree   []byte
+}
+
+type fieldName struct {
+	name string
+	hash byte
+}
+
+// fieldNames collects the field names of all objects in x, and assigns
+// their ids, in the order of their hash ids.
+//
+// Returns the hash id, offset and field names arrays of the header.
+func (e *encoder) fieldNames(x interface{}) (hashIDs, offsets, names []byte, err error) {
+	seen := make(map[string]struct{})
+	var walk func(interface{})
+	walk = func(x interface{}) {
+		switch x := x.(type) {
+		case map[string]interface{}:
+			for k, v := range x {
+				seen[k] = struct{}{}
+				walk(v)
+			}
+		case []interface{}:
+			for _, v := range x {
+				walk(v)
+			}
+		}
+	}
+	walk(x)
+
+	fns := make([]fieldName, 0, len(seen))
+	for k := range seen {
+		if len(k) > math.MaxUint8 {
+			return nil, nil, nil, errors.Errorf("field name %.32q... is longer than 255 bytes", k)
+		}
+		fns = append(fns, fieldName{name: k, hash: hashID(k)})
+	}
+	sort.Sort(byHashID(fns))
+
+	e.ids = make(map[string]uint32, len(fns))
+	switch {
+	case len(fns) > math.MaxUint16:
+		e.idSize = 4
+	case len(fns) > math.MaxUint8:
+		e.idSize = 2
+	default:
+		e.idSize = 1
+	}
+	var size int
+	for _, fn := range fns {
+		size += 1 + len(fn.name)
+	}
+	wide := size > math.MaxUint16
+	names = make([]byte, 0, size)
+	for i, fn := range fns {
+		e.ids[fn.name] = uint32(i + 1)
+		hashIDs = append(hashIDs, fn.hash)
+		if wide {
+			offsets = appendUint32(offsets, uint32(len(names)))
+		} else {
+			offsets = appendUint16(offsets, uint16(len(names)))
+		}
+		names = append(names, byte(len(fn.name)))
+		names = append(names, fn.name...)
+	}
+	return hashIDs, offsets, names, nil
+}
+
+type byHashID []fieldName
+
+func (fns byHashID) Len() int      { return len(fns) }
+func (fns byHashID) Swap(i, j int) { fns[i], fns[j] = fns[j], fns[i] }
+func (fns byHashID) Less(i, j int) bool {
+	a, b := fns[i], fns[j]
+	if a.hash != b.hash {
+		return a.hash < b.hash
+	}
+	if len(a.name) != len(b.name) {
+		return len(a.name) < len(b.name)
+	}
+	return a.name < b.name
+}
+
+// hashID returns the low byte of the FNV-1a hash of the field name.
+func hashID(name string) byte {
+	h := uint32(0x811c9dc5)
+	for i := 0; i < len(name); i++ {
+		h = (h ^ uint32(name[i])) * 16777619
+	}
+	return byte(h)
+}
+
+func (e *encoder) encode(x interface{}) error {
+	switch x := x.(type) {
+	case nil:
+		e.tree = append(e.tree, typeNull)
+	case bool:
+		if x {
+			e.tree = append(e.tree, typeTrue)
+		} else {
+			e.tree = append(e.tree, typeFalse)
+		}
+	case string:
+		switch n := len(x); {
+		case n <= math.MaxUint8:
+			e.tree = append(e.tree, typeStringUint8, byte(n))
+		case n <= math.MaxUint16:
+			e.tree = appendUint16(append(e.tree, typeStringUint16), uint16(n))
+		default:
+			e.tree = appendUint32(append(e.tree, typeStringUint32), uint32(n))
+		}
+		e.tree = append(e.tree, x...)
+	case json.Number:
+		s, err := plainDecimal(string(x))
+		if err != nil {
+			return err
+		}
+		var n num.OCINum
+		if err := n.SetString(s); err != nil {
+			return errors.Wrap(err, string(x))
+		}
+		e.tree = append(e.tree, typeNumberUint8, byte(len(n)))
+		e.tree = append(e.tree, n...)
+	case float64:
+		bits := math.Float64bits(x)
+		if bits&(1<<63) == 0 {
+			bits |= 1 << 63
+		} else {
+			bits = ^bits
+		}
+		e.tree = appendUint64(append(e.tree, typeBinaryDouble), bits)
+	case float32:
+		bits := math.Float32bits(x)
+		if bits&(1<<31) == 0 {
+			bits |= 1 << 31
+		} else {
+			bits = ^bits
+		}
+		e.tree = appendUint32(append(e.tree, typeBinaryFloat), bits)
+	case time.Time:
+		x = x.UTC()
+		if x.Nanosecond() == 0 {
+			e.tree = appendDate(append(e.tree, typeTimestamp7), x)
+		} else {
+			e.tree = appendUint32(appendDate(append(e.tree, typeTimestamp), x), uint32(x.Nanosecond()))
+		}
+	case time.Duration:
+		e.tree = append(e.tree, typeIntervalDS)
+		days := x / (24 * time.Hour)
+		x -= days * 24 * time.Hour
+		hours := x / time.Hour
+		x -= hours * time.Hour
+		minutes := x / time.Minute
+		x -= minutes * time.Minute
+		seconds := x / time.Second
+		x -= seconds * time.Second
+		e.tree = appendUint32(e.tree, uint32(int32(days))+0x80000000)
+		e.tree = append(e.tree, byte(hours+60), byte(minutes+60), byte(seconds+60))
+		e.tree = appendUint32(e.tree, uint32(int32(x))+0x80000000)
+	case []byte:
+		if len(x) <= math.MaxUint16 {
+			e.tree = appendUint16(append(e.tree, typeBinaryUint16), uint16(len(x)))
+		} else {
+			e.tree = appendUint32(append(e.tree, typeBinaryUint32), uint32(len(x)))
+		}
+		e.tree = append(e.tree, x...)
+	case []interface{}:
+		e.container(typeArray, len(x))
+		pos := len(e.tree)
+		e.tree = append(e.tree, make([]byte, 4*len(x))...)
+		for i, v := range x {
+			binary.BigEndian.PutUint32(e.tree[pos+4*i:], uint32(len(e.tree)))
+			if err := e.encode(v); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Sort(byFieldID{keys: keys, ids: e.ids})
+		e.container(typeObject, len(x))
+		idPos := len(e.tree)
+		offPos := idPos + e.idSize*len(keys)
+		e.tree = append(e.tree, make([]byte, (e.idSize+4)*len(keys))...)
+		for i, k := range keys {
+			switch id := e.ids[k]; e.idSize {
+			case 1:
+				e.tree[idPos+i] = byte(id)
+			case 2:
+				binary.BigEndian.PutUint16(e.tree[idPos+2*i:], uint16(id))
+			default:
+				binary.BigEndian.PutUint32(e.tree[idPos+4*i:], id)
+			}
+			binary.BigEndian.PutUint32(e.tree[offPos+4*i:], uint32(len(e.tree)))
+			if err := e.encode(x[k]); err != nil {
+				return err
+			}
+		}
+	default:
+		return errors.Errorf("cannot encode %T", x)
+	}
+	return nil
+}
+
+type byFieldID struct {
+	keys []string
+	ids  map[string]uint32
+}
+
+func (s byFieldID) Len() int           { return len(s.keys) }
+func (s byFieldID) Swap(i, j int)      { s.keys[i], s.keys[j] = s.keys[j], s.keys[i] }
+func (s byFieldID) Less(i, j int) bool { return s.ids[s.keys[i]] < s.ids[s.keys[j]] }
+
+// container writes the node type and number of children of an object or array,
+// which always uses uint32 offsets.
+func (e *encoder) container(typ byte, n int) {
+	typ |= containerOffsetsU32
+	switch {
+	case n > math.MaxUint16:
+		e.tree = appendUint32(append(e.tree, typ|containerChildrenU32), uint32(n))
+	case n > math.MaxUint8:
+		e.tree = appendUint16(append(e.tree, typ|containerChildrenU16), uint16(n))
+	default:
+		e.tree = append(e.tree, typ, byte(n))
+	}
+}
+
+// normalize converts v to the generic types handled by the encoder.
+func normalize(v interface{}) (interface{}, error) {
+	switch x := v.(type) {
+	case nil, bool, string, json.Number, float64, float32, time.Time, time.Duration, []byte:
+		return x, nil
+	case int:
+		return json.Number(strconv.FormatInt(int64(x), 10)), nil
+	case int8:
+		return json.Number(strconv.FormatInt(int64(x), 10)), nil
+	case int16:
+		return json.Number(strconv.FormatInt(int64(x), 10)), nil
+	case int32:
+		return json.Number(strconv.FormatInt(int64(x), 10)), nil
+	case int64:
+		return json.Number(strconv.FormatInt(x, 10)), nil
+	case uint:
+		return json.Number(strconv.FormatUint(uint64(x), 10)), nil
+	case uint8:
+		return json.Number(strconv.FormatUint(uint64(x), 10)), nil
+	case uint16:
+		return json.Number(strconv.FormatUint(uint64(x), 10)), nil
+	case uint32:
+		return json.Number(strconv.FormatUint(uint64(x), 10)), nil
+	case uint64:
+		return json.Number(strconv.FormatUint(x, 10)), nil
+	case json.RawMessage:
+		return parseJSON(x)
+	case []interface{}:
+		arr := make([]interface{}, len(x))
+		for i, v := range x {
+			var err error
+			if arr[i], err = normalize(v); err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, v := range x {
+			var err error
+			if m[k], err = normalize(v); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return parseJSON(b)
+}
+
+// plainDecimal returns the number without exponent, as num.OCINum needs it.
+func plainDecimal(s string) (string, error) {
+	i := strings.IndexAny(s, "eE")
+	if i < 0 {
+		return strings.TrimPrefix(s, "+"), nil
+	}
+	exp, err := strconv.Atoi(s[i+1:])
+	if err != nil {
+		return "", errors.Wrap(err, s)
+	}
+	mant := s[:i]
+	var neg string
+	if mant != "" && (mant[0] == '-' || mant[0] == '+') {
+		if mant[0] == '-' {
+			neg = "-"
+		}
+		mant = mant[1:]
+	}
+	point := strings.IndexByte(mant, '.')
+	if point < 0 {
+		point = len(mant)
+	} else {
+		mant = mant[:point] + mant[point+1:]
+	}
+	point += exp
+	switch {
+	case point <= 0:
+		mant = "0." + strings.Repeat("0", -point) + mant
+	case point >= len(mant):
+		mant += strings.Repeat("0", point-len(mant))
+	default:
+		mant = mant[:point] + "." + mant[point:]
+	}
+	if strings.IndexByte(mant, '.') >= 0 {
+		mant = strings.TrimRight(mant, "0")
+		mant = strings.TrimSuffix(mant, ".")
+	}
+	if t := strings.TrimLeft(mant, "0"); t == "" {
+		mant = "0"
+	} else if t[0] == '.' {
+		mant = "0" + t
+	} else {
+		mant = t
+	}
+	return neg + mant, nil
+}
+
+func appendDate(b []byte, t time.Time) []byte {
+	year := t.Year()
+	return append(b,
+		byte(year/100+100), byte(year%100+100),
+		byte(t.Month()), byte(t.Day()),
+		byte(t.Hour()+1), byte(t.Minute()+1), byte(t.Second()+1))
+}
+
+func appendUint16(b []byte, u uint16) []byte {
+	return append(b, byte(u>>8), byte(u))
+}
+
+func appendUint32(b []byte, u uint32) []byte {
+	return append(b, byte(u>>24), byte(u>>16), byte(u>>8), byte(u))
+}
+
+func appendUint64(b []byte, u uint64) []byte {
+	return appendUint32(appendUint32(b, uint32(u>>32)), uint32(u))
+}
@@ -0,0 +1,137 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+// Package oson implements OSON, the binary JSON format of the Oracle JSON
+// data type (Oracle Database 21c and later).
+//
+// Decode returns the generic Go representation of an OSON document:
+// map[string]interface{} for objects, []interface{} for arrays, string,
+// json.Number for NUMBER, float64 for BINARY_DOUBLE and BINARY_FLOAT, bool,
+// nil, time.Time for DATE and TIMESTAMP, time.Duration for INTERVAL DAY TO
+// SECOND and []byte for RAW and ID values.
+//
+// Marshal accepts the same types, plus the other Go numeric types; anything
+// else is converted with encoding/json first.
+package oson
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// header
+const (
+	magic1 = 0xff
+	magic2 = 0x4a // 'J'
+	magic3 = 0x5a // 'Z'
+
+	versionMaxFname255   = 1
+	versionMaxFname65535 = 3
+)
+
+// primary header flags
+const (
+	flagRelOffsetMode      = 0x01
+	flagInlineLeaf         = 0x02
+	flagNumFnamesUint32    = 0x08
+	flagIsScalar           = 0x10
+	flagSecFnamesSegUint16 = 0x100
+	flagHashIDUint8        = 0x0100
+	flagNumFnamesUint16    = 0x0400
+	flagFnamesSegUint32    = 0x0800
+	flagTreeSegUint32      = 0x1000
+	flagTinyNodesStat      = 0x2000
+)
+
+// node types
+const (
+	typeNull             = 0x30
+	typeTrue             = 0x31
+	typeFalse            = 0x32
+	typeStringUint8      = 0x33
+	typeNumberUint8      = 0x34
+	typeBinaryDouble     = 0x36
+	typeStringUint16     = 0x37
+	typeStringUint32     = 0x38
+	typeTimestamp        = 0x39
+	typeBinaryUint16     = 0x3a
+	typeBinaryUint32     = 0x3b
+	typeDate             = 0x3c
+	typeIntervalYM       = 0x3d
+	typeIntervalDS       = 0x3e
+	typeTimestampTZ      = 0x7c
+	typeTimestamp7       = 0x7d
+	typeID               = 0x7e
+	typeBinaryFloat      = 0x7f
+	typeObject           = 0x84
+	typeArray            = 0xc0
+	containerMask        = 0x80
+	containerArray       = 0x40
+	containerOffsetsU32  = 0x20
+	containerChildrenU16 = 0x08
+	containerChildrenU32 = 0x10
+	containerShared      = 0x18
+)
+
+var (
+	// ErrNotOSON is returned by Decode for data without the OSON magic bytes.
+	ErrNotOSON = errors.New("not OSON data")
+	// ErrUnsupported is returned for node types that cannot be handled.
+	ErrUnsupported = errors.New("unsupported OSON node")
+)
+
+// IsOSON reports whether data starts with the OSON magic bytes.
+func IsOSON(data []byte) bool {
+	return len(data) >= 3 && data[0] == magic1 && data[1] == magic2 && data[2] == magic3
+}
+
+// Unmarshal decodes the OSON data into v, as json.Unmarshal would
+// decode the equivalent JSON text.
+func Unmarshal(data []byte, v interface{}) error {
+	x, err := Decode(data)
+	if err != nil {
+		return err
+	}
+	if p, ok := v.(*interface{}); ok {
+		*p = x
+		return nil
+	}
+	b, err := json.Marshal(x)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// ToJSON converts the OSON data to JSON text.
+func ToJSON(data []byte) ([]byte, error) {
+	x, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(x)
+}
+
+// FromJSON converts JSON text to OSON.
+func FromJSON(text []byte) ([]byte, error) {
+	x, err := parseJSON(text)
+	if err != nil {
+		return nil, err
+	}
+	return Marshal(x)
+}
+
+func parseJSON(text []byte) (interface{}, error) {
+	var x interface{}
+	dec := json.NewDecoder(bytes.NewReader(text))
+	dec.UseNumber()
+	if err := dec.Decode(&x); err != nil {
+		return nil, errors.Wrap(err, "parse JSON")
+	}
+	return x, nil
+}
@@ -0,0 +1,177 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package oson
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarshalScalar(t *testing.T) {
+	for i, tc := range []struct {
+		in   interface{}
+		want []byte
+	}{
+		{nil, []byte{0xff, 0x4a, 0x5a, 1, 0, 0x12, 0, 1, typeNull}},
+		{true, []byte{0xff, 0x4a, 0x5a, 1, 0, 0x12, 0, 1, typeTrue}},
+		{"a", []byte{0xff, 0x4a, 0x5a, 1, 0, 0x12, 0, 3, typeStringUint8, 1, 'a'}},
+		{123, []byte{0xff, 0x4a, 0x5a, 1, 0, 0x12, 0, 5, typeNumberUint8, 3, 194, 2, 24}},
+		{json.Number("-5"), []byte{0xff, 0x4a, 0x5a, 1, 0, 0x12, 0, 5, typeNumberUint8, 3, 62, 96, 102}},
+	} {
+		got, err := Marshal(tc.in)
+		if err != nil {
+			t.Errorf("%d. %v: %v", i, tc.in, err)
+			continue
+		}
+		if !bytes.Equal(got, tc.want) {
+			t.Errorf("%d. %v: got % x, wanted % x", i, tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	ts := time.Date(2017, 3, 4, 5, 6, 7, 891000000, time.UTC)
+	long := strings.Repeat("x", 70000)
+	many := make(map[string]interface{}, 300)
+	for i := 0; i < 300; i++ {
+		many[string(rune('a'+i%26))+strings.Repeat("_", i/26)+string(rune('A'+i%7))] = json.Number("1")
+	}
+	for i, in := range []interface{}{
+		nil,
+		false,
+		"",
+		"árvíztűrő tükörfúrógép",
+		long,
+		json.Number("0"),
+		json.Number("-12.3456"),
+		json.Number("123456789012345678901234567890"),
+		3.5,
+		-0.25,
+		ts,
+		ts.Truncate(time.Second),
+		26*time.Hour + 3*time.Minute + 500*time.Millisecond,
+		[]byte{0, 1, 2, 255},
+		[]interface{}{},
+		map[string]interface{}{},
+		[]interface{}{json.Number("1"), "two", nil, true, []interface{}{3.5}},
+		map[string]interface{}{
+			"name":  "Ora",
+			"tags":  []interface{}{"go", "oracle"},
+			"inner": map[string]interface{}{"name": "nested", "n": json.Number("42")},
+			"empty": map[string]interface{}{},
+		},
+		many,
+		[]interface{}{long, long},
+	} {
+		b, err := Marshal(in)
+		if err != nil {
+			t.Errorf("%d. Marshal: %v", i, err)
+			continue
+		}
+		got, err := Decode(b)
+		if err != nil {
+			t.Errorf("%d. Decode(% x): %v", i, b, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, in) {
+			t.Errorf("%d. got %#v, wanted %#v", i, got, in)
+		}
+	}
+}
+
+func TestMarshalStruct(t *testing.T) {
+	type inner struct {
+		A int    `json:"a"`
+		B string `json:"b,omitempty"`
+	}
+	in := struct {
+		Name  string  `json:"name"`
+		Items []inner `json:"items"`
+	}{Name: "x", Items: []inner{{A: 1, B: "b"}, {A: 2}}}
+	b, err := Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		Name  string  `json:"name"`
+		Items []inner `json:"items"`
+	}
+	if err = Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("got %#v, wanted %#v", out, in)
+	}
+
+	text, err := ToJSON(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"items":[{"a":1,"b":"b"},{"a":2}],"name":"x"}`; string(text) != want {
+		t.Errorf("got %s, wanted %s", text, want)
+	}
+	b2, err := FromJSON(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, b2) {
+		t.Errorf("FromJSON: got % x, wanted % x", b2, b)
+	}
+}
+
+func TestDecodeInline(t *testing.T) {
+	// an array with uint16 offsets, an inline string, an inline integer
+	// and an inline decimal, in relative offset mode
+	data := []byte{0xff, 0x4a, 0x5a, 1, 0x21, flagRelOffsetMode | flagInlineLeaf,
+		0,    // field names
+		0, 0, // field names segment size
+		0, 17, // tree segment size
+		0, 0, // tiny nodes
+		typeArray, 3, 0, 8, 0, 11, 0, 14, // offsets relative to the array
+		2, 'h', 'i',
+		0x42, 194, 2,
+		0x21, 193, 13,
+	}
+	got, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []interface{}{"hi", json.Number("100"), json.Number("12")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, wanted %#v", got, want)
+	}
+
+	if _, err = Decode(data[:len(data)-2]); err != errShort {
+		t.Errorf("truncated: got %v, wanted %v", err, errShort)
+	}
+	if _, err = Decode([]byte(`{"a":1}`)); err != ErrNotOSON {
+		t.Errorf("text: got %v, wanted %v", err, ErrNotOSON)
+	}
+}
+
+func TestPlainDecimal(t *testing.T) {
+	for _, tc := range [][2]string{
+		{"12", "12"},
+		{"-1.5", "-1.5"},
+		{"1e3", "1000"},
+		{"1.25E+2", "125"},
+		{"-1.5e-3", "-0.0015"},
+		{"12.5e-1", "1.25"},
+		{"0e5", "0"},
+	} {
+		got, err := plainDecimal(tc[0])
+		if err != nil {
+			t.Errorf("%s: %v", tc[0], err)
+			continue
+		}
+		if got != tc[1] {
+			t.Errorf("%s: got %s, wanted %s", tc[0], got, tc[1])
+		}
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+*/
+import "C"
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gopkg.in/rana/ora.v4/lex"
+)
+
+// PlsMap binds a Go map to a PL/SQL associative array.
+//
+// A map[int32]T is bound to an INDEX BY PLS_INTEGER table, which may be
+// sparse; a map[string]T is bound to an INDEX BY VARCHAR2 table.
+// T may be int64, int32, float64 or string.
+//
+// Type is the name of the PL/SQL collection type, as PACKAGE.TYPE or
+// SCHEMA.PACKAGE.TYPE, of unquoted or double-quoted identifiers. Value is the map, or a pointer to the map for an
+// IN OUT parameter, which is replaced with the returned collection.
+// Cap is the maximum number of elements that can be returned; the length
+// of the map is used if Cap is less.
+//
+// OCI cannot bind such tables directly, so the statement, which must be a
+// PL/SQL block, is executed inside a generated block, that copies the map
+// into a local variable of Type, and back. The other params are bound
+// as with ExeP.
+type PlsMap struct {
+	Type  string
+	Value interface{}
+	Cap   int
+}
+
+var (
+	plsMapElemTypes = map[reflect.Type]string{
+		reflect.TypeOf(int64(0)):   "NUMBER",
+		reflect.TypeOf(int32(0)):   "NUMBER",
+		reflect.TypeOf(float64(0)): "NUMBER",
+		reflect.TypeOf(""):         "VARCHAR2(32767)",
+	}
+	int32Type  = reflect.TypeOf(int32(0))
+	stringType = reflect.TypeOf("")
+)
+
+// plsMapBnd holds the dense key and value slices of a PlsMap.
+type plsMapBnd struct {
+	dest       reflect.Value // the map, if it is to be returned
+	keys, vals reflect.Value // pointers to the key and value slices
+}
+
+// hasPlsMap reports whether any of the params is a PlsMap.
+func hasPlsMap(params []interface{}) bool {
+	for _, p := range params {
+		if _, v := nameAndValue(p); isPlsMap(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func isPlsMap(v interface{}) bool {
+	_, ok := v.(PlsMap)
+	return ok
+}
+
+// exePlsMap executes the PL/SQL block wrapped in a generated block,
+// which converts the PlsMap params from and to dense arrays.
+func (stmt *Stmt) exePlsMap(ctx context.Context, params []interface{}) (rowsAffected uint64, err error) {
+	stmt.RLock()
+	stmtType, sql := stmt.stmtType, stmt.sql
+	stmt.RUnlock()
+	if stmtType != C.OCI_STMT_BEGIN && stmtType != C.OCI_STMT_DECLARE {
+		return 0, errNew("PlsMap can only be bound in a PL/SQL block")
+	}
+	bindNames, _, duplicates, err := stmt.getBindInfo()
+	if err != nil {
+		return 0, err
+	}
+	names := make([]string, 0, len(bindNames))
+	for i, nm := range bindNames {
+		if !duplicates[i] {
+			names = append(names, nm)
+		}
+	}
+
+	var decl, pre, post bytes.Buffer
+	var mapParams, otherParams []interface{}
+	var maps []plsMapBnd
+	for n, p := range params {
+		name, v := nameAndValue(p)
+		pm, ok := v.(PlsMap)
+		if !ok {
+			otherParams = append(otherParams, p)
+			continue
+		}
+		if name == "" {
+			if n >= len(names) {
+				return 0, errF("no placeholder for parameter %d", n+1)
+			}
+			name = names[n]
+		}
+		mb, keyType, valType, err := newPlsMapBnd(pm)
+		if err != nil {
+			return 0, errF("%s: %v", name, err)
+		}
+		i := len(maps) + 1
+		m, k, val, idx := fmt.Sprintf("ora_m%d", i), fmt.Sprintf("ora_mk%d", i), fmt.Sprintf("ora_mv%d", i), fmt.Sprintf("ora_mi%d", i)
+		sql = replacePlaceholder(sql, name, m)
+		fmt.Fprintf(&decl, "  TYPE %[1]s_t IS TABLE OF %[2]s INDEX BY PLS_INTEGER;\n  TYPE %[3]s_t IS TABLE OF %[4]s INDEX BY PLS_INTEGER;\n", k, keyType, val, valType)
+		fmt.Fprintf(&decl, "  %[1]s %[1]s_t := :%[1]s;\n  %[2]s %[2]s_t := :%[2]s;\n  %[3]s %[4]s;\n", k, val, m, pm.Type)
+		fmt.Fprintf(&pre, "  FOR i IN 1 .. %[1]s.COUNT LOOP\n    %[3]s(%[1]s(i)) := %[2]s(i);\n  END LOOP;\n", k, val, m)
+		if mb.dest.IsValid() {
+			fmt.Fprintf(&decl, "  %s %s;\n", idx, keyType)
+			fmt.Fprintf(&post, "  %[1]s.DELETE;\n  %[2]s.DELETE;\n  %[4]s := %[3]s.FIRST;\n", k, val, m, idx)
+			fmt.Fprintf(&post, "  WHILE %[4]s IS NOT NULL LOOP\n    %[1]s(%[1]s.COUNT + 1) := %[4]s;\n    %[2]s(%[2]s.COUNT + 1) := %[3]s(%[4]s);\n    %[4]s := %[3]s.NEXT(%[4]s);\n  END LOOP;\n", k, val, m, idx)
+			fmt.Fprintf(&post, "  :%[1]s := %[1]s;\n  :%[2]s := %[2]s;\n", k, val)
+		}
+		maps = append(maps, mb)
+		mapParams = append(mapParams, mb.keys.Interface(), mb.vals.Interface())
+	}
+
+	wrapped := "DECLARE\n" + decl.String() + "BEGIN\n" + pre.String() + sql + "\n" + post.String() + "END;"
+	stmt.logF(_drv.Cfg().Log.Stmt.Exe, "PlsMap wrapper:\n%s", wrapped)
+	wStmt, err := stmt.ses.Prep(wrapped)
+	if err != nil {
+		return 0, err
+	}
+	defer wStmt.Close()
+	wStmt.SetCfg(stmt.Cfg())
+	// the generated placeholders precede the original ones
+	if rowsAffected, _, err = wStmt.exeC(ctx, append(mapParams, otherParams...), true); err != nil {
+		return rowsAffected, err
+	}
+	for _, mb := range maps {
+		mb.setDest()
+	}
+	return rowsAffected, nil
+}
+
+// newPlsMapBnd checks the map of pm, and converts it to key and value slices.
+func newPlsMapBnd(pm PlsMap) (mb plsMapBnd, keyType, valType string, err error) {
+	if pm.Type == "" {
+		return mb, "", "", errNew("PlsMap.Type is required")
+	}
+	if !isPlsTypeName(pm.Type) {
+		return mb, "", "", errF("PlsMap.Type %q is not a [SCHEMA.]PACKAGE.TYPE name", pm.Type)
+	}
+	rv := reflect.ValueOf(pm.Value)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return mb, "", "", errNew("PlsMap.Value is a nil pointer")
+		}
+		rv = rv.Elem()
+		mb.dest = rv
+	}
+	if rv.Kind() != reflect.Map {
+		return mb, "", "", errF("PlsMap.Value must be a map, not %T", pm.Value)
+	}
+	var kt reflect.Type
+	switch rv.Type().Key().Kind() {
+	case reflect.Int32:
+		kt, keyType = int32Type, "PLS_INTEGER"
+	case reflect.String:
+		kt, keyType = stringType, "VARCHAR2(32767)"
+	default:
+		return mb, "", "", errF("unsupported map key type %s", rv.Type().Key())
+	}
+	vt := rv.Type().Elem()
+	if valType = plsMapElemTypes[vt]; valType == "" {
+		return mb, "", "", errF("unsupported map value type %s", vt)
+	}
+
+	n := rv.Len()
+	capacity := n
+	if mb.dest.IsValid() && pm.Cap > capacity {
+		capacity = pm.Cap
+	}
+	keys := reflect.MakeSlice(reflect.SliceOf(kt), 0, capacity)
+	vals := reflect.MakeSlice(reflect.SliceOf(vt), 0, capacity)
+	for _, k := range rv.MapKeys() {
+		keys = reflect.Append(keys, k.Convert(kt))
+		vals = reflect.Append(vals, rv.MapIndex(k))
+	}
+	mb.keys, mb.vals = reflect.New(keys.Type()), reflect.New(vals.Type())
+	mb.keys.Elem().Set(keys)
+	mb.vals.Elem().Set(vals)
+	return mb, keyType, valType, nil
+}
+
+// setDest replaces the destination map with the returned keys and values.
+func (mb plsMapBnd) setDest() {
+	if !mb.dest.IsValid() {
+		return
+	}
+	keys, vals := mb.keys.Elem(), mb.vals.Elem()
+	kt := mb.dest.Type().Key()
+	m := reflect.MakeMap(mb.dest.Type())
+	for i := 0; i < keys.Len() && i < vals.Len(); i++ {
+		m.SetMapIndex(keys.Index(i).Convert(kt), vals.Index(i))
+	}
+	mb.dest.Set(m)
+}
+
+// replacePlaceholder replaces the :name placeholders in the (PL/)SQL text
+// with repl, skipping string literals, quoted identifiers and comments.
+// The placeholder name is compared case-insensitively.
+func replacePlaceholder(sql, name, repl string) string {
+	var buf bytes.Buffer
+	// on error, the last token holds the rest of the text
+	tokens, _ := lex.Tokenize(sql)
+	for _, tok := range tokens {
+		if tok.Type == lex.Bind && strings.EqualFold(tok.Text[1:], name) {
+			buf.WriteString(repl)
+		} else {
+			buf.WriteString(tok.Text)
+		}
+	}
+	return buf.String()
+}
+
+// isPlsTypeName reports whether s is a [SCHEMA.]PACKAGE.TYPE name, of
+// unquoted or double-quoted identifiers, so it can be put into the generated
+// block as is.
+func isPlsTypeName(s string) bool {
+	parts := 0
+	for {
+		if strings.HasPrefix(s, `"`) {
+			i := strings.IndexByte(s[1:], '"')
+			if i <= 0 || strings.IndexByte(s[1:i+1], 0) >= 0 {
+				return false
+			}
+			s = s[i+2:]
+		} else {
+			i := strings.IndexFunc(s, func(r rune) bool {
+				return !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '$' || r == '#')
+			})
+			if i < 0 {
+				i = len(s)
+			}
+			if r, _ := utf8.DecodeRuneInString(s); i == 0 || !unicode.IsLetter(r) {
+				return false
+			}
+			s = s[i:]
+		}
+		parts++
+		if s == "" {
+			return parts == 2 || parts == 3
+		}
+		if s[0] != '.' {
+			return false
+		}
+		s = s[1:]
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import "testing"
+
+func TestReplacePlaceholder(t *testing.T) {
+	for i, tc := range []struct {
+		sql, name, want string
+	}{
+		{"BEGIN p(:1, :10); END;", "1", "BEGIN p(ora_m1, :10); END;"},
+		{"BEGIN p(:Tab); :x := :TAB; END;", "TAB", "BEGIN p(ora_m1); :x := ora_m1; END;"},
+		{"BEGIN p(':tab', \":tab\"); END;", "TAB", "BEGIN p(':tab', \":tab\"); END;"},
+		{"BEGIN -- :tab\n p(:tab); /* :tab */ END;", "TAB", "BEGIN -- :tab\n p(ora_m1); /* :tab */ END;"},
+		{"BEGIN p(:tab2); END;", "TAB", "BEGIN p(:tab2); END;"},
+		{"BEGIN x := 'unterminated :tab", "TAB", "BEGIN x := 'unterminated :tab"},
+	} {
+		if got := replacePlaceholder(tc.sql, tc.name, "ora_m1"); got != tc.want {
+			t.Errorf("%d. got %q, wanted %q", i, got, tc.want)
+		}
+	}
+}
+
+func TestIsPlsTypeName(t *testing.T) {
+	for name, want := range map[string]bool{
+		"PKG.TAB_T":                    true,
+		"scott.pkg.tab_t":              true,
+		`"Pkg"."tab t"`:                true,
+		`SCOTT."Pkg".T$#1`:             true,
+		"TAB_T":                        false,
+		"A.B.C.D":                      false,
+		"PKG.":                         false,
+		"PKG..T":                       false,
+		`PKG."T`:                       false,
+		`PKG.""`:                       false,
+		"PKG.1T":                       false,
+		"PKG.T; EXECUTE IMMEDIATE 'x'": false,
+		"PKG.T := NULL; --":            false,
+	} {
+		if got := isPlsTypeName(name); got != want {
+			t.Errorf("%q: got %t, wanted %t", name, got, want)
+		}
+	}
+}
@@ -5,6 +5,7 @@
 package ora
 
 import (
+	"context"
 	"io"
 	"strings"
 	"sync"
@@ -183,6 +184,40 @@ func (p *Pool) Get() (ses *Ses, err error) {
 	return ses, nil
 }
 
+// GetContext is like Get, but returns an error with ctx.Err() as its Cause
+// when ctx is done before a session is got.
+//
+// Opening a new session goes on in the background then, and the session is
+// put back to the pool.
+func (p *Pool) GetContext(ctx context.Context) (*Ses, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if ctx.Done() == nil {
+		return p.Get()
+	}
+	type sesErr struct {
+		ses *Ses
+		err error
+	}
+	ch := make(chan sesErr, 1)
+	go func() {
+		ses, err := p.Get()
+		ch <- sesErr{ses: ses, err: err}
+	}()
+	select {
+	case se := <-ch:
+		return se.ses, se.err
+	case <-ctx.Done():
+		go func() {
+			if se := <-ch; se.ses != nil {
+				se.ses.Close() // puts it back to the pool
+			}
+		}()
+		return nil, &ctxError{ctxErr: ctx.Err(), err: er("no session got from the Pool")}
+	}
+}
+
 // Put the session back to the session pool.
 // Ensure that on ses Close (eviction), srv is put back on the idle pool.
 func (p *Pool) Put(ses *Ses) {
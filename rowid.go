@@ -0,0 +1,146 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Rowid represents a nullable Oracle ROWID or UROWID, in its text form.
+//
+// The Rid GoColumnType defines ROWID and UROWID select-list columns as Rowid;
+// Rowid and *Rowid parameters are bound as their text, which the server
+// converts to a ROWID.
+//
+// The ROWIDs of heap tables are extended ROWIDs, which can be decoded with
+// Extended. The UROWIDs of index-organized and foreign tables start with '*'
+// and are opaque.
+type Rowid struct {
+	IsNull bool
+	Value  string
+}
+
+// ExtendedRowid is the decoded form of an extended ROWID.
+type ExtendedRowid struct {
+	Object uint32 // data object number
+	File   uint16 // relative file number
+	Block  uint32 // block number in the file
+	Row    uint16 // row slot in the block
+}
+
+// extendedRowidLen is the length of the text of an extended ROWID:
+// OOOOOOFFFBBBBBBRRR.
+const extendedRowidLen = 18
+
+const rowidDigits = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// rowidDigitValues maps the base-64 digits of ROWIDs to their value, others
+// to -1.
+var rowidDigitValues = func() (v [256]int8) {
+	for i := range v {
+		v[i] = -1
+	}
+	for i := 0; i < len(rowidDigits); i++ {
+		v[rowidDigits[i]] = int8(i)
+	}
+	return v
+}()
+
+// String returns the text of the Rowid, or "" for a null Rowid.
+func (this Rowid) String() string {
+	if this.IsNull {
+		return ""
+	}
+	return this.Value
+}
+
+// Equals returns true when the receiver and specified Rowid are both null,
+// or when the receiver and specified Rowid are both not null and Values are equal.
+func (this Rowid) Equals(other Rowid) bool {
+	return (this.IsNull && other.IsNull) ||
+		(this.IsNull == other.IsNull && this.Value == other.Value)
+}
+
+// IsExtended reports whether the Rowid is an extended ROWID, which can be
+// decoded.
+func (this Rowid) IsExtended() bool {
+	_, err := this.Extended()
+	return err == nil
+}
+
+// Extended decodes an extended ROWID into the data object number, relative
+// file number, block number and row slot, as DBMS_ROWID does.
+//
+// Returns an error for null Rowids, and UROWIDs other than extended ROWIDs.
+func (this Rowid) Extended() (ExtendedRowid, error) {
+	var x ExtendedRowid
+	if this.IsNull {
+		return x, errNew("Rowid is null")
+	}
+	s := this.Value
+	if len(s) != extendedRowidLen {
+		return x, errF("%q is not an extended ROWID", s)
+	}
+	var parts [4]uint64
+	for i, w := range [4]int{6, 3, 6, 3} {
+		for _, c := range []byte(s[:w]) {
+			d := rowidDigitValues[c]
+			if d < 0 {
+				return x, errF("%q is not an extended ROWID", this.Value)
+			}
+			parts[i] = parts[i]<<6 | uint64(d)
+		}
+		s = s[w:]
+	}
+	// 32 bits object, 10 bits file, 22 bits block and 16 bits row
+	if parts[0] > 1<<32-1 || parts[1] > 1<<10-1 || parts[2] > 1<<22-1 || parts[3] > 1<<16-1 {
+		return x, errF("%q is not an extended ROWID", this.Value)
+	}
+	x.Object, x.File = uint32(parts[0]), uint16(parts[1])
+	x.Block, x.Row = uint32(parts[2]), uint16(parts[3])
+	return x, nil
+}
+
+// Rowid encodes the extended ROWID, as DBMS_ROWID.ROWID_CREATE does.
+//
+// With Row set to 0 and 65535 (the lowest and highest row slot), such Rowids
+// are the bounds of the ROWID range of blocks, for splitting a table.
+func (this ExtendedRowid) Rowid() Rowid {
+	var b [extendedRowidLen]byte
+	i := len(b)
+	for _, part := range [4]struct {
+		v uint64
+		w int
+	}{
+		{uint64(this.Row), 3}, {uint64(this.Block), 6},
+		{uint64(this.File), 3}, {uint64(this.Object), 6},
+	} {
+		for j := 0; j < part.w; j++ {
+			i--
+			b[i] = rowidDigits[part.v&63]
+			part.v >>= 6
+		}
+	}
+	return Rowid{Value: string(b[:])}
+}
+
+var _ = (json.Marshaler)(Rowid{})
+var _ = (json.Unmarshaler)((*Rowid)(nil))
+
+func (this Rowid) MarshalJSON() ([]byte, error) {
+	if this.IsNull {
+		return []byte("null"), nil
+	}
+	return json.Marshal(this.Value)
+}
+func (this *Rowid) UnmarshalJSON(p []byte) error {
+	if bytes.Equal(p, []byte("null")) || bytes.Equal(p, []byte(`""`)) {
+		this.IsNull = true
+		return nil
+	}
+	this.IsNull = false
+	return json.Unmarshal(p, &this.Value)
+}
@@ -0,0 +1,44 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import "testing"
+
+func TestRowidExtended(t *testing.T) {
+	for i, tc := range []struct {
+		rowid string
+		want  ExtendedRowid
+	}{
+		{"AAAAAAAAAAAAAAAAAA", ExtendedRowid{}},
+		{"AAAR3qAAEAAAACHAAA", ExtendedRowid{Object: 73194, File: 4, Block: 135}},
+		{"AAAR3qAAEAAAACHAAB", ExtendedRowid{Object: 73194, File: 4, Block: 135, Row: 1}},
+		{"D/////AP/AAP///P//", ExtendedRowid{Object: 1<<32 - 1, File: 1<<10 - 1, Block: 1<<22 - 1, Row: 1<<16 - 1}},
+	} {
+		got, err := Rowid{Value: tc.rowid}.Extended()
+		if err != nil {
+			t.Errorf("%d. %q: %v", i, tc.rowid, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%d. %q: got %+v, wanted %+v", i, tc.rowid, got, tc.want)
+		}
+		if back := got.Rowid(); back.Value != tc.rowid {
+			t.Errorf("%d. %+v: got %q, wanted %q", i, got, back.Value, tc.rowid)
+		}
+	}
+
+	for i, rowid := range []Rowid{
+		{IsNull: true},
+		{Value: ""},
+		{Value: "AAAR3qAAEAAAACHAA"},
+		{Value: "AAAR3qAAEAAAACH.AA"},
+		{Value: "*BAMAAJwCwQL+"},
+		{Value: "EAAAAAAAAAAAAAAAAA"},
+	} {
+		if rowid.IsExtended() {
+			t.Errorf("%d. %+v is not an extended ROWID", i, rowid)
+		}
+	}
+}
@@ -10,10 +10,13 @@ package ora
 import "C"
 import (
 	"container/list"
+	"context"
 	"fmt"
 	"io"
+	"reflect"
 	"sync"
 	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
@@ -97,15 +100,59 @@ type Rset struct {
 	fetchLen        int
 	finished        bool
 
+	// the number of rows of the next fetch, at most fetchLen; growing with
+	// StmtCfg.SetFetchGrowth, from the time per row of the last fetch
+	fetchRows int
+	growing   bool
+	rowTime   time.Duration
+
+	// the position of the first fetched row, and of the current row
+	// (0 before the first row); scrollable for Rset.Prev etc.
+	scrollable    bool
+	winStart, pos int64
+
+	// the context of the query, limiting the fetches
+	ctx context.Context
+
+	// the Batch reused by NextBatch
+	batch *Batch
+
+	// whether the current row, at offset cur, was read by NextNoRow, so
+	// its buffers are kept for the typed accessors
+	noRow bool
+	cur   int64
+
 	sysNamer
 }
 
+// Column describes a select-list column of a Rset.
 type Column struct {
-	Name      string
-	Type      C.ub2
-	Length    uint32
+	Name string
+	// Type is the OCI data type code (SQLT_*); Length is the size in bytes.
+	Type   C.ub2
+	Length uint32
+	// Precision and Scale of a NUMBER; the leading field and the fractional
+	// seconds precisions of a TIMESTAMP or INTERVAL.
 	Precision C.sb2
 	Scale     C.sb1
+
+	// TypeName is the SQL type of the column, with its length, precision
+	// and scale, such as "VARCHAR2(20 CHAR)", "NUMBER(10,2)" or
+	// "TIMESTAMP(6) WITH TIME ZONE".
+	TypeName string
+	// Nullable reports whether the column may be NULL.
+	Nullable bool
+	// CharUsed reports whether the length of a character column is in
+	// characters (CHAR length semantics); CharLength is that length.
+	CharUsed   bool
+	CharLength uint32
+	// CharsetID and CharsetForm (SQLCS_IMPLICIT=1, SQLCS_NCHAR=2) of a
+	// character column.
+	CharsetID, CharsetForm int
+	// ObjectSchema and ObjectName name the type of an OBJECT or REF column.
+	ObjectSchema, ObjectName string
+	// GoType is the type of the values of the column in Rset.Row.
+	GoType reflect.Type
 }
 
 // Err returns the last error of the reesult set.
@@ -210,7 +257,9 @@ func (rset *Rset) close() (err error) {
 	rset.ocistmt = nil
 	rset.defs = nil
 	rset.Row = nil
+	rset.noRow = false
 	rset.Columns = nil
+	rset.batch = nil
 	// do not clear error in case of autoClose when error exists
 	// clear error when rset in initialized
 	//rset.err = nil
@@ -225,6 +274,11 @@ func (rset *Rset) close() (err error) {
 
 // beginRow allocates a handle for each column and fetches one row.
 func (rset *Rset) beginRow() (err error) {
+	return rset.beginRowC(rset.ctx)
+}
+
+// beginRowC is like beginRow, with the fetch limited by ctx.
+func (rset *Rset) beginRowC(ctx context.Context) (err error) {
 	rset.log(_drv.Cfg().Log.Rset.BeginRow)
 	rset.Lock()
 	defer rset.Unlock()
@@ -235,6 +289,7 @@ func (rset *Rset) beginRow() (err error) {
 	rset.logF(_drv.Cfg().Log.Rset.BeginRow, "fetched=%d offset=%d finished=%t", fetched, offset, finished)
 	if fetched > 0 && fetched > offset {
 		atomic.AddInt32(&rset.index, 1)
+		rset.pos = rset.winStart + offset
 		return nil
 	}
 	if finished {
@@ -250,7 +305,6 @@ func (rset *Rset) beginRow() (err error) {
 	if rset.env == nil {
 		return errF("Rset env is closed")
 	}
-	env := rset.env
 	for _, define := range rset.defs {
 		//rset.logF(_drv.Cfg().Log.Rset.BeginRow, "defs[%d]=%#v", i, define)
 		if define == nil {
@@ -263,22 +317,16 @@ func (rset *Rset) beginRow() (err error) {
 	}
 
 	rset.finished = false
-	// fetch rset.fetchLen rows
-	r := C.OCIStmtFetch2(
-		rset.ocistmt,         //OCIStmt     *stmthp,
-		env.ocierr,           //OCIError    *errhp,
-		C.ub4(rset.fetchLen), //ub4         nrows,
-		C.OCI_FETCH_NEXT,     //ub2         orientation,
-		C.sb4(0),             //sb4         fetchOffset,
-		C.OCI_DEFAULT)        //ub4         mode );
-	if r == C.OCI_ERROR {
-		err := env.ociError()
+	// fetch rset.fetchRows rows
+	nrows := rset.fetchRows
+	start := time.Now()
+	r, err := rset.fetch(ctx, nrows, C.OCI_FETCH_NEXT, 0)
+	if err != nil {
 		return err
 	} else if r == C.OCI_NO_DATA {
 		rset.log(_drv.Cfg().Log.Rset.BeginRow, "OCI_NO_DATA")
 		rset.finished = true
-		fetchLen := rset.fetchLen
-		if fetchLen == 1 {
+		if nrows == 1 {
 			// return io.EOF to conform with database/sql/driver
 			return io.EOF
 		}
@@ -293,8 +341,22 @@ func (rset *Rset) beginRow() (err error) {
 		return err
 	}
 
+	rset.winStart += rset.fetched
 	rset.fetched = int64(rowsFetched)
 	rset.offset = 0
+	if rset.growing {
+		if err := rset.grow(int(rowsFetched), time.Since(start)); err != nil {
+			return err
+		}
+	}
+	if rset.scrollable {
+		var current C.ub4
+		if err := rset.attr(unsafe.Pointer(&current), 4, C.OCI_ATTR_CURRENT_POSITION); err != nil {
+			return err
+		}
+		rset.winStart = int64(current) - rset.fetched + 1
+	}
+	rset.pos = rset.winStart
 	err = nil
 	if rset.fetched == 0 {
 		rset.finished = true
@@ -306,6 +368,97 @@ func (rset *Rset) beginRow() (err error) {
 	return err
 }
 
+// fetch fetches at most nrows rows with OCIStmtFetch2, limited by ctx
+// and StmtCfg.CallTimeout.
+func (rset *Rset) fetch(ctx context.Context, nrows int, orientation C.ub2, offset int64) (C.sword, error) {
+	call, err := rset.stmt.ses.startCall(ctx, rset.stmt.Cfg().callTimeout)
+	if err != nil {
+		return C.OCI_ERROR, err
+	}
+	var pieceDefs []*ociPieceDef
+	for _, def := range rset.defs {
+		if p, ok := def.(pieceDef); ok {
+			pd := p.pieceDef()
+			pd.reset()
+			pieceDefs = append(pieceDefs, pd)
+		}
+	}
+	r := C.OCIStmtFetch2(
+		rset.ocistmt,    //OCIStmt     *stmthp,
+		rset.env.ocierr, //OCIError    *errhp,
+		C.ub4(nrows),    //ub4         nrows,
+		orientation,     //ub2         orientation,
+		C.sb4(offset),   //sb4         fetchOffset,
+		C.OCI_DEFAULT)   //ub4         mode );
+	if r == C.OCI_NEED_DATA {
+		r, err = rset.fetchPieces(pieceDefs, nrows, orientation, offset)
+	}
+	if r == C.OCI_ERROR && err == nil {
+		err = rset.env.ociError()
+	}
+	return r, call.end(err)
+}
+
+// fetchPieces provides the buffers of the LONG and LONG RAW columns for each
+// piece of their values, as long as OCIStmtFetch2 needs data.
+func (rset *Rset) fetchPieces(pieceDefs []*ociPieceDef, nrows int, orientation C.ub2, offset int64) (C.sword, error) {
+	r := C.sword(C.OCI_NEED_DATA)
+	for r == C.OCI_NEED_DATA {
+		var hndl unsafe.Pointer
+		var htype, iter, idx C.ub4
+		var inout, piece C.ub1
+		if C.OCIStmtGetPieceInfo(
+			rset.ocistmt,    //OCIStmt       *stmtp,
+			rset.env.ocierr, //OCIError      *errhp,
+			&hndl,           //void          **hndlpp,
+			&htype,          //ub4           *typep,
+			&inout,          //ub1           *in_outp,
+			&iter,           //ub4           *iterp,
+			&idx,            //ub4           *idxp,
+			&piece,          //ub1           *piecep );
+		) == C.OCI_ERROR {
+			return C.OCI_ERROR, rset.env.ociError("OCIStmtGetPieceInfo")
+		}
+		var pd *ociPieceDef
+		for _, d := range pieceDefs {
+			if unsafe.Pointer(d.ocidef) == hndl {
+				pd = d
+				break
+			}
+		}
+		if pd == nil {
+			return C.OCI_ERROR, errF("unknown define %p for piecewise fetch", hndl)
+		}
+		pd.next(int(iter), piece)
+		if C.OCIStmtSetPieceInfo(
+			hndl,                    //void          *hndlp,
+			C.OCI_HTYPE_DEFINE,      //ub4           type,
+			rset.env.ocierr,         //OCIError      *errhp,
+			pd.buf,                  //const void    *bufp,
+			pd.alenp,                //ub4           *alenp,
+			piece,                   //ub1           piece,
+			unsafe.Pointer(pd.indp), //const void  *indp,
+			pd.rcodep,               //ub2           *rcodep );
+		) == C.OCI_ERROR {
+			return C.OCI_ERROR, rset.env.ociError("OCIStmtSetPieceInfo")
+		}
+		r = C.OCIStmtFetch2(
+			rset.ocistmt,    //OCIStmt     *stmthp,
+			rset.env.ocierr, //OCIError    *errhp,
+			C.ub4(nrows),    //ub4         nrows,
+			orientation,     //ub2         orientation,
+			C.sb4(offset),   //sb4         fetchOffset,
+			C.OCI_DEFAULT)   //ub4         mode );
+		if r != C.OCI_ERROR {
+			pd.got()
+		}
+	}
+	if r == C.OCI_ERROR {
+		return r, rset.env.ociError()
+	}
+	return r, nil
+}
+
 // endRow deallocates a handle for each column.
 func (rset *Rset) endRow() {
 	rset.log(_drv.Cfg().Log.Rset.EndRow)
@@ -314,7 +467,9 @@ func (rset *Rset) endRow() {
 	done := rset.finished && !(rset.fetched > 0 && rset.fetched > rset.offset)
 	defs := rset.defs
 	rset.offset++
-	if !done {
+	if !done || rset.scrollable || rset.noRow {
+		// a scrollable Rset keeps its buffers until closed, and the row of
+		// NextNoRow until the next call
 		return
 	}
 	for _, define := range defs {
@@ -332,6 +487,9 @@ func (rset *Rset) Exhaust() {
 	if !rset.IsOpen() {
 		return
 	}
+	rset.Lock()
+	rset.noRow = false
+	rset.Unlock()
 	for {
 		err := rset.beginRow()
 		rset.endRow()
@@ -349,61 +507,132 @@ func (rset *Rset) Exhaust() {
 //
 // When Next returns false check Rset.Err() for any error that may have occured.
 func (rset *Rset) Next() bool {
+	return rset.next(rset.ctx, false)
+}
+
+// NextContext is like Next, but a fetch is broken when ctx is done, and then
+// Rset.Err() has ctx.Err() as its Cause.
+//
+// ctx replaces the context of QryContext for this call.
+func (rset *Rset) NextContext(ctx context.Context) bool {
+	return rset.next(ctx, false)
+}
+
+// NextNoRow is like Next, but does not populate Rset.Row: the values of the
+// row are read from the define buffers by the typed accessors (Int64,
+// Float64, String, Bytes, Time and IsNull), and by Scan, so only the values
+// asked for are converted, and allocate only if their type needs to.
+//
+// Rset.Row is set to nil. The values of the row are available until the next
+// call of Next, NextNoRow or NextBatch.
+func (rset *Rset) NextNoRow() bool {
+	return rset.next(rset.ctx, true)
+}
+
+// NextNoRowContext is like NextNoRow, but a fetch is broken when ctx is done.
+func (rset *Rset) NextNoRowContext(ctx context.Context) bool {
+	return rset.next(ctx, true)
+}
+
+// next loads the next row, fetching with ctx if needed; without noRow, it
+// populates Row.
+func (rset *Rset) next(ctx context.Context, noRow bool) bool {
 	rset.log(_drv.Cfg().Log.Rset.Next)
-	erase := func(err error) {
-		rset.Lock()
-		rset.err = err
-		rset.Row = nil
-		autoClose := rset.autoClose
-		rset.Unlock()
-		// closing the Stmt will close this (and all) Rsets under it!
-		if !autoClose {
-			rset.close()
-		} else {
-			rset.RLock()
-			stmt := rset.stmt
-			rset.RUnlock()
-			rset.closeWithRemove()
-			stmt.Close()
-		}
-	}
+	erase := rset.erase
 
 	if err := rset.checkIsOpen(); err != nil {
 		erase(err)
 		return false
 	}
-	err := rset.beginRow()
+	rset.Lock()
+	rset.noRow = false
+	rset.Unlock()
+	err := rset.beginRowC(ctx)
 	defer rset.endRow()
 	rset.logF(_drv.Cfg().Log.Rset.Next, "beginRow=%v", err)
 	if err != nil {
 		// io.EOF means no more data; return nil err
 		if err == io.EOF {
 			err = nil
+			rset.RLock()
+			scrollable := rset.scrollable
+			rset.RUnlock()
+			if scrollable {
+				// stay open, after the last row
+				rset.Lock()
+				rset.Row = nil
+				rset.pos = rset.winStart + rset.fetched
+				rset.Unlock()
+				return false
+			}
 		}
 		erase(err)
 		return false
 	}
-	// populate column values
+	if noRow {
+		rset.Lock()
+		rset.Row = nil
+		rset.noRow, rset.cur = true, rset.offset
+		rset.Unlock()
+		return true
+	}
+	rset.RLock()
+	offset := rset.offset
+	rset.RUnlock()
+	if err = rset.loadRow(offset); err != nil {
+		erase(err)
+		return false
+	}
+	//rset.logF(_drv.Cfg().Log.Rset.Next, "Row=%#v", rset.Row)
+	return true
+}
+
+// erase records err (nil at the end of the rows), and closes the Rset, and
+// its Stmt if autoClose.
+func (rset *Rset) erase(err error) {
+	rset.Lock()
+	rset.err = err
+	rset.Row = nil
+	rset.noRow = false
+	autoClose := rset.autoClose
+	rset.Unlock()
+	// closing the Stmt will close this (and all) Rsets under it!
+	if !autoClose {
+		rset.close()
+	} else {
+		rset.RLock()
+		stmt := rset.stmt
+		rset.RUnlock()
+		rset.closeWithRemove()
+		stmt.Close()
+	}
+}
+
+// loadRow populates Row with the column values of the fetched row at offset.
+func (rset *Rset) loadRow(offset int64) error {
 	rset.RLock()
 	Row := rset.Row
 	defs := rset.defs
-	offset := rset.offset
 	rset.RUnlock()
+	if cap(Row) < len(defs) {
+		Row = make([]interface{}, len(defs))
+	} else {
+		Row = Row[:len(defs)]
+	}
 	for n, define := range defs {
 		value, err := define.value(int(offset))
 		//rset.logF(_drv.Cfg().Log.Rset.Next, "value[%d]=%v (%v)", n, value, err)
 		if err != nil {
-			erase(err)
-			return false
+			return err
 		}
 		Row[n] = value
 	}
 	rset.Lock()
 	rset.defs = defs
 	rset.Row = Row
+	rset.noRow = false
 	rset.Unlock()
-	//rset.logF(_drv.Cfg().Log.Rset.Next, "Row=%#v", rset.Row)
-	return true
+	return nil
 }
 
 // NextRow attempts to load a row from the Oracle buffer and return the row.
@@ -444,6 +673,7 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 	rset.offset = 0
 	rset.fetched = 0
 	rset.finished = false
+	rset.winStart, rset.pos = 1, 0
 	rset.err = nil
 	defs, Columns, Row := rset.defs, rset.Columns, rset.Row
 	rset.defs, rset.Columns, rset.Row = nil, nil, nil
@@ -493,9 +723,10 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 
 	// create parameters for each select-list column
 	type paramS struct {
-		columnSize uint32
-		typeCode   C.ub2
-		param      *C.OCIParam
+		columnSize  uint32
+		typeCode    C.ub2
+		charsetForm C.ub1
+		param       *C.OCIParam
 	}
 	params := make([]paramS, len(defs))
 	defer func() {
@@ -532,6 +763,14 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 		if err != nil {
 			return err
 		}
+		// Get charset form: SQLCS_NCHAR for NCHAR, NVARCHAR2 and NCLOB
+		switch params[n].typeCode {
+		case C.SQLT_CHR, C.SQLT_AFC, C.SQLT_CLOB:
+			err = rset.paramAttr(ocipar, unsafe.Pointer(&params[n].charsetForm), nil, C.OCI_ATTR_CHARSET_FORM)
+			if err != nil {
+				return err
+			}
+		}
 		// Get column name
 		var columnName *C.char
 		var colSize C.ub4
@@ -540,10 +779,18 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 			return err
 		}
 
+		name, err := env.decode(C.GoBytes(unsafe.Pointer(columnName), C.int(colSize)))
+		if err != nil {
+			return err
+		}
 		Columns[n] = Column{
-			Name:   C.GoStringN(columnName, C.int(colSize)),
-			Type:   params[n].typeCode,
-			Length: params[n].columnSize,
+			Name:        name,
+			Type:        params[n].typeCode,
+			Length:      params[n].columnSize,
+			CharsetForm: int(params[n].charsetForm),
+		}
+		if err = rset.describeColumn(ocipar, &Columns[n]); err != nil {
+			return err
 		}
 
 		rset.logF(logCfg.Rset.OpenDefs, "%d. %s/%d", n+1, Columns[n].Name, params[n].typeCode)
@@ -563,7 +810,7 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 		for _, param := range params {
 			switch param.typeCode {
 			// These can consume a lot of memory.
-			case C.SQLT_LNG, C.SQLT_BFILE, C.SQLT_BLOB, C.SQLT_CLOB, C.SQLT_LBI:
+			case C.SQLT_LNG, C.SQLT_BFILE, C.SQLT_BLOB, C.SQLT_CLOB, C.SQLT_LBI, C.SQLT_JSON:
 				fetchLen = lobFetchLen
 				break Loop
 			}
@@ -572,7 +819,12 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 	//rset.logF(true, "fetchLen=%d", fetchLen)
 
 	rset.defs, rset.Columns, rset.Row = defs, Columns, Row
-	rset.fetchLen = fetchLen
+	rset.fetchLen, rset.fetchRows, rset.growing = fetchLen, fetchLen, false
+	if cfg.adaptiveFetch {
+		if err = rset.adaptFetch(cfg); err != nil {
+			return err
+		}
+	}
 
 	//rset.logF(logCfg.Rset.Open, "cfg=%#v", cfg)
 	stmt.RLock()
@@ -586,20 +838,7 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 		switch ociTypeCode {
 		case C.SQLT_NUM, C.SQLT_INT: // TimesTen may return an SQLT_INT
 			// NUMBER
-			// Get precision
-			var precision C.sb2
-			err = rset.paramAttr(ocipar, unsafe.Pointer(&precision), nil, C.OCI_ATTR_PRECISION)
-			if err != nil {
-				return err
-			}
-			// Get scale (the number of decimal places)
-			var scale C.sb1
-			err = rset.paramAttr(ocipar, unsafe.Pointer(&scale), nil, C.OCI_ATTR_SCALE)
-			if err != nil {
-				return err
-			}
-			rset.Columns[n].Precision = precision
-			rset.Columns[n].Scale = scale
+			precision, scale := rset.Columns[n].Precision, rset.Columns[n].Scale
 			if gcts == nil || n >= len(gcts) || gcts[n] == D {
 				gct = cfg.numericColumnType(int(precision), int(scale))
 			} else {
@@ -704,7 +943,7 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 				}
 				gct = gcts[n]
 			}
-			defs[n], err = rset.defineString(n, columnSize, gct, false)
+			defs[n], err = rset.defineString(n, columnSize, gct, false, params[n].charsetForm)
 			if err != nil {
 				return err
 			}
@@ -740,7 +979,7 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 					}
 				case S, OraS:
 					// Interpret single char as string
-					defs[n], err = rset.defineString(n, columnSize, gct, true)
+					defs[n], err = rset.defineString(n, columnSize, gct, true, params[n].charsetForm)
 					if err != nil {
 						return err
 					}
@@ -756,7 +995,7 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 					}
 					gct = gcts[n]
 				}
-				defs[n], err = rset.defineString(n, columnSize, gct, true)
+				defs[n], err = rset.defineString(n, columnSize, gct, true, params[n].charsetForm)
 				if err != nil {
 					return err
 				}
@@ -773,8 +1012,10 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 				gct = gcts[n]
 			}
 
-			// longBufferSize: Use a moderate default buffer size; 2GB max buffer may not be feasible on all clients
-			defs[n], err = rset.defineString(n, stmt.Cfg().longBufferSize, gct, false)
+			// fetched piecewise, in pieces of longBufferSize
+			def := rset.getDef(defIdxLong).(*defLong)
+			defs[n] = def
+			err = def.define(n+1, cfg.longBufferSize, gct == OraS, rset)
 			if err != nil {
 				return err
 			}
@@ -782,8 +1023,11 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 			// CLOB, NCLOB
 			if gcts == nil || n >= len(gcts) || gcts[n] == D {
 				gct = cfg.clob
-			} else if gcts[n] == L {
-				gct = L
+				if cfg.jsonLobs && rset.isJSONColumn(ocipar) {
+					gct = cfg.json
+				}
+			} else if gcts[n] == L || gcts[n] == Loc || gcts[n] == J || gcts[n] == JV {
+				gct = gcts[n]
 			} else {
 				err = checkStringColumn(gcts[n])
 				if err != nil {
@@ -802,8 +1046,11 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 			// BLOB
 			if gcts == nil || n >= len(gcts) || gcts[n] == D {
 				gct = cfg.blob
-			} else if gcts[n] == L {
-				gct = L
+				if cfg.jsonLobs && rset.isJSONColumn(ocipar) {
+					gct = cfg.json
+				}
+			} else if gcts[n] == L || gcts[n] == Loc || gcts[n] == J || gcts[n] == JV {
+				gct = gcts[n]
 			} else {
 				err = checkBinColumn(gcts[n])
 				if err != nil {
@@ -882,11 +1129,20 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 			if err != nil {
 				return err
 			}
-		case C.SQLT_RDD:
+		case C.SQLT_RDD, sqltUrowid:
 			// ROWID, UROWID
+			if gcts == nil || n >= len(gcts) || gcts[n] == D {
+				gct = cfg.rowid
+			} else {
+				err = checkRowidColumn(gcts[n])
+				if err != nil {
+					return err
+				}
+				gct = gcts[n]
+			}
 			def := rset.getDef(defIdxRowid).(*defRowid)
 			defs[n] = def
-			err = def.define(n+1, rset)
+			err = def.define(n+1, int(columnSize), gct, rset)
 			if err != nil {
 				return err
 			}
@@ -897,22 +1153,125 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 			if err != nil {
 				return err
 			}
+		case C.SQLT_JSON:
+			// JSON
+			if gcts == nil || n >= len(gcts) || gcts[n] == D {
+				gct = cfg.json
+			} else {
+				err = checkJSONColumn(gcts[n])
+				if err != nil {
+					return err
+				}
+				gct = gcts[n]
+			}
+			def := rset.getDef(defIdxJSON).(*defJSON)
+			defs[n] = def
+			err = def.define(n+1, gct, rset)
+			if err != nil {
+				return err
+			}
+		case C.SQLT_NTY:
+			// OBJECT, VARRAY, nested TABLE
+			typ, err := stmt.ses.describeType(rset.Columns[n].ObjectSchema, rset.Columns[n].ObjectName)
+			if err != nil {
+				return err
+			}
+			def := rset.getDef(defIdxObject).(*defObject)
+			defs[n] = def
+			err = def.define(n+1, typ, rset)
+			if err != nil {
+				return err
+			}
 		default:
 			return errF("unsupported select-list column type (ociTypeCode: %v)", ociTypeCode)
 		}
+		switch defs[n].(type) {
+		case *defIntervalYM:
+			rset.Columns[n].GoType = reflect.TypeOf(IntervalYM{})
+		case *defIntervalDS:
+			rset.Columns[n].GoType = reflect.TypeOf(IntervalDS{})
+		case *defBfile:
+			rset.Columns[n].GoType = reflect.TypeOf(Bfile{})
+		case *defRset:
+			rset.Columns[n].GoType = reflect.TypeOf((*Rset)(nil))
+		case *defObject:
+			rset.Columns[n].GoType = reflect.TypeOf((*Object)(nil))
+		default:
+			rset.Columns[n].GoType = gctGoType(gct)
+		}
 	}
 
 	return nil
 }
 
-func (rset *Rset) defineString(n int, columnSize uint32, gct GoColumnType, rTrim bool) (def, error) {
+// describeColumn sets the attributes of the select-list column c, described
+// by ocipar, beyond its name, type and size.
+func (rset *Rset) describeColumn(ocipar *C.OCIParam, c *Column) error {
+	var isNull C.ub1
+	if err := rset.paramAttr(ocipar, unsafe.Pointer(&isNull), nil, C.OCI_ATTR_IS_NULL); err != nil {
+		return err
+	}
+	c.Nullable = isNull != 0
+	switch c.Type {
+	case C.SQLT_NUM, C.SQLT_INT:
+		// Get precision
+		if err := rset.paramAttr(ocipar, unsafe.Pointer(&c.Precision), nil, C.OCI_ATTR_PRECISION); err != nil {
+			return err
+		}
+		// Get scale (the number of decimal places)
+		if err := rset.paramAttr(ocipar, unsafe.Pointer(&c.Scale), nil, C.OCI_ATTR_SCALE); err != nil {
+			return err
+		}
+	case C.SQLT_TIMESTAMP, C.SQLT_TIMESTAMP_TZ, C.SQLT_TIMESTAMP_LTZ, C.SQLT_INTERVAL_YM, C.SQLT_INTERVAL_DS:
+		var lfPrecision, fsPrecision C.ub1
+		if c.Type == C.SQLT_INTERVAL_YM || c.Type == C.SQLT_INTERVAL_DS {
+			if err := rset.paramAttr(ocipar, unsafe.Pointer(&lfPrecision), nil, C.OCI_ATTR_LFPRECISION); err != nil {
+				return err
+			}
+		}
+		if c.Type != C.SQLT_INTERVAL_YM {
+			if err := rset.paramAttr(ocipar, unsafe.Pointer(&fsPrecision), nil, C.OCI_ATTR_FSPRECISION); err != nil {
+				return err
+			}
+		}
+		c.Precision, c.Scale = C.sb2(lfPrecision), C.sb1(fsPrecision)
+	case C.SQLT_CHR, C.SQLT_AFC, C.SQLT_CLOB, C.SQLT_LNG:
+		var charUsed C.ub1
+		var charSize, charsetID C.ub2
+		if err := rset.paramAttr(ocipar, unsafe.Pointer(&charsetID), nil, C.OCI_ATTR_CHARSET_ID); err != nil {
+			return err
+		}
+		c.CharsetID = int(charsetID)
+		if c.Type == C.SQLT_CHR || c.Type == C.SQLT_AFC {
+			if err := rset.paramAttr(ocipar, unsafe.Pointer(&charUsed), nil, C.OCI_ATTR_CHAR_USED); err != nil {
+				return err
+			}
+			if err := rset.paramAttr(ocipar, unsafe.Pointer(&charSize), nil, C.OCI_ATTR_CHAR_SIZE); err != nil {
+				return err
+			}
+			c.CharUsed, c.CharLength = charUsed != 0, uint32(charSize)
+		}
+	case C.SQLT_NTY, C.SQLT_REF:
+		var err error
+		if c.ObjectSchema, err = rset.env.paramString(unsafe.Pointer(ocipar), C.OCI_ATTR_SCHEMA_NAME); err != nil {
+			return err
+		}
+		if c.ObjectName, err = rset.env.paramString(unsafe.Pointer(ocipar), C.OCI_ATTR_TYPE_NAME); err != nil {
+			return err
+		}
+	}
+	c.TypeName = columnTypeName(*c)
+	return nil
+}
+
+func (rset *Rset) defineString(n int, columnSize uint32, gct GoColumnType, rTrim bool, charsetForm C.ub1) (def, error) {
 	isNullable := false
 	if gct == OraS {
 		isNullable = true
 	}
 	rTrim = rTrim && rset.stmt.Cfg().RTrimChar
 	D := rset.getDef(defIdxString).(*defString)
-	return D, D.define(n+1, int(columnSize), isNullable, rTrim, rset)
+	return D, D.define(n+1, int(columnSize), isNullable, rTrim, charsetForm == C.SQLCS_NCHAR, rset)
 }
 
 func (rset *Rset) defineNumeric(n int, gct GoColumnType) (def, error) {
@@ -976,6 +1335,9 @@ func (rset *Rset) defineNumeric(n int, gct GoColumnType) (def, error) {
 		nullable = true
 	case S:
 		D = rset.getDef(defIdxNumString).(*defNumString)
+	case BigI, BigF, BigR:
+		def := rset.getDef(defIdxBigNum).(*defBigNum)
+		return def, def.define(n+1, gct, rset)
 	}
 	return D, D.(interface {
 		define(int, bool, *Rset) error
@@ -1001,6 +1363,19 @@ func (rset *Rset) paramAttr(ocipar *C.OCIParam, attrup unsafe.Pointer, attrSizep
 	return nil
 }
 
+// isJSONColumn reports whether the CLOB or BLOB column has an IS JSON check
+// constraint. Older clients cannot tell, so this is false for them.
+func (rset *Rset) isJSONColumn(ocipar *C.OCIParam) bool {
+	if C.HAS_JSON_COL == 0 {
+		return false
+	}
+	var isJSON C.ub1
+	if err := rset.paramAttr(ocipar, unsafe.Pointer(&isJSON), nil, C.OCI_ATTR_JSON_COL); err != nil {
+		return false
+	}
+	return isJSON != 0
+}
+
 // attr gets an attribute from the statement handle.
 func (rset *Rset) attr(attrup unsafe.Pointer, attrSize C.ub4, attrType C.ub4) error {
 	env := rset.env
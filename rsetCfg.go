@@ -31,6 +31,10 @@ type RsetCfg struct {
 	blob           GoColumnType
 	raw            GoColumnType
 	longRaw        GoColumnType
+	json           GoColumnType
+	jsonLobs       bool
+	rowid          GoColumnType
+	structMode     StructMode
 
 	// TrueRune is rune a Go bool true value from SQL select-list character column.
 	//
@@ -65,6 +69,8 @@ func NewRsetCfg() RsetCfg {
 	c.blob = Bin
 	c.raw = Bin
 	c.longRaw = Bin
+	c.json = J
+	c.rowid = S
 
 	c.TrueRune = '1'
 	return c
@@ -75,7 +81,7 @@ func NewRsetCfg() RsetCfg {
 //
 // Valid values are I64, I32, I16, I8, U64, U32, U16, U8, F64, F32, OraI64,
 // OraI32, OraI16, OraI8, OraU64, OraU32, OraU16, OraU8, OraF64, OraF32,
-// N, OraN.
+// N, OraN, BigI, BigF, BigR.
 //
 // Returns an error if a non-numeric GoColumnType is specified.
 func (c RsetCfg) SetNumberInt(gct GoColumnType) RsetCfg {
@@ -108,7 +114,7 @@ func (c RsetCfg) NumberInt() GoColumnType {
 //
 // Valid values are I64, I32, I16, I8, U64, U32, U16, U8, F64, F32, OraI64,
 // OraI32, OraI16, OraI8, OraU64, OraU32, OraU16, OraU8, OraF64, OraF32,
-// N, OraN.
+// N, OraN, BigI, BigF, BigR.
 //
 // Returns an error if a non-numeric GoColumnType is specified.
 func (c RsetCfg) SetNumberBigInt(gct GoColumnType) RsetCfg {
@@ -141,7 +147,7 @@ func (c RsetCfg) NumberBigInt() GoColumnType {
 //
 // Valid values are I64, I32, I16, I8, U64, U32, U16, U8, F64, F32, OraI64,
 // OraI32, OraI16, OraI8, OraU64, OraU32, OraU16, OraU8, OraF64, OraF32,
-// N, OraN.
+// N, OraN, BigI, BigF, BigR.
 //
 // Returns an error if a non-numeric GoColumnType is specified.
 func (c RsetCfg) SetNumberFloat(gct GoColumnType) RsetCfg {
@@ -174,7 +180,7 @@ func (c RsetCfg) NumberFloat() GoColumnType {
 //
 // Valid values are I64, I32, I16, I8, U64, U32, U16, U8, F64, F32, OraI64,
 // OraI32, OraI16, OraI8, OraU64, OraU32, OraU16, OraU8, OraF64, OraF32,
-// N, OraN.
+// N, OraN, BigI, BigF, BigR.
 //
 // Returns an error if a non-numeric GoColumnType is specified.
 func (c RsetCfg) SetNumberBigFloat(gct GoColumnType) RsetCfg {
@@ -207,7 +213,7 @@ func (c RsetCfg) NumberBigFloat() GoColumnType {
 //
 // Valid values are I64, I32, I16, I8, U64, U32, U16, U8, F64, F32, OraI64,
 // OraI32, OraI16, OraI8, OraU64, OraU32, OraU16, OraU8, OraF64, OraF32,
-// N, OraN.
+// N, OraN, BigI, BigF, BigR.
 //
 // Returns an error if a non-numeric GoColumnType is specified.
 func (c RsetCfg) SetBinaryDouble(gct GoColumnType) RsetCfg {
@@ -275,7 +281,7 @@ func (c RsetCfg) BinaryFloat() GoColumnType {
 //
 // Valid values are I64, I32, I16, I8, U64, U32, U16, U8, F64, F32, OraI64,
 // OraI32, OraI16, OraI8, OraU64, OraU32, OraU16, OraU8, OraF64, OraF32,
-// N, OraN.
+// N, OraN, BigI, BigF, BigR.
 //
 // Returns an error if a non-numeric GoColumnType is specified.
 func (c RsetCfg) SetFloat(gct GoColumnType) RsetCfg {
@@ -678,6 +684,93 @@ func (c RsetCfg) LongRaw() GoColumnType {
 	return c.longRaw
 }
 
+// SetJSON sets a GoColumnType associated to an Oracle select-list
+// JSON column, and CLOB and BLOB columns with an IS JSON check
+// constraint if JSONLobs is set.
+//
+// Valid values are J, JV, S and OraS.
+//
+// Returns an error if a non-JSON GoColumnType is specified.
+func (c RsetCfg) SetJSON(gct GoColumnType) RsetCfg {
+	if err := checkJSONColumn(gct); err != nil {
+		if c.Err == nil {
+			c.Err = err
+		}
+		return c
+	}
+	c.json = gct
+	return c
+}
+
+// JSON returns a GoColumnType associated to an Oracle select-list
+// JSON column, and CLOB and BLOB columns with an IS JSON check
+// constraint if JSONLobs is set.
+//
+// The default is J.
+func (c RsetCfg) JSON() GoColumnType {
+	return c.json
+}
+
+// SetJSONLobs sets whether the CLOB and BLOB select-list columns with an
+// IS JSON check constraint are associated to the JSON GoColumnType, instead
+// of the Clob and Blob ones.
+func (c RsetCfg) SetJSONLobs(jsonLobs bool) RsetCfg {
+	c.jsonLobs = jsonLobs
+	return c
+}
+
+// JSONLobs returns whether the CLOB and BLOB select-list columns with an
+// IS JSON check constraint are associated to the JSON GoColumnType.
+//
+// The default is false.
+//
+// The IS JSON check constraint is reported by Oracle 19c and newer clients;
+// with older clients these columns are returned as any other CLOB or BLOB
+// column.
+func (c RsetCfg) JSONLobs() bool {
+	return c.jsonLobs
+}
+
+// SetRowid sets a GoColumnType associated to an Oracle select-list
+// ROWID or UROWID column.
+//
+// Valid values are S, OraS and Rid.
+//
+// Returns an error if a non-ROWID GoColumnType is specified.
+func (c RsetCfg) SetRowid(gct GoColumnType) RsetCfg {
+	if err := checkRowidColumn(gct); err != nil {
+		if c.Err == nil {
+			c.Err = err
+		}
+		return c
+	}
+	c.rowid = gct
+	return c
+}
+
+// Rowid returns a GoColumnType associated to an Oracle select-list
+// ROWID or UROWID column.
+//
+// The default is S.
+func (c RsetCfg) Rowid() GoColumnType {
+	return c.rowid
+}
+
+// SetStructMode sets how Rset.ScanStruct and Ses.QryStructs report the
+// columns without a struct field, and the struct fields without a column.
+func (c RsetCfg) SetStructMode(mode StructMode) RsetCfg {
+	c.structMode = mode
+	return c
+}
+
+// StructMode returns how Rset.ScanStruct and Ses.QryStructs report the
+// columns without a struct field, and the struct fields without a column.
+//
+// The default is StructLax.
+func (c RsetCfg) StructMode() StructMode {
+	return c.structMode
+}
+
 // numericColumnType returns the GoColumnType for the NUMBER/INTEGER
 // column, based on precision and scale.
 //
@@ -0,0 +1,145 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"time"
+	"unsafe"
+)
+
+// DefaultFetchMemory is the default memory budget of the fetch array of a
+// result set with StmtCfg.SetAdaptiveFetch.
+const DefaultFetchMemory = 4 << 20 // 4,194,304
+
+// minFetchRows is the length of the first fetch with StmtCfg.SetFetchGrowth,
+// if the execution prefetched no rows.
+const minFetchRows = 16
+
+// fetchWidth returns the estimated size in bytes of a row of col in the
+// fetch array and the OCI buffers, with the indicator and the length.
+func fetchWidth(col Column, cfg StmtCfg) int {
+	const ind = 2 + 4 // null indicator and length
+	switch col.Type {
+	case C.SQLT_CHR, C.SQLT_AFC:
+		// as defString, which doubles the size of national columns
+		n := int(col.Length)
+		if col.CharsetForm == C.SQLCS_NCHAR {
+			n *= 2
+		}
+		if n == 0 {
+			n = 2
+		}
+		return ind + n
+	case C.SQLT_NUM, C.SQLT_INT, C.SQLT_IBDOUBLE, C.SQLT_IBFLOAT:
+		return ind + int(C.sizeof_OCINumber)
+	case C.SQLT_DAT:
+		return ind + 7
+	case C.SQLT_BIN, C.SQLT_RDD, sqltUrowid:
+		return ind + int(col.Length)
+	case C.SQLT_LNG, C.SQLT_LBI:
+		// the pieces of the value
+		size := cfg.longBufferSize
+		if col.Type == C.SQLT_LBI {
+			size = cfg.longRawBufferSize
+		}
+		if size > lobChunkSize {
+			size = lobChunkSize
+		}
+		return ind + int(size)
+	case C.SQLT_CLOB, C.SQLT_BLOB, C.SQLT_FILE, C.SQLT_JSON:
+		// the locator, and the data prefetched with it
+		return ind + int(unsafe.Sizeof(uintptr(0))) + lobChunkSize
+	}
+	// timestamps, intervals, cursors and objects: a descriptor or handle
+	return ind + 64
+}
+
+// adaptiveFetchLen returns the number of rows of width bytes that fit into
+// budget bytes, between 1 and MaxFetchLen.
+func adaptiveFetchLen(width, budget int) int {
+	if width <= 0 {
+		return MaxFetchLen
+	}
+	n := budget / width
+	if n < 1 {
+		return 1
+	}
+	if n > MaxFetchLen {
+		return MaxFetchLen
+	}
+	return n
+}
+
+// adaptFetch sets the fetch array length of rset from the widths of its
+// columns and StmtCfg.FetchMemory, and the prefetch of its statement handle
+// to match. The caller holds the lock; the columns are described.
+func (rset *Rset) adaptFetch(cfg StmtCfg) error {
+	var width int
+	for _, col := range rset.Columns {
+		width += fetchWidth(col, cfg)
+	}
+	budget := cfg.FetchMemory()
+	rset.fetchLen = adaptiveFetchLen(width, budget)
+	rset.fetchRows = rset.fetchLen
+	rset.growing, rset.rowTime = false, -1
+	if cfg.fetchGrowth && !rset.scrollable {
+		// the first fetch reads the rows prefetched by the execution
+		n := int(cfg.prefetchRowCount)
+		if n < minFetchRows {
+			n = minFetchRows
+		}
+		if n < rset.fetchLen {
+			rset.fetchRows, rset.growing = n, true
+		}
+	}
+	rset.logF(_drv.Cfg().Log.Rset.Open, "width=%d fetchLen=%d fetchRows=%d",
+		width, rset.fetchLen, rset.fetchRows)
+
+	mem := C.ub4(budget)
+	if err := rset.env.setAttr(unsafe.Pointer(rset.ocistmt), C.OCI_HTYPE_STMT,
+		unsafe.Pointer(&mem), 4, C.OCI_ATTR_PREFETCH_MEMORY); err != nil {
+		return err
+	}
+	return rset.setPrefetchRows(rset.fetchRows)
+}
+
+// setPrefetchRows sets the number of rows prefetched by a round trip.
+func (rset *Rset) setPrefetchRows(n int) error {
+	rows := C.ub4(n)
+	return rset.env.setAttr(unsafe.Pointer(rset.ocistmt), C.OCI_HTYPE_STMT,
+		unsafe.Pointer(&rows), 4, C.OCI_ATTR_PREFETCH_ROWS)
+}
+
+// grow doubles the length of the fetches of rset with StmtCfg.SetFetchGrowth,
+// as long as the fetches get faster per row: the last one fetched rows rows
+// in d. The caller holds the lock.
+func (rset *Rset) grow(rows int, d time.Duration) error {
+	if rows < rset.fetchRows {
+		// the last fetch
+		rset.growing = false
+		return nil
+	}
+	perRow := d / time.Duration(rows)
+	switch {
+	case rset.rowTime < 0:
+		// the first fetch may be served by the prefetch of the execution,
+		// without a round trip
+		rset.rowTime = 0
+	case rset.rowTime > 0 && perRow > rset.rowTime:
+		rset.growing = false
+		return nil
+	default:
+		rset.rowTime = perRow
+	}
+	if rset.fetchRows *= 2; rset.fetchRows >= rset.fetchLen {
+		rset.fetchRows, rset.growing = rset.fetchLen, false
+	}
+	return rset.setPrefetchRows(rset.fetchRows)
+}
@@ -0,0 +1,62 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import (
+	"strconv"
+	"testing"
+)
+
+// TestAdaptiveFetchLen tests adaptiveFetchLen.
+func TestAdaptiveFetchLen(t *testing.T) {
+	for i, tc := range []struct {
+		width, budget, want int
+	}{
+		{28, DefaultFetchMemory, MaxFetchLen},
+		{8006, DefaultFetchMemory, 523},
+		{4006 + 1<<20, DefaultFetchMemory, 3},
+		{16 << 20, DefaultFetchMemory, 1},
+		{0, DefaultFetchMemory, MaxFetchLen},
+	} {
+		if got := adaptiveFetchLen(tc.width, tc.budget); got != tc.want {
+			t.Errorf("%d. %d/%d: got %d, wanted %d", i, tc.budget, tc.width, got, tc.want)
+		}
+	}
+}
+
+// TestFetchWidth tests fetchWidth. The test files cannot use cgo, so the
+// data type codes are the values of oci.h.
+func TestFetchWidth(t *testing.T) {
+	const (
+		sqltChr, sqltAfc, sqltNum = 1, 96, 2
+		sqltLng, sqltLbi          = 8, 24
+		sqltClob, sqltBlob        = 112, 113
+		sqlcsNchar                = 2
+	)
+	cfg := NewStmtCfg()
+	small := cfg.SetLongBufferSize(4000).SetLongRawBufferSize(2000)
+	lob := 6 + strconv.IntSize/8 + lobChunkSize
+	for i, tc := range []struct {
+		col  Column
+		cfg  StmtCfg
+		want int
+	}{
+		{Column{Type: sqltChr, Length: 20}, cfg, 26},
+		{Column{Type: sqltAfc, Length: 20}, cfg, 26},
+		{Column{Type: sqltChr, Length: 20, CharsetForm: sqlcsNchar}, cfg, 46},
+		{Column{Type: sqltChr}, cfg, 8},
+		{Column{Type: sqltNum, Precision: 10}, cfg, 28},
+		{Column{Type: sqltClob}, cfg, lob},
+		{Column{Type: sqltBlob}, cfg, lob},
+		{Column{Type: sqltLng}, cfg, 6 + lobChunkSize},
+		{Column{Type: sqltLbi}, cfg, 6 + lobChunkSize},
+		{Column{Type: sqltLng}, small, 4006},
+		{Column{Type: sqltLbi}, small, 2006},
+	} {
+		if got := fetchWidth(tc.col, tc.cfg); got != tc.want {
+			t.Errorf("%d. %d: got %d, wanted %d", i, tc.col.Type, got, tc.want)
+		}
+	}
+}
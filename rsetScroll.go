@@ -0,0 +1,188 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+*/
+import "C"
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// Position returns the 1-based position of the current row: 0 before the
+// first row, and the number of rows + 1 after the last row.
+func (rset *Rset) Position() int {
+	rset.RLock()
+	defer rset.RUnlock()
+	return int(rset.pos)
+}
+
+// Prev moves to the previous row of a scrollable Rset, and loads it into Row.
+// False is returned, and Row is set to nil, before the first row.
+//
+// The Stmt has to be executed with StmtCfg.SetScrollable(true).
+func (rset *Rset) Prev() bool {
+	rset.RLock()
+	pos := rset.pos
+	rset.RUnlock()
+	return rset.scrollTo(pos-1, true)
+}
+
+// First moves to the first row of a scrollable Rset, and loads it into Row.
+func (rset *Rset) First() bool {
+	return rset.scrollTo(1, false)
+}
+
+// Last moves to the last row of a scrollable Rset, and loads it into Row.
+func (rset *Rset) Last() bool {
+	if !rset.checkScrollable() {
+		return false
+	}
+	rset.Lock()
+	if err := rset.fetchScroll(C.OCI_FETCH_LAST, 0, 1); err != nil {
+		rset.err, rset.Row = err, nil
+		rset.Unlock()
+		return false
+	}
+	rset.finished = true
+	if rset.fetched == 0 {
+		rset.offset, rset.pos, rset.Row = 0, 1, nil
+		rset.Unlock()
+		return false
+	}
+	rset.offset, rset.pos = 1, rset.winStart
+	rset.Unlock()
+	return rset.loadScrolled(0)
+}
+
+// Absolute moves to the row at the 1-based position n of a scrollable Rset,
+// and loads it into Row. False is returned, and Row is set to nil, if there
+// is no such row.
+func (rset *Rset) Absolute(n int) bool {
+	rset.RLock()
+	backward := int64(n) < rset.pos
+	rset.RUnlock()
+	return rset.scrollTo(int64(n), backward)
+}
+
+// Relative moves n rows forward (or backward, for a negative n) in a
+// scrollable Rset, and loads the row into Row. False is returned, and Row is
+// set to nil, if there is no such row.
+func (rset *Rset) Relative(n int) bool {
+	rset.RLock()
+	pos := rset.pos
+	rset.RUnlock()
+	return rset.scrollTo(pos+int64(n), n < 0)
+}
+
+// checkScrollable sets Err if the Rset is closed or is not scrollable.
+func (rset *Rset) checkScrollable() bool {
+	err := rset.checkIsOpen()
+	rset.Lock()
+	defer rset.Unlock()
+	if err == nil && !rset.scrollable {
+		err = er("Rset is not scrollable.")
+	}
+	if err != nil {
+		rset.err, rset.Row = err, nil
+		return false
+	}
+	return true
+}
+
+// scrollTo moves to the row at pos. The rows around pos are fetched if they
+// are not fetched already: the FetchLen rows before pos if moving backward,
+// after pos otherwise.
+func (rset *Rset) scrollTo(pos int64, backward bool) bool {
+	if !rset.checkScrollable() {
+		return false
+	}
+	rset.Lock()
+	if pos < 1 {
+		// before the first row; Next shall load the first row
+		if rset.winStart != 1 || rset.fetched == 0 {
+			if err := rset.fetchScroll(C.OCI_FETCH_ABSOLUTE, 1, rset.fetchLen); err != nil {
+				rset.err, rset.Row = err, nil
+				rset.Unlock()
+				return false
+			}
+		}
+		rset.offset, rset.pos, rset.Row = 0, 0, nil
+		rset.Unlock()
+		return false
+	}
+	if pos < rset.winStart || pos >= rset.winStart+rset.fetched {
+		start := pos
+		if backward {
+			if start = pos - int64(rset.fetchLen) + 1; start < 1 {
+				start = 1
+			}
+		}
+		if err := rset.fetchScroll(C.OCI_FETCH_ABSOLUTE, start, rset.fetchLen); err != nil {
+			rset.err, rset.Row = err, nil
+			rset.Unlock()
+			return false
+		}
+		if pos >= rset.winStart+rset.fetched {
+			// after the last row
+			if err := rset.fetchScroll(C.OCI_FETCH_LAST, 0, 1); err != nil {
+				rset.err, rset.Row = err, nil
+				rset.Unlock()
+				return false
+			}
+			rset.finished = true
+			rset.offset, rset.pos, rset.Row = rset.fetched, rset.winStart+rset.fetched, nil
+			rset.Unlock()
+			return false
+		}
+	}
+	offset := pos - rset.winStart
+	rset.offset, rset.pos = offset+1, pos
+	rset.Unlock()
+	return rset.loadScrolled(offset)
+}
+
+// loadScrolled loads the fetched row at offset into Row.
+func (rset *Rset) loadScrolled(offset int64) bool {
+	if err := rset.loadRow(offset); err != nil {
+		rset.Lock()
+		rset.err, rset.Row = err, nil
+		rset.Unlock()
+		return false
+	}
+	atomic.AddInt32(&rset.index, 1)
+	return true
+}
+
+// fetchScroll fetches at most nrows rows from the given orientation and
+// offset, and sets the position of the first fetched row.
+// The Rset must be locked.
+func (rset *Rset) fetchScroll(orientation C.ub2, offset int64, nrows int) error {
+	for _, define := range rset.defs {
+		if define == nil {
+			continue
+		}
+		if err := define.alloc(); err != nil {
+			return err
+		}
+	}
+	r, err := rset.fetch(rset.ctx, nrows, orientation, offset)
+	if err != nil {
+		return err
+	}
+	var rowsFetched, current C.ub4
+	if err := rset.attr(unsafe.Pointer(&rowsFetched), 4, C.OCI_ATTR_ROWS_FETCHED); err != nil {
+		return err
+	}
+	if err := rset.attr(unsafe.Pointer(&current), 4, C.OCI_ATTR_CURRENT_POSITION); err != nil {
+		return err
+	}
+	rset.fetched = int64(rowsFetched)
+	rset.winStart = int64(current) - rset.fetched + 1
+	rset.finished = r == C.OCI_NO_DATA
+	return nil
+}
@@ -0,0 +1,337 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+*/
+import "C"
+import "time"
+
+// The typed accessors read column i of the current row. After NextNoRow,
+// they decode the value straight from the define buffers; after Next, they
+// convert the value of Rset.Row.
+//
+// A NULL value is returned as the zero value of the type: tell it apart with
+// IsNull. A value of another type is converted as by Scan.
+
+// IsNull reports whether column i of the current row is NULL. Without a
+// current row, or column i, IsNull reports true.
+func (rset *Rset) IsNull(i int) bool {
+	d, offset, value, err := rset.column(i)
+	if err != nil {
+		return true
+	}
+	if d == nil {
+		return isNullValue(value)
+	}
+	if isNull, ok := isNullAt(d, offset); ok {
+		return isNull
+	}
+	if value, err = d.value(offset); err != nil {
+		return true
+	}
+	return isNullValue(value)
+}
+
+// Int64 returns column i of the current row as an int64.
+func (rset *Rset) Int64(i int) (int64, error) {
+	d, offset, value, err := rset.column(i)
+	if err != nil {
+		return 0, err
+	}
+	if d != nil {
+		if i64, ok, err := int64At(d, offset); ok {
+			return i64, err
+		}
+		if value, err = d.value(offset); err != nil {
+			return 0, err
+		}
+	}
+	var v Int64
+	if err = convertAssign(&v, value); err != nil {
+		return 0, errF("column %d: %v", i, err)
+	}
+	return v.Value, nil
+}
+
+// Float64 returns column i of the current row as a float64.
+func (rset *Rset) Float64(i int) (float64, error) {
+	d, offset, value, err := rset.column(i)
+	if err != nil {
+		return 0, err
+	}
+	if d != nil {
+		if f64, ok, err := float64At(d, offset); ok {
+			return f64, err
+		}
+		if value, err = d.value(offset); err != nil {
+			return 0, err
+		}
+	}
+	var v Float64
+	if err = convertAssign(&v, value); err != nil {
+		return 0, errF("column %d: %v", i, err)
+	}
+	return v.Value, nil
+}
+
+// Bytes returns column i of the current row as a []byte.
+//
+// After NextNoRow, the returned slice of a CHAR, VARCHAR2, LONG, RAW or
+// LONG RAW column may point into the define buffers: it is valid until the
+// next call of Next, NextNoRow or NextBatch, and must not be modified.
+func (rset *Rset) Bytes(i int) ([]byte, error) {
+	d, offset, value, err := rset.column(i)
+	if err != nil {
+		return nil, err
+	}
+	if d != nil {
+		if p, ok, err := bytesAt(d, offset); ok {
+			return p, err
+		}
+		if value, err = d.value(offset); err != nil {
+			return nil, err
+		}
+	}
+	var v Raw
+	if err = convertAssign(&v, value); err != nil {
+		return nil, errF("column %d: %v", i, err)
+	}
+	return v.Value, nil
+}
+
+// String returns column i of the current row as a string.
+func (rset *Rset) String(i int) (string, error) {
+	d, offset, value, err := rset.column(i)
+	if err != nil {
+		return "", err
+	}
+	if d != nil {
+		if p, ok, err := bytesAt(d, offset); ok {
+			return string(p), err
+		}
+		if value, err = d.value(offset); err != nil {
+			return "", err
+		}
+	}
+	var v String
+	if err = convertAssign(&v, value); err != nil {
+		return "", errF("column %d: %v", i, err)
+	}
+	return v.Value, nil
+}
+
+// Time returns column i of the current row as a time.Time.
+func (rset *Rset) Time(i int) (time.Time, error) {
+	d, offset, value, err := rset.column(i)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if d != nil {
+		if t, ok, err := timeAt(d, offset); ok {
+			return t, err
+		}
+		if value, err = d.value(offset); err != nil {
+			return time.Time{}, err
+		}
+	}
+	var v Time
+	if err = convertAssign(&v, value); err != nil {
+		return time.Time{}, errF("column %d: %v", i, err)
+	}
+	return v.Value, nil
+}
+
+// column returns the def of column i and the offset of the current row, after
+// NextNoRow, or else the value of column i of Row.
+func (rset *Rset) column(i int) (d def, offset int, value interface{}, err error) {
+	rset.RLock()
+	defer rset.RUnlock()
+	if rset.noRow {
+		if i < 0 || i >= len(rset.defs) {
+			return nil, 0, nil, errF("column index %d out of range [0, %d)", i, len(rset.defs))
+		}
+		return rset.defs[i], int(rset.cur), nil, nil
+	}
+	if rset.Row == nil {
+		return nil, 0, nil, errNew("no current row; call Next or NextNoRow first")
+	}
+	if i < 0 || i >= len(rset.Row) {
+		return nil, 0, nil, errF("column index %d out of range [0, %d)", i, len(rset.Row))
+	}
+	return nil, 0, rset.Row[i], nil
+}
+
+// defNullInds returns the null indicators of the defs with typed buffers.
+func defNullInds(d def) []C.sb2 {
+	switch d := d.(type) {
+	case *defInt64:
+		return d.nullInds
+	case *defInt32:
+		return d.nullInds
+	case *defInt16:
+		return d.nullInds
+	case *defInt8:
+		return d.nullInds
+	case *defUint64:
+		return d.nullInds
+	case *defUint32:
+		return d.nullInds
+	case *defUint16:
+		return d.nullInds
+	case *defUint8:
+		return d.nullInds
+	case *defFloat64:
+		return d.nullInds
+	case *defFloat32:
+		return d.nullInds
+	case *defNumString:
+		return d.nullInds
+	case *defString:
+		return d.nullInds
+	case *defRaw:
+		return d.nullInds
+	case *defDate:
+		return d.nullInds
+	case *defTime:
+		return d.nullInds
+	}
+	return nil
+}
+
+// isNullAt reports whether the value of d at offset is NULL, if d has typed
+// buffers.
+func isNullAt(d def, offset int) (isNull, ok bool) {
+	if pd, ok := d.(pieceDef); ok {
+		return pd.pieceDef().isNull(offset), true
+	}
+	if nullInds := defNullInds(d); nullInds != nil {
+		return nullInds[offset] < 0, true
+	}
+	return false, false
+}
+
+// int64At returns the value of d at offset, if d holds integers.
+func int64At(d def, offset int) (int64, bool, error) {
+	var nums []C.OCINumber
+	var env *Env
+	switch d := d.(type) {
+	case *defInt64:
+		nums, env = d.ociNumber, d.rset.env
+	case *defInt32:
+		nums, env = d.ociNumber, d.rset.env
+	case *defInt16:
+		nums, env = d.ociNumber, d.rset.env
+	case *defInt8:
+		nums, env = d.ociNumber, d.rset.env
+	case *defUint32:
+		nums, env = d.ociNumber, d.rset.env
+	case *defUint16:
+		nums, env = d.ociNumber, d.rset.env
+	case *defUint8:
+		nums, env = d.ociNumber, d.rset.env
+	default:
+		return 0, false, nil
+	}
+	if defNullInds(d)[offset] < 0 {
+		return 0, true, nil
+	}
+	i64, err := numberInt64(env, &nums[offset])
+	return i64, true, err
+}
+
+// float64At returns the value of d at offset, if d holds numbers.
+func float64At(d def, offset int) (float64, bool, error) {
+	var nums []C.OCINumber
+	var env *Env
+	switch d := d.(type) {
+	case *defFloat64:
+		nums, env = d.ociNumber, d.rset.env
+	case *defFloat32:
+		nums, env = d.ociNumber, d.rset.env
+	case *defInt64:
+		nums, env = d.ociNumber, d.rset.env
+	case *defInt32:
+		nums, env = d.ociNumber, d.rset.env
+	case *defInt16:
+		nums, env = d.ociNumber, d.rset.env
+	case *defInt8:
+		nums, env = d.ociNumber, d.rset.env
+	case *defUint64:
+		nums, env = d.ociNumber, d.rset.env
+	case *defUint32:
+		nums, env = d.ociNumber, d.rset.env
+	case *defUint16:
+		nums, env = d.ociNumber, d.rset.env
+	case *defUint8:
+		nums, env = d.ociNumber, d.rset.env
+	default:
+		return 0, false, nil
+	}
+	if defNullInds(d)[offset] < 0 {
+		return 0, true, nil
+	}
+	f64, err := numberFloat64(env, &nums[offset])
+	return f64, true, err
+}
+
+// bytesAt returns the value of d at offset, if d holds text or binary
+// values; with a UTF-8 charset, it is a slice of the buffers of d.
+func bytesAt(d def, offset int) ([]byte, bool, error) {
+	switch d := d.(type) {
+	case *defNumString:
+		return bytesAt(&d.defString, offset)
+	case *defString:
+		d.RLock()
+		defer d.RUnlock()
+		if d.nullInds[offset] < 0 {
+			return nil, true, nil
+		}
+		p, err := d.bytes(offset)
+		return p, true, err
+	case *defLong:
+		if d.isNull(offset) {
+			return nil, true, nil
+		}
+		cs := d.rset.env.charset
+		if cs.isUTF8() {
+			return d.values[offset], true, nil
+		}
+		s, err := cs.decode(d.values[offset])
+		return []byte(s), true, err
+	case *defLongRaw:
+		if d.isNull(offset) {
+			return nil, true, nil
+		}
+		return d.values[offset], true, nil
+	case *defRaw:
+		if d.nullInds[offset] < 0 {
+			return nil, true, nil
+		}
+		off := offset * d.columnSize
+		return d.buf[off : off+int(d.alen[offset])], true, nil
+	}
+	return nil, false, nil
+}
+
+// timeAt returns the value of d at offset, if d holds dates or
+// timestamps.
+func timeAt(d def, offset int) (time.Time, bool, error) {
+	switch d := d.(type) {
+	case *defDate:
+		if d.nullInds[offset] < 0 {
+			return time.Time{}, true, nil
+		}
+		return d.ociDate[offset].GetIn(d.timezone), true, nil
+	case *defTime:
+		if d.nullInds[offset] < 0 {
+			return time.Time{}, true, nil
+		}
+		t, err := getTime(d.rset.env, d.dates[offset])
+		return t, true, err
+	}
+	return time.Time{}, false, nil
+}
@@ -0,0 +1,501 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Scan copies the columns of the current row into the values pointed at by
+// dest, converting them with the rules of database/sql's Rows.Scan.
+//
+// dest may hold pointers to the basic Go types (*string, *[]byte, *bool,
+// *time.Time, and the integer and floating-point types), to the nullable
+// types of this package (*String, *Int64, *Float64, *Time, *Bool, *Raw,
+// *OraNum and the like), sql.Scanner implementations and *interface{}.
+// A NULL column can only be scanned into a nullable destination: a nullable
+// type of this package, a pointer to a pointer (set to nil), a sql.Scanner or
+// an *interface{}. Other values, such as *LobLocator or Bfile, can be
+// scanned into a pointer to their own type.
+//
+// Scan returns an error for a failed conversion instead of panicking, so it
+// does not depend on the GoColumnTypes of the select-list columns.
+//
+// Call Scan after Next or NextNoRow returned true. After NextNoRow, the
+// *int64, *float64, *string, *[]byte and *time.Time destinations are set
+// straight from the define buffers, and the values of the other columns are
+// converted without populating Row.
+func (rset *Rset) Scan(dest ...interface{}) error {
+	rset.RLock()
+	row, columns, noRow, n := rset.Row, rset.Columns, rset.noRow, len(rset.defs)
+	rset.RUnlock()
+	if noRow {
+		if len(dest) != n {
+			return errF("expected %d destination arguments in Scan, not %d", n, len(dest))
+		}
+		for i := range dest {
+			if err := rset.scanColumn(i, dest[i]); err != nil {
+				return errF("Scan error on column index %d, name %q: %v", i, columns[i].Name, err)
+			}
+		}
+		return nil
+	}
+	if row == nil {
+		return errNew("Scan called without a current row; call Next first")
+	}
+	if len(dest) != len(row) {
+		return errF("expected %d destination arguments in Scan, not %d", len(row), len(dest))
+	}
+	for i, src := range row {
+		if err := convertAssign(dest[i], src); err != nil {
+			return errF("Scan error on column index %d, name %q: %v", i, columns[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// scanColumn copies column i of the current row of NextNoRow into dest.
+func (rset *Rset) scanColumn(i int, dest interface{}) error {
+	d, offset, _, err := rset.column(i)
+	if err != nil {
+		return err
+	}
+	if isNull, ok := isNullAt(d, offset); ok && !isNull {
+		switch dest := dest.(type) {
+		case *int64:
+			if i64, ok, err := int64At(d, offset); ok {
+				*dest = i64
+				return err
+			}
+		case *float64:
+			if f64, ok, err := float64At(d, offset); ok {
+				*dest = f64
+				return err
+			}
+		case *string:
+			if p, ok, err := bytesAt(d, offset); ok {
+				*dest = string(p)
+				return err
+			}
+		case *[]byte:
+			if p, ok, err := bytesAt(d, offset); ok {
+				*dest = cloneBytes(p)
+				return err
+			}
+		case *time.Time:
+			if t, ok, err := timeAt(d, offset); ok {
+				*dest = t
+				return err
+			}
+		}
+	}
+	value, err := d.value(offset)
+	if err != nil {
+		return err
+	}
+	return convertAssign(dest, value)
+}
+
+// scanValue converts a column value, as returned by a def, to a
+// driver.Value: nil, int64, float64, bool, []byte, string or time.Time.
+//
+// Values without such a form (*LobLocator, Bfile, Object, *Rset, etc.) are
+// returned as is.
+func scanValue(src interface{}) (interface{}, error) {
+	switch x := src.(type) {
+	case nil, int64, float64, bool, []byte, string, time.Time:
+		return x, nil
+	case int32:
+		return int64(x), nil
+	case int16:
+		return int64(x), nil
+	case int8:
+		return int64(x), nil
+	case uint64:
+		if x > 1<<63-1 {
+			return strconv.FormatUint(x, 10), nil
+		}
+		return int64(x), nil
+	case uint32:
+		return int64(x), nil
+	case uint16:
+		return int64(x), nil
+	case uint8:
+		return int64(x), nil
+	case float32:
+		return float64(x), nil
+	case Num:
+		return string(x), nil
+	case OCINum:
+		return x.String(), nil
+	case Int64:
+		if x.IsNull {
+			return nil, nil
+		}
+		return x.Value, nil
+	case Int32:
+		if x.IsNull {
+			return nil, nil
+		}
+		return int64(x.Value), nil
+	case Int16:
+		if x.IsNull {
+			return nil, nil
+		}
+		return int64(x.Value), nil
+	case Int8:
+		if x.IsNull {
+			return nil, nil
+		}
+		return int64(x.Value), nil
+	case Uint64:
+		if x.IsNull {
+			return nil, nil
+		}
+		return scanValue(x.Value)
+	case Uint32:
+		if x.IsNull {
+			return nil, nil
+		}
+		return int64(x.Value), nil
+	case Uint16:
+		if x.IsNull {
+			return nil, nil
+		}
+		return int64(x.Value), nil
+	case Uint8:
+		if x.IsNull {
+			return nil, nil
+		}
+		return int64(x.Value), nil
+	case Float64:
+		if x.IsNull {
+			return nil, nil
+		}
+		return x.Value, nil
+	case Float32:
+		if x.IsNull {
+			return nil, nil
+		}
+		return float64(x.Value), nil
+	case OraNum:
+		if x.IsNull {
+			return nil, nil
+		}
+		return x.Value, nil
+	case OraOCINum:
+		if x.IsNull {
+			return nil, nil
+		}
+		return x.Value.String(), nil
+	case String:
+		if x.IsNull {
+			return nil, nil
+		}
+		return x.Value, nil
+	case Bool:
+		if x.IsNull {
+			return nil, nil
+		}
+		return x.Value, nil
+	case Raw:
+		if x.IsNull {
+			return nil, nil
+		}
+		return x.Value, nil
+	case Time:
+		if x.IsNull {
+			return nil, nil
+		}
+		return x.Value, nil
+	case Date:
+		if x.IsNull() {
+			return nil, nil
+		}
+		return x.Get(), nil
+	case Rowid:
+		if x.IsNull {
+			return nil, nil
+		}
+		return x.Value, nil
+	case IntervalYM:
+		if x.IsNull {
+			return nil, nil
+		}
+		return x.String(), nil
+	case IntervalDS:
+		if x.IsNull {
+			return nil, nil
+		}
+		return x.String(), nil
+	case *big.Int:
+		if x == nil {
+			return nil, nil
+		}
+		return x.String(), nil
+	case *big.Float:
+		if x == nil {
+			return nil, nil
+		}
+		return x.Text('g', -1), nil
+	case *big.Rat:
+		if x == nil {
+			return nil, nil
+		}
+		return x.RatString(), nil
+	case *Lob:
+		if x == nil || x.Reader == nil {
+			return nil, nil
+		}
+		return x.Bytes()
+	}
+	return src, nil
+}
+
+// convertAssign copies the column value src to the value pointed at by dest,
+// as database/sql does.
+func convertAssign(dest, src interface{}) error {
+	// the same type needs no conversion
+	dpv := reflect.ValueOf(dest)
+	if dpv.Kind() != reflect.Ptr || dpv.IsNil() {
+		return errF("destination not a non-nil pointer: %T", dest)
+	}
+	if _, isBytes := src.([]byte); !isBytes && src != nil && reflect.TypeOf(src) == dpv.Type().Elem() {
+		dpv.Elem().Set(reflect.ValueOf(src))
+		return nil
+	}
+
+	sv, err := scanValue(src)
+	if err != nil {
+		return err
+	}
+	switch d := dest.(type) {
+	case *interface{}:
+		if b, ok := sv.([]byte); ok {
+			sv = cloneBytes(b)
+		}
+		*d = sv
+		return nil
+	case sql.Scanner:
+		if b, ok := sv.([]byte); ok {
+			sv = cloneBytes(b)
+		}
+		return d.Scan(sv)
+	}
+	if ok, err := convertNullable(dest, sv); ok {
+		return err
+	}
+
+	switch s := sv.(type) {
+	case nil:
+		if dpv.Elem().Kind() == reflect.Ptr {
+			dpv.Elem().Set(reflect.Zero(dpv.Elem().Type()))
+			return nil
+		}
+		return errF("converting NULL to %s is unsupported", dpv.Elem().Kind())
+	case string:
+		switch d := dest.(type) {
+		case *string:
+			*d = s
+			return nil
+		case *[]byte:
+			*d = []byte(s)
+			return nil
+		}
+	case []byte:
+		switch d := dest.(type) {
+		case *string:
+			*d = string(s)
+			return nil
+		case *[]byte:
+			*d = cloneBytes(s)
+			return nil
+		}
+	case time.Time:
+		switch d := dest.(type) {
+		case *time.Time:
+			*d = s
+			return nil
+		case *string:
+			*d = s.Format(time.RFC3339Nano)
+			return nil
+		case *[]byte:
+			*d = []byte(s.Format(time.RFC3339Nano))
+			return nil
+		}
+	}
+
+	dv := dpv.Elem()
+	if dv.Kind() == reflect.Ptr {
+		// allocate the pointed value, and convert into it
+		pv := reflect.New(dv.Type().Elem())
+		if err := convertAssign(pv.Interface(), src); err != nil {
+			return err
+		}
+		dv.Set(pv)
+		return nil
+	}
+	if sv != nil {
+		if rv := reflect.ValueOf(sv); rv.Type().AssignableTo(dv.Type()) {
+			dv.Set(rv)
+			return nil
+		}
+	}
+	if dv.Kind() == reflect.Bool {
+		bv, err := driver.Bool.ConvertValue(sv)
+		if err != nil {
+			return errF("converting %T to a bool: %v", sv, err)
+		}
+		dv.SetBool(bv.(bool))
+		return nil
+	}
+
+	s, ok := asString(sv)
+	if !ok {
+		return errF("unsupported Scan, storing %T into type %T", src, dest)
+	}
+	switch dv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i64, err := strconv.ParseInt(s, 10, dv.Type().Bits())
+		if err != nil {
+			return errF("converting %T (%q) to a %s: %v", src, s, dv.Kind(), numError(err))
+		}
+		dv.SetInt(i64)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u64, err := strconv.ParseUint(s, 10, dv.Type().Bits())
+		if err != nil {
+			return errF("converting %T (%q) to a %s: %v", src, s, dv.Kind(), numError(err))
+		}
+		dv.SetUint(u64)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f64, err := strconv.ParseFloat(s, dv.Type().Bits())
+		if err != nil {
+			return errF("converting %T (%q) to a %s: %v", src, s, dv.Kind(), numError(err))
+		}
+		dv.SetFloat(f64)
+		return nil
+	case reflect.String:
+		dv.SetString(s)
+		return nil
+	}
+	return errF("unsupported Scan, storing %T into type %T", src, dest)
+}
+
+// convertNullable converts sv (a driver.Value) into dest, if dest points to
+// a nullable type of this package.
+func convertNullable(dest, sv interface{}) (bool, error) {
+	isNull := sv == nil
+	var err error
+	switch d := dest.(type) {
+	case *String:
+		if d.IsNull = isNull; !isNull {
+			err = convertAssign(&d.Value, sv)
+		}
+	case *Int64:
+		if d.IsNull = isNull; !isNull {
+			err = convertAssign(&d.Value, sv)
+		}
+	case *Int32:
+		if d.IsNull = isNull; !isNull {
+			err = convertAssign(&d.Value, sv)
+		}
+	case *Int16:
+		if d.IsNull = isNull; !isNull {
+			err = convertAssign(&d.Value, sv)
+		}
+	case *Int8:
+		if d.IsNull = isNull; !isNull {
+			err = convertAssign(&d.Value, sv)
+		}
+	case *Uint64:
+		if d.IsNull = isNull; !isNull {
+			err = convertAssign(&d.Value, sv)
+		}
+	case *Uint32:
+		if d.IsNull = isNull; !isNull {
+			err = convertAssign(&d.Value, sv)
+		}
+	case *Uint16:
+		if d.IsNull = isNull; !isNull {
+			err = convertAssign(&d.Value, sv)
+		}
+	case *Uint8:
+		if d.IsNull = isNull; !isNull {
+			err = convertAssign(&d.Value, sv)
+		}
+	case *Float64:
+		if d.IsNull = isNull; !isNull {
+			err = convertAssign(&d.Value, sv)
+		}
+	case *Float32:
+		if d.IsNull = isNull; !isNull {
+			err = convertAssign(&d.Value, sv)
+		}
+	case *OraNum:
+		if d.IsNull = isNull; !isNull {
+			err = convertAssign(&d.Value, sv)
+		}
+	case *Bool:
+		if d.IsNull = isNull; !isNull {
+			err = convertAssign(&d.Value, sv)
+		}
+	case *Raw:
+		if d.IsNull = isNull; !isNull {
+			err = convertAssign(&d.Value, sv)
+		}
+	case *Time:
+		if d.IsNull = isNull; !isNull {
+			err = convertAssign(&d.Value, sv)
+		}
+	case *Rowid:
+		if d.IsNull = isNull; !isNull {
+			err = convertAssign(&d.Value, sv)
+		}
+	default:
+		return false, nil
+	}
+	return true, err
+}
+
+// asString returns the text form of the numbers, strings and bools.
+func asString(src interface{}) (string, bool) {
+	switch v := src.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
+
+// numError returns the cause of a strconv error.
+func numError(err error) error {
+	if ne, ok := err.(*strconv.NumError); ok {
+		return ne.Err
+	}
+	return err
+}
+
+func cloneBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	c := make([]byte, len(b))
+	copy(c, b)
+	return c
+}
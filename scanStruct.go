@@ -0,0 +1,255 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// StructMode controls how Rset.ScanStruct and Ses.QryStructs treat the
+// columns without a struct field, and the struct fields without a column.
+type StructMode uint8
+
+const (
+	// StructLax ignores the columns without a field, and the fields without
+	// a column, which keep their values.
+	StructLax StructMode = 0
+
+	// StructColumns returns an error for a column without a field.
+	StructColumns StructMode = 1
+
+	// StructFields returns an error for a field without a column.
+	StructFields StructMode = 2
+
+	// StructStrict returns an error for any column without a field, and for
+	// any field without a column.
+	StructStrict = StructColumns | StructFields
+)
+
+// ScanStruct copies the columns of the current row into the fields of the
+// struct pointed at by dest, converting them as Rset.Scan does.
+//
+// A column is copied into the exported field whose `db` tag names it, or
+// else into the field of the same name, compared case-insensitively. Fields
+// tagged `db:"-"` are skipped, the fields of embedded structs are mapped
+// as the fields of dest. A nested cursor column (SELECT CURSOR(...)) is
+// copied into a slice of structs (or struct pointers) field, each row of the
+// cursor mapped to an element as by ScanStruct.
+//
+// The unmapped columns and fields are reported as errors according to
+// RsetCfg.StructMode.
+//
+// Call ScanStruct after Next returned true.
+func (rset *Rset) ScanStruct(dest interface{}) error {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errF("ScanStruct needs a non-nil pointer to a struct, not %T", dest)
+	}
+	return rset.scanStruct(rv.Elem())
+}
+
+// QryStructs queries with the SQL statement and params, and appends each row
+// to the slice of structs (or struct pointers) pointed at by dest, mapped as
+// by Rset.ScanStruct.
+//
+// The select-list columns are defined with the GoColumnTypes of the StmtCfg:
+// to see NULL values in nullable fields (ora.String, ora.Int64, *string and
+// the like), configure nullable GoColumnTypes, such as OraS and OraI64.
+func (ses *Ses) QryStructs(dest interface{}, sql string, params ...interface{}) error {
+	return ses.QryStructsContext(context.Background(), dest, sql, params...)
+}
+
+// QryStructsContext is like QryStructs, but the query and the fetches are
+// broken when ctx is done, returning an error with ctx.Err() as its Cause.
+func (ses *Ses) QryStructsContext(ctx context.Context, dest interface{}, sql string, params ...interface{}) error {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || !isStructSlice(rv.Elem().Type()) {
+		return errF("QryStructs needs a pointer to a slice of structs, not %T", dest)
+	}
+	rset, err := ses.prepAndQry(ctx, sql, params)
+	if err != nil {
+		return err
+	}
+	return rset.scanStructs(rv.Elem())
+}
+
+// scanStructs appends the rows of rset to the slice of structs (or struct
+// pointers) sv, and exhausts rset.
+func (rset *Rset) scanStructs(sv reflect.Value) error {
+	elemType := sv.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	for rset.Next() {
+		ev := reflect.New(elemType)
+		if err := rset.scanStruct(ev.Elem()); err != nil {
+			rset.Exhaust()
+			return err
+		}
+		if !isPtr {
+			ev = ev.Elem()
+		}
+		sv.Set(reflect.Append(sv, ev))
+	}
+	return rset.Err()
+}
+
+// scanStruct copies the current row into the struct sv.
+func (rset *Rset) scanStruct(sv reflect.Value) error {
+	rset.RLock()
+	row, columns, stmt := rset.Row, rset.Columns, rset.stmt
+	rset.RUnlock()
+	if row == nil {
+		return errNew("ScanStruct called without a current row; call Next first")
+	}
+	var mode StructMode
+	if stmt != nil {
+		mode = stmt.Cfg().RsetCfg.StructMode()
+	}
+	return assignStruct(sv, columns, row, mode)
+}
+
+// assignStruct copies the values of row, of the columns, into the struct sv.
+func assignStruct(sv reflect.Value, columns []Column, row []interface{}, mode StructMode) error {
+	fields := structFieldsOf(sv.Type())
+	var mapped []bool
+	if mode&StructFields != 0 {
+		mapped = make([]bool, len(fields.list))
+	}
+	for i, src := range row {
+		name := columns[i].Name
+		j, ok := fields.byName[strings.ToUpper(name)]
+		if !ok {
+			if mode&StructColumns != 0 {
+				return errF("column %q has no field in %v", name, sv.Type())
+			}
+			continue
+		}
+		if mapped != nil {
+			mapped[j] = true
+		}
+		f := fields.list[j]
+		fv := sv.FieldByIndex(f.index)
+		if nested, ok := src.(*Rset); ok && isStructSlice(fv.Type()) {
+			fv.Set(reflect.Zero(fv.Type()))
+			if nested == nil {
+				continue
+			}
+			if err := nested.scanStructs(fv); err != nil {
+				return errF("column %q into field %s: %v", name, f.name, err)
+			}
+			continue
+		}
+		if err := convertAssign(fv.Addr().Interface(), src); err != nil {
+			return errF("column %q into field %s: %v", name, f.name, err)
+		}
+	}
+	for j, ok := range mapped {
+		if !ok {
+			return errF("field %s of %v has no column", fields.list[j].name, sv.Type())
+		}
+	}
+	return nil
+}
+
+// isStructSlice reports whether t is a slice of structs or struct pointers.
+func isStructSlice(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice {
+		return false
+	}
+	t = t.Elem()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// structField is a field of a struct mapped to a column.
+type structField struct {
+	name  string // Go name, for errors
+	index []int  // for reflect.Value.FieldByIndex
+}
+
+// structFields are the fields of a struct mapped to columns, by uppercase
+// column name.
+type structFields struct {
+	list   []structField
+	byName map[string]int
+}
+
+var (
+	structFieldsMu    sync.RWMutex
+	structFieldsCache = make(map[reflect.Type]*structFields)
+)
+
+// structFieldsOf returns the fields of the struct type t, which are cached.
+func structFieldsOf(t reflect.Type) *structFields {
+	structFieldsMu.RLock()
+	fields := structFieldsCache[t]
+	structFieldsMu.RUnlock()
+	if fields != nil {
+		return fields
+	}
+	fields = &structFields{byName: make(map[string]int)}
+	// breadth first, so the shallower fields take precedence, as in Go
+	queue := []embeddedStruct{{typ: t}}
+	for len(queue) != 0 {
+		e := queue[0]
+		queue = append(queue[1:], fields.add(e.typ, e.index)...)
+	}
+	structFieldsMu.Lock()
+	structFieldsCache[t] = fields
+	structFieldsMu.Unlock()
+	return fields
+}
+
+// embeddedStruct is a struct type at index in the outermost struct.
+type embeddedStruct struct {
+	typ   reflect.Type
+	index []int
+}
+
+// add adds the fields of t, which is at index in the outermost struct, and
+// returns the structs embedded in t.
+func (fields *structFields) add(t reflect.Type, index []int) []embeddedStruct {
+	var embedded []embeddedStruct
+	for n := 0; n < t.NumField(); n++ {
+		f := t.Field(n)
+		tag := f.Tag.Get("db")
+		name := strings.TrimSpace(strings.Split(tag, ",")[0])
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			embedded = append(embedded, embeddedStruct{typ: f.Type, index: fieldIndex(index, n)})
+			continue
+		}
+		if f.PkgPath != "" { // skip unexported fields
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		name = strings.ToUpper(name)
+		if _, ok := fields.byName[name]; ok {
+			continue
+		}
+		fields.byName[name] = len(fields.list)
+		fields.list = append(fields.list, structField{
+			name:  f.Name,
+			index: fieldIndex(index, n),
+		})
+	}
+	return embedded
+}
+
+// fieldIndex returns a copy of index, extended with n.
+func fieldIndex(index []int, n int) []int {
+	return append(append(make([]int, 0, len(index)+1), index...), n)
+}
@@ -0,0 +1,73 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import (
+	"reflect"
+	"testing"
+)
+
+type scanBase struct {
+	ID      int64
+	Created string `db:"created_at"`
+}
+
+type scanRow struct {
+	scanBase
+	Name    string
+	Nick    String `db:"NICKNAME"`
+	Ignored string `db:"-"`
+	ID      string // shadows scanBase.ID
+	private int
+	_hidden int
+	ékezet  string
+}
+
+func TestAssignStruct(t *testing.T) {
+	columns := []Column{{Name: "ID"}, {Name: "NAME"}, {Name: "NICKNAME"}, {Name: "CREATED_AT"}}
+	row := []interface{}{int64(7), "Gopher", String{IsNull: true}, "2017-03-04"}
+
+	var got scanRow
+	if err := assignStruct(reflect.ValueOf(&got).Elem(), columns, row, StructStrict); err != nil {
+		t.Fatal(err)
+	}
+	want := scanRow{
+		scanBase: scanBase{Created: "2017-03-04"},
+		Name:     "Gopher", Nick: String{IsNull: true}, ID: "7",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, wanted %#v", got, want)
+	}
+
+	for i, tc := range []struct {
+		columns []Column
+		mode    StructMode
+		wantErr bool
+	}{
+		{append(columns, Column{Name: "EXTRA"}), StructLax, false},
+		{append(columns, Column{Name: "EXTRA"}), StructFields, false},
+		{append(columns, Column{Name: "EXTRA"}), StructColumns, true},
+		{columns[:2], StructColumns, false},
+		{columns[:2], StructFields, true},
+		{columns[:2], StructStrict, true},
+	} {
+		row := make([]interface{}, len(tc.columns))
+		for j := range row {
+			row[j] = "1"
+		}
+		var got scanRow
+		err := assignStruct(reflect.ValueOf(&got).Elem(), tc.columns, row, tc.mode)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%d. mode=%d: got error %v, wanted error %t", i, tc.mode, err, tc.wantErr)
+		}
+	}
+
+	// conversion errors are returned
+	row = []interface{}{"x", "Gopher", String{}, ""}
+	var base scanBase
+	if err := assignStruct(reflect.ValueOf(&base).Elem(), columns, row, StructLax); err == nil {
+		t.Errorf("wanted error for %q into int64", row[0])
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import (
+	"database/sql"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConvertAssign(t *testing.T) {
+	now := time.Date(2017, 3, 4, 5, 6, 7, 0, time.UTC)
+	i64 := func(i int64) *int64 { return &i }
+	for i, tc := range []struct {
+		src  interface{}
+		dest interface{}
+		want interface{}
+	}{
+		{int64(42), new(int64), int64(42)},
+		{float64(42), new(int64), int64(42)},
+		{float64(4.5), new(float32), float32(4.5)},
+		{Int32{Value: 7}, new(int), 7},
+		{Uint64{Value: 1<<64 - 1}, new(uint64), uint64(1<<64 - 1)},
+		{"12", new(int16), int16(12)},
+		{OraNum{Value: "3.25"}, new(float64), 3.25},
+		{Num("-9"), new(int8), int8(-9)},
+		{big.NewInt(123), new(string), "123"},
+		{"abc", new(string), "abc"},
+		{String{Value: "abc"}, new(string), "abc"},
+		{"abc", new([]byte), []byte("abc")},
+		{[]byte("xyz"), new(string), "xyz"},
+		{Raw{Value: []byte{1, 2}}, new([]byte), []byte{1, 2}},
+		{now, new(time.Time), now},
+		{Time{Value: now}, new(time.Time), now},
+		{true, new(bool), true},
+		{int64(1), new(bool), true},
+		{Bool{Value: true}, new(string), "true"},
+		{"abc", new(String), String{Value: "abc"}},
+		{String{IsNull: true}, new(String), String{IsNull: true}},
+		{float64(42), new(Int64), Int64{Value: 42}},
+		{Int64{IsNull: true}, new(Float64), Float64{IsNull: true}},
+		{now, new(Time), Time{Value: now}},
+		{Rowid{Value: "AAAAAAAAB"}, new(Rowid), Rowid{Value: "AAAAAAAAB"}},
+		{"AAAAAAAAB", new(Rowid), Rowid{Value: "AAAAAAAAB"}},
+		{Int64{Value: 3}, new(*int64), i64(3)},
+		{Int64{IsNull: true}, new(*int64), (*int64)(nil)},
+		{Float64{Value: 2}, new(interface{}), float64(2)},
+		{String{IsNull: true}, new(interface{}), nil},
+		{"5", new(sql.NullInt64), sql.NullInt64{Int64: 5, Valid: true}},
+		{String{IsNull: true}, new(sql.NullString), sql.NullString{}},
+		{Bfile{DirectoryAlias: "D", Filename: "f"}, new(Bfile), Bfile{DirectoryAlias: "D", Filename: "f"}},
+	} {
+		if err := convertAssign(tc.dest, tc.src); err != nil {
+			t.Errorf("%d. %T into %T: %v", i, tc.src, tc.dest, err)
+			continue
+		}
+		if got := reflect.ValueOf(tc.dest).Elem().Interface(); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%d. %T into %T: got %#v, wanted %#v", i, tc.src, tc.dest, got, tc.want)
+		}
+	}
+}
+
+func TestConvertAssign_errors(t *testing.T) {
+	for i, tc := range []struct {
+		src  interface{}
+		dest interface{}
+	}{
+		{String{IsNull: true}, new(string)},
+		{Int64{IsNull: true}, new(int64)},
+		{int64(300), new(int8)},
+		{int64(-1), new(uint)},
+		{float64(1.5), new(int64)},
+		{"abc", new(float64)},
+		{"maybe", new(bool)},
+		{time.Now(), new(int64)},
+		{Bfile{}, new(string)},
+		{int64(1), int64(1)},
+		{int64(1), (*int64)(nil)},
+	} {
+		if err := convertAssign(tc.dest, tc.src); err == nil {
+			t.Errorf("%d. %T into %T: wanted error, got %#v", i, tc.src, tc.dest, reflect.ValueOf(tc.dest))
+		}
+	}
+}
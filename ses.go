@@ -12,6 +12,7 @@ import "C"
 import (
 	"bytes"
 	"container/list"
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
@@ -20,6 +21,8 @@ import (
 	"sync/atomic"
 	"time"
 	"unsafe"
+
+	"gopkg.in/rana/ora.v4/lex"
 )
 
 type SesCfg struct {
@@ -66,6 +69,34 @@ func (c SesCfg) SetByteSlice(gct GoColumnType) SesCfg {
 	c.StmtCfg = c.StmtCfg.SetByteSlice(gct)
 	return c
 }
+func (c SesCfg) SetPlsBool(plsBool bool) SesCfg {
+	c.StmtCfg = c.StmtCfg.SetPlsBool(plsBool)
+	return c
+}
+func (c SesCfg) SetScrollable(scrollable bool) SesCfg {
+	c.StmtCfg = c.StmtCfg.SetScrollable(scrollable)
+	return c
+}
+func (c SesCfg) SetNumberPlaceholders(numberPlaceholders bool) SesCfg {
+	c.StmtCfg = c.StmtCfg.SetNumberPlaceholders(numberPlaceholders)
+	return c
+}
+func (c SesCfg) SetCallTimeout(timeout time.Duration) SesCfg {
+	c.StmtCfg = c.StmtCfg.SetCallTimeout(timeout)
+	return c
+}
+func (c SesCfg) SetAdaptiveFetch(adaptive bool) SesCfg {
+	c.StmtCfg = c.StmtCfg.SetAdaptiveFetch(adaptive)
+	return c
+}
+func (c SesCfg) SetFetchMemory(size int) SesCfg {
+	c.StmtCfg = c.StmtCfg.SetFetchMemory(size)
+	return c
+}
+func (c SesCfg) SetFetchGrowth(growth bool) SesCfg {
+	c.StmtCfg = c.StmtCfg.SetFetchGrowth(growth)
+	return c
+}
 func (c SesCfg) SetNumberInt(gct GoColumnType) SesCfg {
 	c.StmtCfg = c.StmtCfg.SetNumberInt(gct)
 	return c
@@ -112,6 +143,16 @@ func (c SesCfg) SetClob(gct GoColumnType) SesCfg    { c.StmtCfg = c.StmtCfg.SetC
 func (c SesCfg) SetBlob(gct GoColumnType) SesCfg    { c.StmtCfg = c.StmtCfg.SetBlob(gct); return c }
 func (c SesCfg) SetRaw(gct GoColumnType) SesCfg     { c.StmtCfg = c.StmtCfg.SetRaw(gct); return c }
 func (c SesCfg) SetLongRaw(gct GoColumnType) SesCfg { c.StmtCfg = c.StmtCfg.SetLongRaw(gct); return c }
+func (c SesCfg) SetJSON(gct GoColumnType) SesCfg    { c.StmtCfg = c.StmtCfg.SetJSON(gct); return c }
+func (c SesCfg) SetRowid(gct GoColumnType) SesCfg   { c.StmtCfg = c.StmtCfg.SetRowid(gct); return c }
+func (c SesCfg) SetJSONLobs(jsonLobs bool) SesCfg {
+	c.StmtCfg = c.StmtCfg.SetJSONLobs(jsonLobs)
+	return c
+}
+func (c SesCfg) SetStructMode(mode StructMode) SesCfg {
+	c.StmtCfg = c.StmtCfg.SetStructMode(mode)
+	return c
+}
 
 type SessionMode uint8
 
@@ -175,6 +216,11 @@ type LogSesCfg struct {
 	//
 	// The default is true.
 	Break bool
+
+	// DescribeType determines whether the Ses.DescribeType method is logged.
+	//
+	// The default is true.
+	DescribeType bool
 }
 
 // NewLogSesCfg creates a LogSesCfg with default values.
@@ -190,6 +236,7 @@ func NewLogSesCfg() LogSesCfg {
 	c.StartTx = true
 	c.Ping = true
 	c.Break = true
+	c.DescribeType = true
 	return c
 }
 
@@ -206,12 +253,15 @@ type Ses struct {
 	ocisvcctx *C.OCISvcCtx
 	ocises    *C.OCISession
 	isLocked  bool
+	// OCI_ATTR_CALL_TIMEOUT in milliseconds, accessed atomically
+	callTimeout uint32
 
 	openStmts *stmtList
 	openTxs   *txList
 
 	insteadClose func(ses *Ses) error
 	timezone     *time.Location
+	objTypes     map[string]*ObjectType
 
 	sysNamer
 }
@@ -298,6 +348,8 @@ func (ses *Ses) close() (err error) {
 		ses.srv = nil
 		ses.ocisvcctx = nil
 		ses.ocises = nil
+		ses.callTimeout = 0
+		ses.objTypes = nil
 		ses.openStmts.clear()
 		ses.openTxs.clear()
 		ses.Unlock()
@@ -370,18 +422,24 @@ func (ses *Ses) close() (err error) {
 // then Exe separately (and close the Stmt returned by Prep after finishing with
 // those objects).
 func (ses *Ses) PrepAndExe(sql string, params ...interface{}) (rowsAffected uint64, err error) {
-	return ses.prepAndExe(sql, false, params...)
+	return ses.prepAndExe(context.Background(), sql, false, params...)
 }
 
 // PrepAndExeP prepares and executes a SQL statement returning the number of rows
 // affected and a possible error, using ExeP, so passing arrays as is.
 func (ses *Ses) PrepAndExeP(sql string, params ...interface{}) (rowsAffected uint64, err error) {
-	return ses.prepAndExe(sql, true, params...)
+	return ses.prepAndExe(context.Background(), sql, true, params...)
+}
+
+// PrepAndExeContext is like PrepAndExe, but the execution is broken when ctx
+// is done, returning an error with ctx.Err() as its Cause.
+func (ses *Ses) PrepAndExeContext(ctx context.Context, sql string, params ...interface{}) (rowsAffected uint64, err error) {
+	return ses.prepAndExe(ctx, sql, false, params...)
 }
 
 // prepAndExe prepares and executes a SQL statement returning the number of rows
 // affected and a possible error.
-func (ses *Ses) prepAndExe(sql string, isAssocArray bool, params ...interface{}) (rowsAffected uint64, err error) {
+func (ses *Ses) prepAndExe(ctx context.Context, sql string, isAssocArray bool, params ...interface{}) (rowsAffected uint64, err error) {
 	defer func() {
 		if value := recover(); value != nil {
 			err = errR(value)
@@ -404,11 +462,7 @@ func (ses *Ses) prepAndExe(sql string, isAssocArray bool, params ...interface{})
 	if err != nil {
 		return 0, errE(err)
 	}
-	if isAssocArray {
-		rowsAffected, err = stmt.ExeP(params...)
-	} else {
-		rowsAffected, err = stmt.Exe(params...)
-	}
+	rowsAffected, _, err = stmt.exeC(ctx, params, isAssocArray)
 	if err != nil {
 		return rowsAffected, errE(err)
 	}
@@ -423,6 +477,19 @@ func (ses *Ses) prepAndExe(sql string, isAssocArray bool, params ...interface{})
 // The *Stmt internal to this method is automatically closed when the *Rset
 // retrieves all rows or returns an error.
 func (ses *Ses) PrepAndQry(sql string, params ...interface{}) (rset *Rset, err error) {
+	return ses.prepAndQry(context.Background(), sql, params)
+}
+
+// PrepAndQryContext is like PrepAndQry, but the query and the fetches of the
+// returned *Rset are broken when ctx is done, returning an error with ctx.Err()
+// as its Cause.
+func (ses *Ses) PrepAndQryContext(ctx context.Context, sql string, params ...interface{}) (rset *Rset, err error) {
+	return ses.prepAndQry(ctx, sql, params)
+}
+
+// prepAndQry prepares a SQL statement and queries an Oracle server returning
+// an *Rset and a possible error.
+func (ses *Ses) prepAndQry(ctx context.Context, sql string, params []interface{}) (rset *Rset, err error) {
 	ses.log(_drv.Cfg().Log.Ses.PrepAndQry)
 	err = ses.checkClosed()
 	if err != nil {
@@ -433,7 +500,7 @@ func (ses *Ses) PrepAndQry(sql string, params ...interface{}) (rset *Rset, err e
 		defer stmt.Close()
 		return nil, errE(err)
 	}
-	rset, err = stmt.Qry(params...)
+	rset, err = stmt.qryC(ctx, params)
 	if err != nil {
 		defer stmt.Close()
 		return nil, errE(err)
@@ -457,8 +524,18 @@ func (ses *Ses) Prep(sql string, gcts ...GoColumnType) (stmt *Stmt, err error) {
 	if err != nil {
 		return nil, errE(err)
 	}
+	var argOrder []int
+	if ses.Cfg().StmtCfg.numberPlaceholders {
+		if sql, argOrder, err = lex.NumberPlaceholders(sql); err != nil {
+			return nil, errE(err)
+		}
+	}
 	ocistmt := (*C.OCIStmt)(nil)
-	cSql := C.CString(sql) // prepare sql text with statement handle
+	text, err := ses.Env().encode(sql)
+	if err != nil {
+		return nil, errE(err)
+	}
+	cSql := C.CString(text) // prepare sql text with statement handle
 	ses.RLock()
 	env := ses.Env()
 	r := C.OCIStmtPrepare2(
@@ -466,7 +543,7 @@ func (ses *Ses) Prep(sql string, gcts ...GoColumnType) (stmt *Stmt, err error) {
 		&ocistmt,                           // OCIStmt       *stmtp,
 		env.ocierr,                         // OCIError      *errhp,
 		(*C.OraText)(unsafe.Pointer(cSql)), // const OraText *stmt,
-		C.ub4(len(sql)),                    // ub4           stmt_len,
+		C.ub4(len(text)),                   // ub4           stmt_len,
 		nil,                                // const OraText *key,
 		C.ub4(0),                           // ub4           keylen,
 		C.OCI_NTV_SYNTAX,                   // ub4           language,
@@ -493,6 +570,7 @@ func (ses *Ses) Prep(sql string, gcts ...GoColumnType) (stmt *Stmt, err error) {
 	ses.RUnlock()
 	stmt.sql = sql
 	stmt.gcts = gcts
+	stmt.argOrder = argOrder
 	if stmt.id == 0 {
 		stmt.id = _drv.stmtId.nextId()
 	}
@@ -721,6 +799,19 @@ func TxTimeout(timeout time.Duration) TxOption { return func(o *txOption) { o.ti
 
 // StartTx starts an Oracle transaction returning a *Tx and possible error.
 func (ses *Ses) StartTx(opts ...TxOption) (tx *Tx, err error) {
+	return ses.startTx(context.Background(), opts)
+}
+
+// StartTxContext is like StartTx, but starting the transaction is broken when
+// ctx is done, returning an error with ctx.Err() as its Cause.
+//
+// ctx limits Tx.Commit, too.
+func (ses *Ses) StartTxContext(ctx context.Context, opts ...TxOption) (tx *Tx, err error) {
+	return ses.startTx(ctx, opts)
+}
+
+// startTx starts a transaction, the round trips of which are limited by ctx.
+func (ses *Ses) startTx(ctx context.Context, opts []TxOption) (tx *Tx, err error) {
 	ses.log(_drv.Cfg().Log.Ses.StartTx)
 	err = ses.checkClosed()
 	if err != nil {
@@ -738,6 +829,10 @@ func (ses *Ses) StartTx(opts ...TxOption) (tx *Tx, err error) {
 	if o.timeout > 0 {
 		timeout = C.uword(o.timeout / time.Second)
 	}
+	call, err := ses.startCall(ctx, ses.Cfg().StmtCfg.callTimeout)
+	if err != nil {
+		return nil, errE(err)
+	}
 	ses.RLock()
 	env := ses.Env()
 	r := C.OCITransStart(
@@ -747,12 +842,16 @@ func (ses *Ses) StartTx(opts ...TxOption) (tx *Tx, err error) {
 		C.OCI_TRANS_NEW|C.ub4(o.flags)) //ub4          flags );
 	ses.RUnlock()
 	if r == C.OCI_ERROR {
-		return nil, errE(env.ociError())
+		err = env.ociError()
+	}
+	if err = call.end(err); err != nil {
+		return nil, errE(err)
 	}
 	tx = _drv.txPool.Get().(*Tx) // set *Tx
 	tx.cmu.Lock()
 	tx.Lock()
 	tx.ses = ses
+	tx.ctx = ctx
 	if tx.id == 0 {
 		tx.id = _drv.txId.nextId()
 	}
@@ -806,8 +905,10 @@ func (ses *Ses) Break() (err error) {
 	if err != nil {
 		return errE(err)
 	}
-	ses.Lock()
-	defer ses.Unlock()
+	// OCIBreak is called while another call is running on the session,
+	// which holds the read lock
+	ses.RLock()
+	defer ses.RUnlock()
 	env := ses.Env()
 	if ses.ocisvcctx == nil || env == nil || env.ocierr == nil {
 		return nil
@@ -927,6 +1028,50 @@ func (ses *Ses) Timezone() (*time.Location, error) {
 	return tz, nil
 }
 
+// serverMajorVersion returns the major version of the server, cached on the Srv.
+func (ses *Ses) serverMajorVersion() (int, error) {
+	srv := ses.srv
+	if v := atomic.LoadInt32(&srv.majorVersion); v != 0 {
+		return int(v), nil
+	}
+	env := ses.Env()
+	var buf [512]C.char
+	var version C.ub4
+	r := C.OCIServerRelease(
+		unsafe.Pointer(ses.ocisvcctx),         //void         *hndlp,
+		env.ocierr,                            //OCIError     *errhp,
+		(*C.OraText)(unsafe.Pointer(&buf[0])), //OraText      *bufp,
+		C.ub4(len(buf)),                       //ub4          bufsz,
+		C.OCI_HTYPE_SVCCTX,                    //ub1          hndltype,
+		&version)                              //ub4          *version );
+	if r == C.OCI_ERROR {
+		return 0, errE(env.ociError())
+	}
+	v := int32((version >> 24) & 0xff)
+	atomic.StoreInt32(&srv.majorVersion, v)
+	return int(v), nil
+}
+
+// hasPlsTypes reports whether PL/SQL BOOLEAN and RECORD types can be bound
+// natively, which needs both client and server version 12.1 or newer.
+func (ses *Ses) hasPlsTypes() bool {
+	if C.HAS_PLSQL_TYPES == 0 {
+		return false
+	}
+	major, err := ses.serverMajorVersion()
+	return err == nil && major >= 12
+}
+
+// hasJSON reports whether the JSON data type can be used,
+// which needs both client and server version 21 or newer.
+func (ses *Ses) hasJSON() bool {
+	if C.HAS_JSON == 0 {
+		return false
+	}
+	major, err := ses.serverMajorVersion()
+	return err == nil && major >= 21
+}
+
 // SetAction sets the MODULE and ACTION attribute of the session.
 func (ses *Ses) SetAction(module, action string) error {
 	if len(module) > 48 {
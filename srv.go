@@ -68,6 +68,8 @@ type Srv struct {
 	env    *Env
 	ocisrv *C.OCIServer
 	isUTF8 int32
+	// majorVersion is the cached major version of the server, 0 if unknown
+	majorVersion int32
 
 	ocipool        unsafe.Pointer
 	ociPoolName    *C.OraText
@@ -151,6 +153,7 @@ func (srv *Srv) close() (err error) {
 		srv.ociPoolName = nil
 		srv.ociPoolNameLen = 0
 		srv.poolType = NoPool
+		srv.majorVersion = 0
 		srv.Unlock()
 		_drv.srvPool.Put(srv)
 
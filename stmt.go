@@ -14,7 +14,9 @@ import (
 	"bytes"
 	"container/list"
 	"context"
+	"encoding/json"
 	"fmt"
+	"math/big"
 	"reflect"
 	"strings"
 	"sync"
@@ -72,6 +74,7 @@ type Stmt struct {
 	gcts                []GoColumnType
 	bnds                []bnd
 	hasPtrBind          bool
+	argOrder            []int // the $n of the rewritten placeholders, see StmtCfg.SetNumberPlaceholders
 	stringPtrBufferSize int
 	bindInfo
 
@@ -167,6 +170,7 @@ func (stmt *Stmt) close() (err error) {
 		stmt.stmtType = 0
 		stmt.sql = ""
 		stmt.gcts = nil
+		stmt.argOrder = nil
 		stmt.bnds = nil
 		stmt.hasPtrBind = false
 		stmt.bindInfo = bindInfo{}
@@ -229,6 +233,13 @@ func (stmt *Stmt) Exe(params ...interface{}) (rowsAffected uint64, err error) {
 	return rowsAffected, err
 }
 
+// ExeContext is like Exe, but the execution is broken when ctx is done,
+// returning an error with ctx.Err() as its Cause.
+func (stmt *Stmt) ExeContext(ctx context.Context, params ...interface{}) (rowsAffected uint64, err error) {
+	rowsAffected, _, err = stmt.exeC(ctx, params, false)
+	return rowsAffected, err
+}
+
 // ExeP executes an (PL/)SQL statement on an Oracle server returning the number of
 // rows affected and a possible error.
 //
@@ -304,6 +315,18 @@ func (stmt *Stmt) exeC(ctx context.Context, params []interface{}, isAssocArray b
 	if cfg, ok := ctxStmtCfg(ctx); ok {
 		stmt.SetCfg(cfg)
 	}
+	if hasPlsMap(params) {
+		if rowsAffected, err = stmt.exePlsMap(ctx, params); err != nil {
+			return 0, 0, errE(err)
+		}
+		return rowsAffected, 0, nil
+	}
+	// binding LOBs and the execution are round trips, too
+	call, err := stmt.ses.startCall(ctx, stmt.Cfg().callTimeout)
+	if err != nil {
+		return 0, 0, errE(err)
+	}
+	defer func() { err = call.end(err) }()
 	// for case of inserting and returning identity for database/sql package
 	stmt.RLock()
 	pkgEnvInsert := stmt.Env().isPkgEnv && stmt.stmtType == C.OCI_STMT_INSERT
@@ -389,6 +412,14 @@ func (stmt *Stmt) Qry(params ...interface{}) (*Rset, error) {
 	return stmt.qry(params)
 }
 
+// QryContext is like Qry, but the query is broken when ctx is done,
+// returning an error with ctx.Err() as its Cause.
+//
+// ctx limits the fetches of the returned Rset, too: see Rset.NextContext.
+func (stmt *Stmt) QryContext(ctx context.Context, params ...interface{}) (*Rset, error) {
+	return stmt.qryC(ctx, params)
+}
+
 // qry runs a SQL query on an Oracle server returning a *Rset and possible error.
 func (stmt *Stmt) qry(params []interface{}) (rset *Rset, err error) {
 	return stmt.qryC(context.Background(), params)
@@ -411,6 +442,11 @@ func (stmt *Stmt) qryC(ctx context.Context, params []interface{}) (rset *Rset, e
 	if err != nil {
 		return nil, errE(err)
 	}
+	call, err := stmt.ses.startCall(ctx, stmt.Cfg().callTimeout)
+	if err != nil {
+		return nil, errE(err)
+	}
+	defer func() { err = call.end(err) }()
 	_, err = stmt.bind(params, false) // bind parameters
 	if err != nil {
 		return nil, errE(err)
@@ -420,20 +456,31 @@ func (stmt *Stmt) qryC(ctx context.Context, params []interface{}) (rset *Rset, e
 	if err != nil {
 		return nil, errE(err)
 	}
+	scrollable := stmt.Cfg().scrollable
 	// Query statement on Oracle server
 	stmt.RLock()
 	env := stmt.Env()
+	// a PL/SQL block is executed once, and returns its implicit result sets
+	isPlSQL := stmt.stmtType == C.OCI_STMT_BEGIN || stmt.stmtType == C.OCI_STMT_DECLARE
+	var iters C.ub4
+	mode := C.ub4(C.OCI_DEFAULT)
+	if isPlSQL {
+		iters = 1
+		scrollable = false
+	} else if scrollable {
+		mode = C.OCI_STMT_SCROLLABLE_READONLY
+	}
 	stmt.ses.RLock()
 	r := C.OCIStmtExecute(
 		//stmt.ses.ocisvcctx,      //OCISvcCtx           *svchp,
 		stmt.ses.ocisvcctx, //OCISvcCtx           *svchp,
 		stmt.ocistmt,       //OCIStmt             *stmtp,
 		env.ocierr,         //OCIError            *errhp,
-		C.ub4(0),           //ub4                 iters,
+		iters,              //ub4                 iters,
 		C.ub4(0),           //ub4                 rowoff,
 		nil,                //const OCISnapshot   *snap_in,
 		nil,                //OCISnapshot         *snap_out,
-		C.OCI_DEFAULT)      //ub4                 mode );
+		mode)               //ub4                 mode );
 	stmt.ses.RUnlock()
 	hasPtrBind := stmt.hasPtrBind
 	stmt.RUnlock()
@@ -446,10 +493,22 @@ func (stmt *Stmt) qryC(ctx context.Context, params []interface{}) (rset *Rset, e
 			return nil, errE(err)
 		}
 	}
+	if isPlSQL {
+		if rset, err = stmt.nextResultSet(ctx); err != nil || rset != nil {
+			return rset, err
+		}
+		// no implicit result set: return an empty one
+		rset = &Rset{env: env, stmt: stmt, ocistmt: stmt.ocistmt, finished: true, ctx: ctx}
+		rset.id = _drv.rsetId.nextId()
+		stmt.RLock()
+		stmt.openRsets.add(rset)
+		stmt.RUnlock()
+		return rset, nil
+	}
 	// create result set and open
 	// FIXME(tgulacsi): reusing Rsets causes sporadic failures.
 	//rset = _drv.rsetPool.Get().(*Rset)
-	rset = &Rset{}
+	rset = &Rset{scrollable: scrollable, ctx: ctx}
 	//rset.Lock()
 	rset.env = env
 	if rset.id == 0 {
@@ -468,6 +527,49 @@ func (stmt *Stmt) qryC(ctx context.Context, params []interface{}) (rset *Rset, e
 	return rset, nil
 }
 
+// NextResultSet returns the next implicit result set of the executed PL/SQL
+// block, as returned with DBMS_SQL.RETURN_RESULT, or nil if there are no more.
+//
+// Qry of a PL/SQL block returns its first implicit result set.
+//
+// Implicit result sets need Oracle 12.1 or later on both client and server.
+func (stmt *Stmt) NextResultSet() (*Rset, error) {
+	return stmt.nextResultSet(context.Background())
+}
+
+// nextResultSet returns the next implicit result set, fetched within ctx.
+func (stmt *Stmt) nextResultSet(ctx context.Context) (*Rset, error) {
+	stmt.log(_drv.Cfg().Log.Stmt.Qry)
+	if err := stmt.checkClosed(); err != nil {
+		return nil, errE(err)
+	}
+	stmt.RLock()
+	env := stmt.Env()
+	ocistmt, stmtType := stmt.ocistmt, stmt.stmtType
+	stmt.RUnlock()
+	if stmtType != C.OCI_STMT_BEGIN && stmtType != C.OCI_STMT_DECLARE {
+		return nil, nil
+	}
+	var result *C.OCIStmt
+	r := C.stmtGetNextResult(ocistmt, env.ocierr, &result)
+	if r == C.OCI_NO_DATA {
+		return nil, nil
+	} else if r == C.OCI_ERROR {
+		return nil, errE(env.ociError())
+	}
+	// the result's statement handle is owned by the parent statement
+	rset := &Rset{env: env, ctx: ctx}
+	rset.id = _drv.rsetId.nextId()
+	if err := rset.open(stmt, result); err != nil {
+		rset.close()
+		return nil, errE(err)
+	}
+	stmt.RLock()
+	stmt.openRsets.add(rset)
+	stmt.RUnlock()
+	return rset, nil
+}
+
 // setBindPtrs enables binds to set out pointers for some types such as time.Time, etc.
 func (stmt *Stmt) setBindPtrs() (err error) {
 	stmt.RLock()
@@ -517,7 +619,15 @@ func (stmt *Stmt) bind(params []interface{}, isAssocArray bool) (iterations uint
 	iterations = 1
 	stmt.RLock()
 	bnds := stmt.bnds
+	argOrder := stmt.argOrder
 	stmt.RUnlock()
+	if argOrder != nil {
+		arranged, err := arrangeParams(params, argOrder)
+		if err != nil {
+			return iterations, err
+		}
+		params = arranged
+	}
 	if cap(bnds) < len(params) {
 		bnds = make([]bnd, len(params))
 	} else {
@@ -787,6 +897,24 @@ func (stmt *Stmt) bind(params []interface{}, isAssocArray bool) (iterations uint
 			if err != nil {
 				return iterations, err
 			}
+		case *big.Int, *big.Float, *big.Rat:
+			var num OCINum
+			if isNil, err := setBig(&num.OCINum, value); err != nil {
+				return iterations, err
+			} else if isNil {
+				bnd := stmt.getBnd(bndIdxNil).(*bndNil)
+				bnds[n] = bnd
+				if err = bnd.bind(pos, C.SQLT_VNU, stmt); err != nil {
+					return iterations, err
+				}
+				break
+			}
+			bnd := stmt.getBnd(bndIdxOCINum).(*bndOCINum)
+			bnds[n] = bnd
+			err = bnd.bind(num, pos, stmt)
+			if err != nil {
+				return iterations, err
+			}
 
 		case *int64:
 			bnd := stmt.getBnd(bndIdxInt64Ptr).(*bndInt64Ptr)
@@ -1248,6 +1376,13 @@ func (stmt *Stmt) bind(params []interface{}, isAssocArray bool) (iterations uint
 			if err != nil {
 				return iterations, err
 			}
+		case NString:
+			bnd := stmt.getBnd(bndIdxString).(*bndString)
+			bnds[n] = bnd
+			err = bnd.bindForm(string(value), C.SQLCS_NCHAR, pos, stmt)
+			if err != nil {
+				return iterations, err
+			}
 		case *string:
 			bnd := stmt.getBnd(bndIdxStringPtr).(*bndStringPtr)
 			bnds[n] = bnd
@@ -1284,6 +1419,29 @@ func (stmt *Stmt) bind(params []interface{}, isAssocArray bool) (iterations uint
 				return iterations, err
 			}
 			stmt.hasPtrBind = true
+		case Rowid:
+			if value.IsNull {
+				stmt.setNilBind(n, C.SQLT_CHR)
+			} else {
+				bnd := stmt.getBnd(bndIdxString).(*bndString)
+				bnds[n] = bnd
+				err = bnd.bind(value.Value, pos, stmt)
+				if err != nil {
+					return iterations, err
+				}
+			}
+		case *Rowid:
+			bnd := stmt.getBnd(bndIdxStringPtr).(*bndStringPtr)
+			bnds[n] = bnd
+			spbs := stmt.stringPtrBufferSize
+			if spbs == 0 {
+				spbs = stmt.Cfg().stringPtrBufferSize
+			}
+			err = bnd.bind(&(value.Value), &(value.IsNull), pos, spbs, stmt)
+			if err != nil {
+				return iterations, err
+			}
+			stmt.hasPtrBind = true
 		case []string:
 			bnd := stmt.getBnd(bndIdxStringSlice).(*bndStringSlice)
 			bnds[n] = bnd
@@ -1314,6 +1472,14 @@ func (stmt *Stmt) bind(params []interface{}, isAssocArray bool) (iterations uint
 			stmt.hasPtrBind = true
 
 		case bool:
+			if stmt.usePlsBool() {
+				bnd := stmt.getBnd(bndIdxPlsBool).(*bndPlsBool)
+				bnds[n] = bnd
+				if err = bnd.bind(value, false, pos, stmt); err != nil {
+					return iterations, err
+				}
+				break
+			}
 			bnd := stmt.getBnd(bndIdxBool).(*bndBool)
 			bnds[n] = bnd
 			err = bnd.bind(value, pos, stmt.Cfg(), stmt)
@@ -1321,6 +1487,15 @@ func (stmt *Stmt) bind(params []interface{}, isAssocArray bool) (iterations uint
 				return iterations, err
 			}
 		case *bool:
+			if stmt.usePlsBool() {
+				bnd := stmt.getBnd(bndIdxPlsBool).(*bndPlsBool)
+				bnds[n] = bnd
+				if err = bnd.bindPtr(value, pos, stmt); err != nil {
+					return iterations, err
+				}
+				stmt.hasPtrBind = true
+				break
+			}
 			bnd := stmt.getBnd(bndIdxBoolPtr).(*bndBoolPtr)
 			bnds[n] = bnd
 			err = bnd.bind(value, pos, stmt.Cfg().TrueRune, stmt)
@@ -1328,8 +1503,24 @@ func (stmt *Stmt) bind(params []interface{}, isAssocArray bool) (iterations uint
 				return iterations, err
 			}
 			stmt.hasPtrBind = true
+		case *Bool:
+			if !stmt.usePlsBool() {
+				return iterations, errF("*Bool parameters need native PL/SQL BOOLEAN binding (StmtCfg.SetPlsBool, Oracle 12.1+)")
+			}
+			bnd := stmt.getBnd(bndIdxPlsBool).(*bndPlsBool)
+			bnds[n] = bnd
+			if err = bnd.bindBoolPtr(value, pos, stmt); err != nil {
+				return iterations, err
+			}
+			stmt.hasPtrBind = true
 		case Bool:
-			if value.IsNull {
+			if stmt.usePlsBool() {
+				bnd := stmt.getBnd(bndIdxPlsBool).(*bndPlsBool)
+				bnds[n] = bnd
+				if err = bnd.bind(value.Value, value.IsNull, pos, stmt); err != nil {
+					return iterations, err
+				}
+			} else if value.IsNull {
 				stmt.setNilBind(n, C.SQLT_CHR)
 			} else {
 				bnd := stmt.getBnd(bndIdxBool).(*bndBool)
@@ -1401,6 +1592,18 @@ func (stmt *Stmt) bind(params []interface{}, isAssocArray bool) (iterations uint
 				stmt.hasPtrBind = true
 			}
 			stmt.hasPtrBind = true
+		case *LobLocator:
+			if value == nil {
+				stmt.setNilBind(n, C.SQLT_BLOB)
+			} else {
+				bnd := stmt.getBnd(bndIdxLobLocator).(*bndLobLocator)
+				bnds[n] = bnd
+				err = bnd.bind(value, pos, stmt)
+				if err != nil {
+					return iterations, err
+				}
+				stmt.hasPtrBind = true
+			}
 
 		case [][]byte:
 			bnd := stmt.getBnd(bndIdxBinSlice).(*bndBinSlice)
@@ -1488,6 +1691,50 @@ func (stmt *Stmt) bind(params []interface{}, isAssocArray bool) (iterations uint
 				return iterations, err
 			}
 			stmt.hasPtrBind = true
+		case Object:
+			bnd := stmt.getBnd(bndIdxObject).(*bndObject)
+			bnds[n] = bnd
+			err = bnd.bind(&value, false, pos, stmt)
+			if err != nil {
+				return iterations, err
+			}
+		case *Object:
+			bnd := stmt.getBnd(bndIdxObject).(*bndObject)
+			bnds[n] = bnd
+			err = bnd.bind(value, true, pos, stmt)
+			if err != nil {
+				return iterations, err
+			}
+			stmt.hasPtrBind = true
+		case Record:
+			if value.Type == nil {
+				return iterations, errF("Record.Type is required for binding")
+			}
+			obj, err := value.Type.NewObject(value.Value)
+			if err != nil {
+				return iterations, err
+			}
+			bnd := stmt.getBnd(bndIdxObject).(*bndObject)
+			bnds[n] = bnd
+			isPtr := value.Value != nil && reflect.TypeOf(value.Value).Kind() == reflect.Ptr
+			if isPtr {
+				bnd.dest = value.Value
+				stmt.hasPtrBind = true
+			}
+			if err = bnd.bind(obj, isPtr, pos, stmt); err != nil {
+				return iterations, err
+			}
+		case JSON:
+			if bnds[n], err = stmt.bindJSON(value, pos); err != nil {
+				return iterations, err
+			}
+			if value.isPtr() {
+				stmt.hasPtrBind = true
+			}
+		case json.RawMessage:
+			if bnds[n], err = stmt.bindJSON(JSON{Value: value}, pos); err != nil {
+				return iterations, err
+			}
 		default:
 			if v == nil {
 				err = stmt.setNilBind(n, C.SQLT_CHR)
@@ -1512,6 +1759,36 @@ func (stmt *Stmt) NumRset() int {
 	return stmt.openRsets.len()
 }
 
+// arrangeParams returns the params in the order of the rewritten $n
+// placeholders, repeating them as needed.
+func arrangeParams(params []interface{}, argOrder []int) ([]interface{}, error) {
+	arranged := make([]interface{}, len(argOrder))
+	for i, n := range argOrder {
+		if n < 1 || n > len(params) {
+			return nil, errF("no parameter for $%d, got %d parameters", n, len(params))
+		}
+		arranged[i] = params[n-1]
+	}
+	return arranged, nil
+}
+
+// numArgs returns the number of parameters of the rewritten $n placeholders,
+// or -1 if the parameters are bound as given.
+func (stmt *Stmt) numArgs() int {
+	stmt.RLock()
+	defer stmt.RUnlock()
+	if stmt.argOrder == nil {
+		return -1
+	}
+	var max int
+	for _, n := range stmt.argOrder {
+		if n > max {
+			max = n
+		}
+	}
+	return max
+}
+
 type bindInfo struct {
 	BindNames, IndNames []string
 	Duplicates          []bool
@@ -1664,21 +1941,25 @@ func (stmt *Stmt) logF(enabled bool, format string, v ...interface{}) {
 }
 
 // set prefetch size. No locking occurs.
+//
+// Both the row count and the memory size are set, as SetAdaptiveFetch changes
+// them for the executions of the statement: the memory size only limits the
+// prefetch without a row count.
 func (stmt *Stmt) setPrefetchSize() error {
 	cfg := stmt.Cfg()
-	if cfg.prefetchRowCount > 0 {
-		//fmt.Println("stmt.setPrefetchSize: prefetchRowCount ", stmt.Cfg().prefetchRowCount)
-		// set prefetch row count
-		if err := stmt.setAttr(cfg.prefetchRowCount, C.OCI_ATTR_PREFETCH_ROWS); err != nil {
-			return errE(err)
-		}
-	} else if cfg.prefetchMemorySize > 0 {
-		//fmt.Println("stmt.setPrefetchSize: prefetchMemorySize ", stmt.Cfg().prefetchMemorySize)
-		// Set prefetch memory size
-		if err := stmt.setAttr(cfg.prefetchMemorySize, C.OCI_ATTR_PREFETCH_MEMORY); err != nil {
-			return errE(err)
+	rows, mem := cfg.prefetchRowCount, uint32(0)
+	if rows == 0 {
+		mem = cfg.prefetchMemorySize
+		if mem == 0 {
+			rows = 1 // the default of OCI
 		}
 	}
+	if err := stmt.setAttr(rows, C.OCI_ATTR_PREFETCH_ROWS); err != nil {
+		return errE(err)
+	}
+	if err := stmt.setAttr(mem, C.OCI_ATTR_PREFETCH_MEMORY); err != nil {
+		return errE(err)
+	}
 	return nil
 }
 
@@ -1722,6 +2003,17 @@ func (stmt *Stmt) setAttr(attrValue uint32, attrType C.ub4) error {
 	return nil
 }
 
+// usePlsBool reports whether bool parameters are to be bound as native PL/SQL BOOLEAN.
+func (stmt *Stmt) usePlsBool() bool {
+	if !stmt.Cfg().PlsBool() {
+		return false
+	}
+	if stmt.stmtType != C.OCI_STMT_BEGIN && stmt.stmtType != C.OCI_STMT_DECLARE {
+		return false
+	}
+	return stmt.ses.hasPlsTypes()
+}
+
 // setNilBind sets a nil bind. No locking occurs.
 func (stmt *Stmt) setNilBind(index int, sqlt C.ub2) (err error) {
 	bnd := _drv.bndPools[bndIdxNil].Get().(*bndNil)
@@ -4,6 +4,8 @@
 
 package ora
 
+import "time"
+
 // StmtCfg affects various aspects of a SQL statement.
 //
 // Assign values to StmtCfg prior to calling Stmt.Exe
@@ -20,6 +22,13 @@ type StmtCfg struct {
 	stringPtrBufferSize   int
 	fetchLen, lobFetchLen int
 	byteSlice             GoColumnType
+	plsBool               bool
+	scrollable            bool
+	numberPlaceholders    bool
+	callTimeout           time.Duration
+	adaptiveFetch         bool
+	fetchGrowth           bool
+	fetchMemory           int
 
 	// IsAutoCommitting determines whether DML statements are automatically
 	// committed.
@@ -59,6 +68,7 @@ func NewStmtCfg() StmtCfg {
 	var c StmtCfg
 	c.fetchLen = DefaultFetchLen
 	c.lobFetchLen = DefaultLOBFetchLen
+	c.fetchMemory = DefaultFetchMemory
 	c.prefetchRowCount = 128
 	c.prefetchMemorySize = 128 << 20 // 134,217,728
 	c.longBufferSize = 16 << 20      // 16,777,216
@@ -114,6 +124,9 @@ func (c StmtCfg) PrefetchMemorySize() uint32 {
 
 // SetLongBufferSize sets the long buffer size in bytes.
 //
+// LONG values are fetched piecewise in pieces of at most the long buffer
+// size (and at most 1MB), so it does not limit the length of the values.
+//
 // The maximum is 2,147,483,642 bytes.
 //
 // Returns an error if the specified size is less than 1 or greater than 2,147,483,642.
@@ -139,16 +152,17 @@ func (c StmtCfg) SetLongBufferSize(size uint32) StmtCfg {
 // LongBufferSize returns the long buffer size in bytes used to define the sql select-column
 // buffer size of an Oracle LONG type.
 //
-// The default is 16,777,216 bytes.
-//
-// The default is considered a moderate buffer where the 2GB max buffer may not
-// be feasible on all clients.
+// The default is 16,777,216 bytes; the pieces of the piecewise fetch are
+// 1MB at most.
 func (c StmtCfg) LongBufferSize() uint32 {
 	return c.longBufferSize
 }
 
 // SetLongRawBufferSize sets the LONG RAW buffer size in bytes.
 //
+// LONG RAW values are fetched piecewise in pieces of at most the LONG RAW
+// buffer size (and at most 1MB), so it does not limit the length of the values.
+//
 // The maximum is 2,147,483,642 bytes.
 //
 // Returns an error if the specified size is greater than 2,147,483,642.
@@ -168,10 +182,8 @@ func (c StmtCfg) SetLongRawBufferSize(size uint32) StmtCfg {
 // LongRawBufferSize returns the LONG RAW buffer size in bytes used to define the sql select-column
 // buffer size of an Oracle LONG RAW type.
 //
-// The default is 16,777,216 bytes.
-//
-// The default is considered a moderate buffer where the 2GB max buffer may not
-// be feasible on all clients.
+// The default is 16,777,216 bytes; the pieces of the piecewise fetch are
+// 1MB at most.
 func (c StmtCfg) LongRawBufferSize() uint32 {
 	return c.longRawBufferSize
 }
@@ -261,6 +273,84 @@ func (c StmtCfg) ByteSlice() GoColumnType {
 	return c.byteSlice
 }
 
+// SetPlsBool sets whether Go bool parameters of PL/SQL blocks are bound as
+// native PL/SQL BOOLEAN values.
+//
+// Native binding needs Oracle 12.1 or newer, both on the client and the server;
+// with older versions bool parameters are bound as TrueRune and FalseRune, as
+// with SQL statements, so the PL/SQL block has to convert them itself.
+func (c StmtCfg) SetPlsBool(plsBool bool) StmtCfg {
+	c.plsBool = plsBool
+	return c
+}
+
+// PlsBool returns whether Go bool parameters of PL/SQL blocks are bound as
+// native PL/SQL BOOLEAN values.
+//
+// The default is false.
+func (c StmtCfg) PlsBool() bool {
+	return c.plsBool
+}
+
+// SetScrollable sets whether queries are executed with a scrollable cursor,
+// to be navigated with Rset.Prev, First, Last, Absolute and Relative.
+//
+// A scrollable Rset is not closed when Next reaches the end of the rows;
+// it is closed with its Stmt.
+func (c StmtCfg) SetScrollable(scrollable bool) StmtCfg {
+	c.scrollable = scrollable
+	return c
+}
+
+// Scrollable returns whether queries are executed with a scrollable cursor.
+//
+// The default is false.
+func (c StmtCfg) Scrollable() bool {
+	return c.scrollable
+}
+
+// SetNumberPlaceholders sets whether the ? and $1 placeholders of other
+// databases are rewritten to :1..:n when the statement is prepared.
+//
+// The parameters of $n placeholders are bound by n, so they may be repeated
+// or out of order. String literals, q'[...]' quoted strings, quoted
+// identifiers and comments are left untouched. A statement with both ? and
+// $n placeholders is an error.
+func (c StmtCfg) SetNumberPlaceholders(numberPlaceholders bool) StmtCfg {
+	c.numberPlaceholders = numberPlaceholders
+	return c
+}
+
+// NumberPlaceholders returns whether ? and $1 placeholders are rewritten to :1..:n.
+//
+// The default is false.
+func (c StmtCfg) NumberPlaceholders() bool {
+	return c.numberPlaceholders
+}
+
+// SetCallTimeout sets the time limit of each round trip to the server of
+// statement executions, Rset fetches, LOB reads and commits;
+// zero means no limit.
+//
+// The deadline of the context of the call (with the database/sql Context
+// methods) limits the round trips, too, whichever is earlier. With Oracle 18c or newer clients, the limit is enforced by OCI with
+// OCI_ATTR_CALL_TIMEOUT, and the session remains usable; with older clients,
+// the running call is interrupted with Ses.Break.
+//
+// An interrupted call returns an error whose errors.Cause is
+// context.DeadlineExceeded, or context.Canceled for a canceled context.
+func (c StmtCfg) SetCallTimeout(timeout time.Duration) StmtCfg {
+	c.callTimeout = timeout
+	return c
+}
+
+// CallTimeout returns the time limit of each round trip to the server.
+//
+// The default is zero, no limit.
+func (c StmtCfg) CallTimeout() time.Duration {
+	return c.callTimeout
+}
+
 // returns a value of the lobFetchLen
 func (c StmtCfg) LOBFetchLen() int {
 	return c.lobFetchLen
@@ -301,6 +391,69 @@ func (c StmtCfg) SetLOBFetchLen(length int) StmtCfg {
 	return c
 }
 
+// SetAdaptiveFetch sets whether the fetch array length of the result sets is
+// computed from the widths of their columns, instead of FetchLen and
+// LOBFetchLen.
+//
+// With adaptive fetching, the array of a Rset holds as many rows as fit into
+// FetchMemory (at least one, at most MaxFetchLen), and the rows prefetched by
+// OCI are set to the same length and memory. A narrow query thus fetches many
+// rows in a round trip, while a query of LOBs or long strings fetches a few.
+func (c StmtCfg) SetAdaptiveFetch(adaptive bool) StmtCfg {
+	c.adaptiveFetch = adaptive
+	return c
+}
+
+// AdaptiveFetch returns whether the fetch array length of the result sets is
+// computed from the widths of their columns.
+//
+// The default is false.
+func (c StmtCfg) AdaptiveFetch() bool {
+	return c.adaptiveFetch
+}
+
+// SetFetchMemory sets the memory budget in bytes of the fetch array of a
+// result set with adaptive fetching.
+//
+// A size less than 1 restores DefaultFetchMemory.
+func (c StmtCfg) SetFetchMemory(size int) StmtCfg {
+	if size <= 0 {
+		size = DefaultFetchMemory
+	}
+	c.fetchMemory = size
+	return c
+}
+
+// FetchMemory returns the memory budget in bytes of the fetch array of a
+// result set with adaptive fetching.
+//
+// The default is DefaultFetchMemory.
+func (c StmtCfg) FetchMemory() int {
+	if c.fetchMemory <= 0 {
+		return DefaultFetchMemory
+	}
+	return c.fetchMemory
+}
+
+// SetFetchGrowth sets whether a result set with adaptive fetching starts
+// with short fetches, and doubles their length as long as that makes the
+// fetches faster per row, up to the computed array length.
+//
+// This returns the first rows sooner, and does not make round trips for more
+// rows than the network or the server can deliver efficiently.
+func (c StmtCfg) SetFetchGrowth(growth bool) StmtCfg {
+	c.fetchGrowth = growth
+	return c
+}
+
+// FetchGrowth returns whether a result set with adaptive fetching grows the
+// length of its fetches.
+//
+// The default is false.
+func (c StmtCfg) FetchGrowth() bool {
+	return c.fetchGrowth
+}
+
 func (c StmtCfg) SetNumberInt(gct GoColumnType) StmtCfg {
 	c.RsetCfg = c.RsetCfg.SetNumberInt(gct)
 	return c
@@ -350,3 +503,16 @@ func (c StmtCfg) SetLongRaw(gct GoColumnType) StmtCfg {
 	c.RsetCfg = c.RsetCfg.SetLongRaw(gct)
 	return c
 }
+func (c StmtCfg) SetJSON(gct GoColumnType) StmtCfg { c.RsetCfg = c.RsetCfg.SetJSON(gct); return c }
+func (c StmtCfg) SetJSONLobs(jsonLobs bool) StmtCfg {
+	c.RsetCfg = c.RsetCfg.SetJSONLobs(jsonLobs)
+	return c
+}
+func (c StmtCfg) SetRowid(gct GoColumnType) StmtCfg {
+	c.RsetCfg = c.RsetCfg.SetRowid(gct)
+	return c
+}
+func (c StmtCfg) SetStructMode(mode StructMode) StmtCfg {
+	c.RsetCfg = c.RsetCfg.SetStructMode(mode)
+	return c
+}
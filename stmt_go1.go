@@ -14,6 +14,9 @@ import "C"
 
 // NumInput returns the number of placeholders in a sql statement.
 func (stmt *Stmt) NumInput() int {
+	if n := stmt.numArgs(); n >= 0 {
+		return n
+	}
 	bc, err := stmt.attr(4, C.OCI_ATTR_BIND_COUNT)
 	if err != nil {
 		return 0
@@ -12,6 +12,9 @@ import "database/sql/driver"
 //
 // This returns a constant -1, as named params can be less, then positional params.
 func (stmt *Stmt) NumInput() int {
+	if n := stmt.numArgs(); n >= 0 {
+		return n
+	}
 	if bindNames, _, duplicates, err := stmt.getBindInfo(); err == nil {
 		n := len(bindNames)
 		for _, d := range duplicates {
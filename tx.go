@@ -10,6 +10,7 @@ package ora
 */
 import "C"
 import (
+	"context"
 	"fmt"
 	"sync"
 )
@@ -44,6 +45,7 @@ type Tx struct {
 	cmu sync.Mutex
 	id  uint64
 	ses *Ses
+	ctx context.Context // limits Commit
 }
 
 // checkIsOpen validates that the session is open.
@@ -77,6 +79,7 @@ func (tx *Tx) close() (err error) {
 	tx.Lock()
 	if tx.ses != nil {
 		tx.ses = nil
+		tx.ctx = nil
 		ok = true
 	}
 	tx.Unlock()
@@ -93,21 +96,47 @@ func (tx *Tx) Commit() (err error) {
 	if tx == nil {
 		return nil
 	}
+	tx.RLock()
+	ctx := tx.ctx
+	tx.RUnlock()
+	return tx.commit(ctx)
+}
+
+// CommitContext is like Commit, but the commit is broken when ctx is done,
+// returning an error with ctx.Err() as its Cause.
+//
+// ctx replaces the context of Ses.StartTxContext.
+func (tx *Tx) CommitContext(ctx context.Context) (err error) {
+	if tx == nil {
+		return nil
+	}
+	return tx.commit(ctx)
+}
+
+// commit commits the transaction, limited by ctx.
+func (tx *Tx) commit(ctx context.Context) (err error) {
 	tx.log(_drv.Cfg().Log.Tx.Commit)
 	if err = tx.checkIsOpen(); err != nil {
 		return err
 	}
 	defer tx.closeWithRemove()
 	tx.RLock()
+	ses := tx.ses
+	tx.RUnlock()
+	call, err := ses.startCall(ctx, ses.Cfg().StmtCfg.callTimeout)
+	if err != nil {
+		return err
+	}
+	tx.RLock()
 	r := C.OCITransCommit(
 		tx.ses.ocisvcctx,      //OCISvcCtx    *svchp,
 		tx.ses.srv.env.ocierr, //OCIError     *errhp,
 		C.OCI_DEFAULT)         //ub4          flags );
 	tx.RUnlock()
 	if r == C.OCI_ERROR {
-		return tx.ses.srv.env.ociError()
+		err = ses.srv.env.ociError()
 	}
-	return nil
+	return call.end(err)
 }
 
 // Rollback rolls back a transaction.
@@ -128,6 +157,11 @@ func (tx *Tx) Rollback() (err error) {
 		return nil
 	}
 	defer tx.closeWithRemove()
+	// roll back even if the context of the transaction is done
+	call, err := ses.startCall(context.Background(), ses.Cfg().StmtCfg.callTimeout)
+	if err != nil {
+		return err
+	}
 	tx.RLock()
 	r := C.OCITransRollback(
 		tx.ses.ocisvcctx,      //OCISvcCtx    *svchp,
@@ -135,9 +169,9 @@ func (tx *Tx) Rollback() (err error) {
 		C.OCI_DEFAULT)         //ub4          flags );
 	tx.RUnlock()
 	if r == C.OCI_ERROR {
-		return tx.ses.srv.env.ociError()
+		err = ses.srv.env.ociError()
 	}
-	return nil
+	return call.end(err)
 }
 
 // sysName returns a string representing the Tx.
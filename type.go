@@ -436,6 +436,11 @@ func (this *String) UnmarshalJSON(p []byte) error {
 	return json.Unmarshal(p, &this.Value)
 }
 
+// NString is a string bound in the national character set form, for NCHAR,
+// NVARCHAR2 and NCLOB parameters whose characters are not in the database
+// character set. An empty NString is bound as NULL, as string is.
+type NString string
+
 type Num string
 type OraNum struct {
 	IsNull bool
@@ -20,6 +20,7 @@ func checkNumericColumn(gct GoColumnType, columnName string) error {
 		F64, F32,
 		OraF64, OraF32,
 		N, OraN,
+		BigI, BigF, BigR,
 		S:
 		return nil
 	}
@@ -27,7 +28,7 @@ func checkNumericColumn(gct GoColumnType, columnName string) error {
 	if columnName != "" {
 		s = fmt.Sprintf(" (%s)", columnName)
 	}
-	return errF("Invalid go column type (%v) specified for numeric sql column%s. Expected go column type I64, I32, I16, I8, U64, U32, U16, U8, F64, F32, OraI64, OraI32, OraI16, OraI8, OraU64, OraU32, OraU16, OraU8, OraF64, OraF32, N or OraN.", GctName(gct), s)
+	return errF("Invalid go column type (%v) specified for numeric sql column%s. Expected go column type I64, I32, I16, I8, U64, U32, U16, U8, F64, F32, OraI64, OraI32, OraI16, OraI8, OraU64, OraU32, OraU16, OraU8, OraF64, OraF32, N, OraN, BigI, BigF or BigR.", GctName(gct), s)
 }
 
 // checkTimeColumn returns nil when the column type is time; otherwise, an error.
@@ -48,6 +49,24 @@ func checkStringColumn(gct GoColumnType) error {
 	return errF("Invalid go column type (%v) specified for string-based sql column. Expected go column type S or OraS.", GctName(gct))
 }
 
+// checkJSONColumn returns nil when the column type is JSON or string; otherwise, an error.
+func checkJSONColumn(gct GoColumnType) error {
+	switch gct {
+	case J, JV, S, OraS:
+		return nil
+	}
+	return errF("Invalid go column type (%v) specified for JSON sql column. Expected go column type J, JV, S or OraS.", GctName(gct))
+}
+
+// checkRowidColumn returns nil when the column type is Rowid or string; otherwise, an error.
+func checkRowidColumn(gct GoColumnType) error {
+	switch gct {
+	case Rid, S, OraS:
+		return nil
+	}
+	return errF("Invalid go column type (%v) specified for ROWID sql column. Expected go column type Rid, S or OraS.", GctName(gct))
+}
+
 // checkBoolOrStringColumn returns nil when the column type is bool; otherwise, an error.
 func checkBoolOrStringColumn(gct GoColumnType) error {
 	switch gct {
@@ -227,6 +246,11 @@ func (e *oraErr) Error() string {
 	return e.Underlying.Error()
 }
 
+// Cause returns the underlying error, for github.com/pkg/errors.Cause.
+func (e *oraErr) Cause() error {
+	return e.Underlying
+}
+
 func (e oraErr) Code() int {
 	if e.Underlying == nil {
 		return 0
@@ -250,9 +274,14 @@ func (e oraErr) Code() int {
 type DescribedColumn struct {
 	Column
 
-	Schema                 string
-	Nullable               bool
-	CharsetID, CharsetForm int
+	Schema string
+
+	// Deprecated: use Column.Nullable.
+	Nullable bool
+	// Deprecated: use Column.CharsetID.
+	CharsetID int
+	// Deprecated: use Column.CharsetForm.
+	CharsetForm int
 }
 
 // DescribeQuery parses the query and returns the column types, as
@@ -315,11 +344,14 @@ END;`, qry, &res,
 			col.Schema, line = string(line[:j]), line[j+1:]
 		}
 		if n, err := fmt.Sscanf(string(line), "%s %d %d %d %d %d %d %d",
-			&col.Name, &col.Type, &col.Length, &col.Precision, &col.Scale, &nullable, &col.CharsetID, &col.CharsetForm,
+			&col.Name, &col.Type, &col.Length, &col.Precision, &col.Scale, &nullable, &col.Column.CharsetID, &col.Column.CharsetForm,
 		); err != nil {
 			return cols, fmt.Errorf("parsing %q (parsed: %d): %v", line, n, err)
 		}
-		col.Nullable = nullable != 0
+		col.Column.Nullable = nullable != 0
+		// the deprecated copies
+		col.Nullable = col.Column.Nullable
+		col.CharsetID, col.CharsetForm = col.Column.CharsetID, col.Column.CharsetForm
 		cols = append(cols, col)
 	}
 	return cols, nil
@@ -355,24 +387,22 @@ func GetCompileErrors(ses *Ses, all bool) ([]CompileError, error) {
 	}
 	var errors []CompileError
 	for rset.Next() {
-		warn := rset.Row[7].(string) == "WARNING"
-		if warn && !all {
-			continue
+		var ce CompileError
+		var attribute string
+		if err = rset.Scan(&ce.Owner, &ce.Name, &ce.Type,
+			&ce.Line, &ce.Position, &ce.Code, &ce.Text, &attribute,
+		); err != nil {
+			rset.Exhaust()
+			return errors, err
 		}
-		if len(rset.Row) != 8 {
-			panic(fmt.Sprintf("rset.Row=%#v", rset.Row))
+		ce.Warning = attribute == "WARNING"
+		if ce.Warning && !all {
+			continue
 		}
-		errors = append(errors,
-			CompileError{
-				Owner:    rset.Row[0].(string),
-				Name:     rset.Row[1].(string),
-				Type:     rset.Row[2].(string),
-				Line:     int64(rset.Row[3].(float64)),
-				Position: int64(rset.Row[4].(float64)),
-				Code:     int64(rset.Row[5].(float64)),
-				Text:     rset.Row[6].(string),
-				Warning:  warn,
-			})
+		errors = append(errors, ce)
+	}
+	if err = rset.Err(); err != nil {
+		return errors, err
 	}
 	return errors, nil
 }
@@ -115,3 +115,104 @@ decriptorAllocSlice(
 	}
 	return OCI_SUCCESS;
 }
+
+// typeByFullName returns the TDO of a (PL/SQL package) type,
+// or OCI_ERROR if the client does not support it.
+sword
+typeByFullName(
+	OCIEnv *env,
+	OCIError *err,
+	const OCISvcCtx *svc,
+	const oratext *full_type_name,
+	ub4 full_type_name_length,
+	OCIType **tdo
+) {
+#if HAS_PLSQL_TYPES
+	return OCITypeByFullName(
+		env,
+		err,
+		svc,
+		full_type_name,
+		full_type_name_length,
+		NULL,
+		0,
+		OCI_DURATION_SESSION,
+		OCI_TYPEGET_ALL,
+		tdo);
+#else
+	return OCI_ERROR;
+#endif
+}
+
+// stmtGetNextResult returns the next implicit result of the executed statement,
+// or OCI_NO_DATA if there are no more (or the client does not support them).
+sword
+stmtGetNextResult(
+	OCIStmt *stmtp,
+	OCIError *err,
+	OCIStmt **result
+) {
+#if HAS_IMPLICIT_RESULTS
+	ub4 rtype;
+	return OCIStmtGetNextResult(stmtp, err, (void **)result, &rtype, OCI_DEFAULT);
+#else
+	return OCI_NO_DATA;
+#endif
+}
+
+// jsonDescAlloc allocates an OCIJson descriptor,
+// or returns OCI_ERROR if the client does not support it.
+sword
+jsonDescAlloc(
+	OCIEnv *env,
+	void **jsond
+) {
+#if HAS_JSON
+	return OCIDescriptorAlloc(env, jsond, OCI_DTYPE_JSON, 0, NULL);
+#else
+	return OCI_ERROR;
+#endif
+}
+
+sword
+jsonDescFree(
+	void *jsond
+) {
+#if HAS_JSON
+	return OCIDescriptorFree(jsond, OCI_DTYPE_JSON);
+#else
+	return OCI_ERROR;
+#endif
+}
+
+// jsonToBinary copies the OSON image of the JSON descriptor into buf.
+sword
+jsonToBinary(
+	OCISvcCtx *svc,
+	void *jsond,
+	ub1 *buf,
+	oraub8 *buf_sz,
+	OCIError *err
+) {
+#if HAS_JSON
+	return OCIJsonToBinaryBuffer(svc, (OCIJson *)jsond, buf, buf_sz, err, OCI_DEFAULT);
+#else
+	return OCI_ERROR;
+#endif
+}
+
+// jsonFromBinary loads the OSON image in buf into the JSON descriptor.
+sword
+jsonFromBinary(
+	OCISvcCtx *svc,
+	void *jsond,
+	ub1 *buf,
+	oraub8 buf_sz,
+	OCIError *err
+) {
+#if HAS_JSON
+	return OCIJsonBinaryBufferLoad(svc, (OCIJson *)jsond, buf, buf_sz, err, OCI_DEFAULT);
+#else
+	return OCI_ERROR;
+#endif
+}
@@ -60,7 +60,69 @@
 	#define OCILOBWRITE                 OCILobWrite
 #endif
 
+// PL/SQL BOOLEAN and RECORD binding needs 12.1 client (and server)
+#if ORACLE_VERSION_HEX >= ORACLE_VERSION(12,1)
+	#define HAS_PLSQL_TYPES             1
+#else
+	#define HAS_PLSQL_TYPES             0
+#endif
+// implicit results (DBMS_SQL.RETURN_RESULT) need 12.1 client (and server)
+#if ORACLE_VERSION_HEX >= ORACLE_VERSION(12,1)
+	#define HAS_IMPLICIT_RESULTS        1
+#else
+	#define HAS_IMPLICIT_RESULTS        0
+#endif
+#ifndef SQLT_BOL
+	#define SQLT_BOL                    252
+#endif
+#ifndef OCI_TYPECODE_BOOLEAN
+	#define OCI_TYPECODE_BOOLEAN        SQLT_BOL
+#endif
+#ifndef OCI_TYPECODE_RECORD
+	#define OCI_TYPECODE_RECORD         250
+#endif
+#ifndef OCI_ATTR_PACKAGE_NAME
+	// only used with HAS_PLSQL_TYPES
+	#define OCI_ATTR_PACKAGE_NAME       0
+#endif
+#ifndef OCI_TYPECODE_BINARY_INTEGER
+	#define OCI_TYPECODE_BINARY_INTEGER 265
+#endif
+#ifndef OCI_TYPECODE_PLS_INTEGER
+	#define OCI_TYPECODE_PLS_INTEGER    266
+#endif
+
+// the JSON data type needs 21c client (and server)
+#if ORACLE_VERSION_HEX >= ORACLE_VERSION(21,1)
+	#define HAS_JSON                    1
+#else
+	#define HAS_JSON                    0
+#endif
+#ifndef SQLT_JSON
+	#define SQLT_JSON                   119
+#endif
+// the IS JSON check constraint of a column is reported since 19c
+#if ORACLE_VERSION_HEX >= ORACLE_VERSION(19,1)
+	#define HAS_JSON_COL                1
+#else
+	#define HAS_JSON_COL                0
+#endif
+#ifndef OCI_ATTR_JSON_COL
+	#define OCI_ATTR_JSON_COL           611
+#endif
+
+// OCI_ATTR_CALL_TIMEOUT needs 18c client
+#if ORACLE_VERSION_HEX >= ORACLE_VERSION(18,1)
+	#define HAS_CALL_TIMEOUT            1
+#else
+	#define HAS_CALL_TIMEOUT            0
+#endif
+#ifndef OCI_ATTR_CALL_TIMEOUT
+	#define OCI_ATTR_CALL_TIMEOUT       531
+#endif
+
 #define sof_DateTimep sizeof(OCIDateTime*)
+#define sof_Jsonp sizeof(void*)
 #define sof_Intervalp sizeof(OCIInterval*)
 #define sof_LobLocatorp sizeof(OCILobLocator*)
 #define sof_Stmtp sizeof(OCIStmt*)
@@ -111,3 +173,49 @@ decriptorAllocSlice(
 	ub4 type,
 	size_t length
 );
+
+sword
+typeByFullName(
+	OCIEnv *env,
+	OCIError *err,
+	const OCISvcCtx *svc,
+	const oratext *full_type_name,
+	ub4 full_type_name_length,
+	OCIType **tdo
+);
+
+sword
+stmtGetNextResult(
+	OCIStmt *stmtp,
+	OCIError *err,
+	OCIStmt **result
+);
+
+sword
+jsonDescAlloc(
+	OCIEnv *env,
+	void **jsond
+);
+
+sword
+jsonDescFree(
+	void *jsond
+);
+
+sword
+jsonToBinary(
+	OCISvcCtx *svc,
+	void *jsond,
+	ub1 *buf,
+	oraub8 *buf_sz,
+	OCIError *err
+);
+
+sword
+jsonFromBinary(
+	OCISvcCtx *svc,
+	void *jsond,
+	ub1 *buf,
+	oraub8 buf_sz,
+	OCIError *err
+);
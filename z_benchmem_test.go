@@ -182,6 +182,70 @@ func BenchmarkSelect(b *testing.B) {
 	}
 }
 
+// BenchmarkSelectRow and BenchmarkSelectNoRow compare the allocations of
+// Rset.Next, which boxes each value into Rset.Row, and Rset.NextNoRow with
+// the typed accessors.
+//
+// go test -run=^$ -bench='SelectN?o?Row' -benchmem
+func BenchmarkSelectRow(b *testing.B) {
+	benchSelectNative(b, false)
+}
+
+func BenchmarkSelectNoRow(b *testing.B) {
+	benchSelectNative(b, true)
+}
+
+func benchSelectNative(b *testing.B, noRow bool) {
+	geoTableOnce.Do(func() {
+		if err := createGeoTable(); err != nil {
+			b.Fatal(err)
+		}
+	})
+	testSes := getSes(b)
+	defer testSes.Close()
+	stmt, err := testSes.Prep("SELECT record_id, location FROM "+geoTableName, ora.I64, ora.S)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer stmt.Close()
+	var length int
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; {
+		rset, err := stmt.Qry()
+		if err != nil {
+			b.Fatal(err)
+		}
+		for i < b.N {
+			if noRow {
+				if !rset.NextNoRow() {
+					break
+				}
+				if _, err = rset.Int64(0); err != nil {
+					b.Fatal(err)
+				}
+				p, err := rset.Bytes(1)
+				if err != nil {
+					b.Fatal(err)
+				}
+				length += len(p)
+			} else {
+				if !rset.Next() {
+					break
+				}
+				_ = rset.Row[0].(int64)
+				length += len(rset.Row[1].(string))
+			}
+			i++
+		}
+		if err = rset.Err(); err != nil {
+			b.Fatal(err)
+		}
+		rset.Exhaust()
+	}
+	b.SetBytes(int64(length / b.N))
+}
+
 func BenchmarkPrepare(b *testing.B) {
 	rows, err := testDb.Query("SELECT A.object_name from user_objects A")
 	if err != nil {
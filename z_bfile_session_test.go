@@ -6,6 +6,7 @@ package ora_test
 
 import (
 	"fmt"
+	"io/ioutil"
 	"testing"
 
 	"gopkg.in/rana/ora.v4"
@@ -33,3 +34,70 @@ func TestBindDefine_bfile(t *testing.T) {
 		})
 	}
 }
+
+func TestBfileRead(t *testing.T) {
+	testSes := getSes(t)
+	defer testSes.Close()
+
+	var dir string
+	rset, err := testSes.PrepAndQry("SELECT directory_name FROM all_directories WHERE directory_name IN ('TEMP_DIR', 'DATA_PUMP_DIR') ORDER BY 1 DESC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rset.Next() {
+		if dir == "" {
+			dir = rset.Row[0].(string)
+		}
+	}
+	if dir == "" {
+		t.Skip("no directory to test with")
+	}
+
+	const content = "árvíztűrő tükörfúrógép"
+	filename := tableName() + ".txt"
+	if _, err = testSes.PrepAndExe(`DECLARE
+  f UTL_FILE.FILE_TYPE;
+BEGIN
+  f := UTL_FILE.FOPEN(:1, :2, 'wb');
+  UTL_FILE.PUT_RAW(f, UTL_RAW.CAST_TO_RAW(:3));
+  UTL_FILE.FCLOSE(f);
+END;`, dir, filename, content); err != nil {
+		t.Skipf("write %s/%s: %v", dir, filename, err)
+	}
+	defer testSes.PrepAndExe("BEGIN UTL_FILE.FREMOVE(:1, :2); END;", dir, filename)
+
+	// a fetched BFILE
+	rset, err = testSes.PrepAndQry("SELECT BFILENAME(:1, :2), BFILENAME(:1, 'nonexistent-'||:2) FROM DUAL", dir, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rset.Next() {
+		t.Fatal("no rows:", rset.Err())
+	}
+	bf, missing := rset.Row[0].(ora.Bfile), rset.Row[1].(ora.Bfile)
+	rset.Exhaust()
+
+	if exists, err := testSes.BfileExists(missing); err != nil || exists {
+		t.Errorf("%v exists: %t (%v)", missing, exists, err)
+	}
+	if exists, err := testSes.BfileExists(bf); err != nil || !exists {
+		t.Fatalf("%v exists: %t (%v)", bf, exists, err)
+	}
+	if size, err := testSes.BfileSize(bf); err != nil || size != int64(len(content)) {
+		t.Errorf("size: got %d (%v), wanted %d", size, err, len(content))
+	}
+	r, err := testSes.OpenBfile(ora.Bfile{DirectoryAlias: dir, Filename: filename})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(r)
+	if closeErr := r.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != content {
+		t.Errorf("got %q, wanted %q", b, content)
+	}
+}
@@ -77,3 +77,42 @@ func TestRapidCancelIssue192(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestImplicitResults(t *testing.T) {
+	rows, err := testDb.Query(implicitResultsQry)
+	if err != nil {
+		t.Skipf("implicit results are not supported: %v", err)
+	}
+	defer rows.Close()
+	var got []int
+	for {
+		var n int
+		for rows.Next() {
+			n++
+		}
+		got = append(got, n)
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != 2 || got[1] != 1 {
+		t.Errorf("got %v rows, wanted [2 1]", got)
+	}
+}
+
+func TestQueryContextDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	const qry = "SELECT COUNT(0) FROM all_objects A, all_objects B, all_objects C"
+	var n int64
+	err := testDb.QueryRowContext(ctx, qry).Scan(&n)
+	if err != context.DeadlineExceeded {
+		t.Errorf("got %v, wanted %v", err, context.DeadlineExceeded)
+	}
+	if err = testDb.QueryRow("SELECT 1 FROM DUAL").Scan(&n); err != nil {
+		t.Error(err)
+	}
+}
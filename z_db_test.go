@@ -10,8 +10,10 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"math"
 	"math/rand"
 	"os"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
@@ -251,6 +253,87 @@ func TestZeroRowsAffected(t *testing.T) {
 	}
 }
 
+func TestColumnTypes(t *testing.T) {
+	t.Parallel()
+	tableName := tableName()
+	if _, err := testDb.Exec("CREATE TABLE " + tableName + ` (
+		id NUMBER(10) NOT NULL, amount NUMBER(12,2), name VARCHAR2(20 CHAR),
+		code CHAR(3 BYTE) NOT NULL, nname NVARCHAR2(10), ts TIMESTAMP(3) WITH TIME ZONE,
+		doc CLOB)`); err != nil {
+		t.Fatal(err)
+	}
+	defer testDb.Exec("DROP TABLE " + tableName)
+	qry := "SELECT id, amount, name, code, nname, ts, doc FROM " + tableName
+
+	type want struct {
+		typeName         string
+		nullable         bool
+		length           int64
+		lengthOK         bool
+		precision, scale int64
+		precisionScaleOK bool
+	}
+	wants := []want{
+		{"NUMBER(10)", false, 0, false, 10, 0, true},
+		{"NUMBER(12,2)", true, 0, false, 12, 2, true},
+		{"VARCHAR2(20 CHAR)", true, 20, true, 0, 0, false},
+		{"CHAR(3)", false, 3, true, 0, 0, false},
+		{"NVARCHAR2(10)", true, 10, true, 0, 0, false},
+		{"TIMESTAMP(3) WITH TIME ZONE", true, 0, false, 0, 0, false},
+		{"CLOB", true, math.MaxInt64, true, 0, 0, false},
+	}
+
+	rows, err := testDb.Query(qry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	cts, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, ct := range cts {
+		w := wants[i]
+		if nullable, ok := ct.Nullable(); !ok || nullable != w.nullable {
+			t.Errorf("%d. %s: got nullable %t (%t), wanted %t", i, ct.Name(), nullable, ok, w.nullable)
+		}
+		if length, ok := ct.Length(); ok != w.lengthOK || length != w.length {
+			t.Errorf("%d. %s: got length %d (%t), wanted %d (%t)", i, ct.Name(), length, ok, w.length, w.lengthOK)
+		}
+		if p, s, ok := ct.DecimalSize(); ok != w.precisionScaleOK || p != w.precision || s != w.scale {
+			t.Errorf("%d. %s: got precision, scale %d, %d (%t), wanted %d, %d (%t)", i, ct.Name(), p, s, ok, w.precision, w.scale, w.precisionScaleOK)
+		}
+	}
+
+	testSes := getSes(t)
+	defer testSes.Close()
+	stmt, err := testSes.Prep(qry, ora.I64, ora.OraF64, ora.S, ora.S, ora.OraS, ora.OraT, ora.S)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+	rset, err := stmt.Qry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	goTypes := []reflect.Type{
+		reflect.TypeOf(int64(0)), reflect.TypeOf(ora.Float64{}), reflect.TypeOf(""), reflect.TypeOf(""),
+		reflect.TypeOf(ora.String{}), reflect.TypeOf(ora.Time{}), reflect.TypeOf(""),
+	}
+	for i, c := range rset.Columns {
+		if c.TypeName != wants[i].typeName || c.Nullable != wants[i].nullable || c.GoType != goTypes[i] {
+			t.Errorf("%d. %s: got %q, %t, %v, wanted %q, %t, %v", i, c.Name, c.TypeName, c.Nullable, c.GoType, wants[i].typeName, wants[i].nullable, goTypes[i])
+		}
+	}
+	if c := rset.Columns[2]; !c.CharUsed || c.CharLength != 20 || c.CharsetID == 0 {
+		t.Errorf("%s: got CharUsed=%t CharLength=%d CharsetID=%d", c.Name, c.CharUsed, c.CharLength, c.CharsetID)
+	}
+	if c := rset.Columns[4]; c.CharsetForm != 2 {
+		t.Errorf("%s: got CharsetForm=%d, wanted 2 (SQLCS_NCHAR)", c.Name, c.CharsetForm)
+	}
+	rset.Exhaust()
+}
+
 func Test_db(t *testing.T) {
 	for valName, tc := range map[string]struct {
 		gen    func() interface{}
@@ -188,3 +188,67 @@ func TestEnv_SesCfg(t *testing.T) {
 		t.Errorf("stmt.Cfg: wanted %s=%d, got %s=%d (default: %s)", x, x, y, y, old.NumberBigFloat())
 	}
 }
+
+func TestEnv_Charset(t *testing.T) {
+	t.Parallel()
+	for _, strict := range []bool{false, true} {
+		env, err := ora.OpenEnv(ora.EnvCfg{Charset: "EE8ISO8859P2", StrictCharset: strict})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer env.Close()
+		if cs := env.Charset(); cs != "EE8ISO8859P2" {
+			t.Errorf("got charset %q, wanted EE8ISO8859P2", cs)
+		}
+		srv, err := env.OpenSrv(testSrvCfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer srv.Close()
+		ses, err := srv.OpenSes(testSesCfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ses.Close()
+
+		want := "árvíztűrő tükörfúrógép"
+		qry := "SELECT :1, 'árvíztűrő' FROM DUAL"
+		rset, err := ses.PrepAndQry(qry, want)
+		if err != nil {
+			t.Fatal(qry, err)
+		}
+		if !rset.Next() {
+			t.Fatal(qry, "no rows", rset.Err())
+		}
+		if got := rset.Row[0].(string); got != want {
+			t.Errorf("bind: got %q, wanted %q", got, want)
+		}
+		if got := rset.Row[1].(string); got != "árvíztűrő" {
+			t.Errorf("literal: got %q, wanted %q", got, "árvíztűrő")
+		}
+		rset.Exhaust()
+
+		// ISO-8859-2 has no €
+		rset, err = ses.PrepAndQry("SELECT :1 FROM DUAL", "100 €")
+		if strict {
+			if err == nil {
+				t.Error("wanted error for unmappable character")
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !rset.Next() {
+			t.Fatal("no rows", rset.Err())
+		}
+		if got := rset.Row[0].(string); got == "100 €" {
+			t.Errorf("got %q, wanted a replaced €", got)
+		}
+		rset.Exhaust()
+	}
+
+	if _, err := ora.OpenEnv(ora.EnvCfg{Charset: "NO_SUCH_CHARSET"}); err == nil {
+		t.Error("wanted error for unknown charset")
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"gopkg.in/rana/ora.v4"
+)
+
+func Test_json_session(t *testing.T) {
+	testSes := getSes(t)
+	defer testSes.Close()
+
+	type doc struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+	want := doc{Name: "ora", Tags: []string{"go", "oracle"}}
+
+	for _, colType := range []string{"JSON", "CLOB CHECK (c IS JSON)", "BLOB CHECK (c IS JSON)"} {
+		tableName := tableName()
+		if _, err := testSes.PrepAndExe("CREATE TABLE " + tableName + " (id NUMBER(3), c " + colType + ")"); err != nil {
+			if colType == "JSON" {
+				t.Logf("JSON data type is not supported: %v", err)
+				continue
+			}
+			t.Fatal(err)
+		}
+		defer testSes.PrepAndExe("DROP TABLE " + tableName)
+
+		var in interface{} = ora.JSON{Value: want}
+		if colType != "JSON" {
+			// IS JSON LOB columns take the JSON text
+			b, err := json.Marshal(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			in = json.RawMessage(b)
+			if colType[0] == 'B' {
+				in = b
+			}
+		}
+		if _, err := testSes.PrepAndExe("INSERT INTO "+tableName+" (id, c) VALUES (1, :1)", in); err != nil {
+			t.Fatalf("%s: %v", colType, err)
+		}
+
+		if colType != "JSON" {
+			// the IS JSON LOB columns are CLOBs and BLOBs by default
+			rset, err := testSes.PrepAndQry("SELECT c FROM " + tableName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !rset.Next() {
+				t.Fatalf("%s: %v", colType, rset.Err())
+			}
+			switch rset.Row[0].(type) {
+			case string, []byte:
+			default:
+				t.Errorf("%s: got %T, wanted string or []byte", colType, rset.Row[0])
+			}
+			rset.Exhaust()
+		}
+
+		// J by default, with SetJSONLobs for the IS JSON LOB columns
+		stmt, err := testSes.Prep("SELECT c FROM " + tableName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stmt.SetCfg(stmt.Cfg().SetJSONLobs(true))
+		rset, err := stmt.Qry()
+		if err != nil {
+			stmt.Close()
+			t.Fatal(err)
+		}
+		if !rset.Next() {
+			stmt.Close()
+			t.Fatalf("%s: %v", colType, rset.Err())
+		}
+		raw, ok := rset.Row[0].(json.RawMessage)
+		stmt.Close()
+		if !ok {
+			// older clients can't tell IS JSON LOB columns
+			t.Logf("%s: got %T, wanted json.RawMessage", colType, rset.Row[0])
+			continue
+		}
+		var got doc
+		if err = json.Unmarshal(raw, &got); err != nil {
+			t.Fatalf("%s: %s: %v", colType, raw, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %#v, wanted %#v", colType, got, want)
+		}
+
+		// JV
+		stmt, err = testSes.Prep("SELECT c FROM "+tableName, ora.JV)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rset, err = stmt.Qry()
+		if err != nil {
+			stmt.Close()
+			t.Fatal(err)
+		}
+		if !rset.Next() {
+			stmt.Close()
+			t.Fatalf("%s: %v", colType, rset.Err())
+		}
+		m, ok := rset.Row[0].(map[string]interface{})
+		stmt.Close()
+		if !ok || m["name"] != "ora" {
+			t.Errorf("%s: got %#v, wanted map with name=ora", colType, rset.Row[0])
+		}
+
+		if colType != "JSON" {
+			continue
+		}
+		// IN OUT
+		var out doc
+		if _, err = testSes.PrepAndExe("BEGIN SELECT c INTO :1 FROM "+tableName+"; END;", ora.JSON{Value: &out}); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(out, want) {
+			t.Errorf("OUT: got %#v, wanted %#v", out, want)
+		}
+	}
+}
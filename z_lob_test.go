@@ -288,6 +288,168 @@ END test_get_json;`,
 	}
 }
 
+func TestLobLocator(t *testing.T) {
+	testSes := getSes(t)
+	defer testSes.Close()
+
+	tbl := tableName()
+	if _, err := testSes.PrepAndExe("CREATE TABLE " + tbl + " (id NUMBER(3), b BLOB, c CLOB)"); err != nil {
+		t.Fatal(err)
+	}
+	defer testSes.PrepAndExe("DROP TABLE " + tbl)
+
+	tx, err := testSes.StartTx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	loc := new(ora.LobLocator)
+	if _, err = testSes.PrepAndExe(
+		"INSERT INTO "+tbl+" (id, b, c) VALUES (1, EMPTY_BLOB(), 'árvíztűrő') RETURNING b INTO :1",
+		loc,
+	); err != nil {
+		t.Fatal(err)
+	}
+	if loc.IsNull() {
+		t.Fatal("no locator returned")
+	}
+	chunk, err := loc.ChunkSize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("chunk size: %d", chunk)
+	if err = loc.Open(false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = loc.Write([]byte("0123456789")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = loc.WriteAt([]byte("abc"), 3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = loc.Append([]byte("XYZ")); err != nil {
+		t.Fatal(err)
+	}
+	if err = loc.Trim(1); err != nil {
+		t.Fatal(err)
+	}
+	if err = loc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err = loc.Free(); err != nil {
+		t.Fatal(err)
+	}
+
+	rset, err := testSes.PrepAndQry("SELECT b, c FROM "+tbl+" WHERE id = 1 FOR UPDATE", ora.Loc, ora.Loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rset.Next() {
+		t.Fatal("no rows:", rset.Err())
+	}
+	b, c := rset.Row[0].(*ora.LobLocator), rset.Row[1].(*ora.LobLocator)
+	defer b.Free()
+	defer c.Free()
+	rset.Exhaust()
+
+	if size, err := b.Size(); err != nil || size != 12 {
+		t.Errorf("BLOB size: got %d (%v), wanted 12", size, err)
+	}
+	p := make([]byte, 5)
+	if n, err := b.ReadAt(p, 2); err != nil || string(p[:n]) != "2abc6" {
+		t.Errorf("ReadAt: got %q (%v), wanted %q", p[:n], err, "2abc6")
+	}
+	if _, err = b.Seek(-4, io.SeekEnd); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ioutil.ReadAll(b); err != nil || string(got) != "89XY" {
+		t.Errorf("ReadAll: got %q (%v), wanted %q", got, err, "89XY")
+	}
+	if err = b.Truncate(3); err != nil {
+		t.Fatal(err)
+	}
+	if size, err := b.Size(); err != nil || size != 3 {
+		t.Errorf("truncated BLOB size: got %d (%v), wanted 3", size, err)
+	}
+
+	// CLOB offsets are in characters
+	if size, err := c.Size(); err != nil || size != 9 {
+		t.Errorf("CLOB size: got %d (%v), wanted 9", size, err)
+	}
+	if _, err = c.WriteAt([]byte("Ő"), 8); err != nil {
+		t.Fatal(err)
+	}
+	p = make([]byte, 16)
+	if n, chars, err := c.ReadCharsAt(p, 6); err != io.EOF || string(p[:n]) != "űrŐ" || chars != 3 {
+		t.Errorf("CLOB ReadCharsAt: got %q, %d (%v), wanted %q, 3", p[:n], chars, err, "űrŐ")
+	}
+	if _, err := c.ReadAt(p, 0); err == nil {
+		t.Error("CLOB ReadAt: wanted an error")
+	}
+}
+
+func TestLobLocatorWriter(t *testing.T) {
+	testSes := getSes(t)
+	defer testSes.Close()
+
+	tbl := tableName()
+	if _, err := testSes.PrepAndExe("CREATE TABLE " + tbl + " (id NUMBER(3), b BLOB, c CLOB)"); err != nil {
+		t.Fatal(err)
+	}
+	defer testSes.PrepAndExe("DROP TABLE " + tbl)
+
+	b, c := new(ora.LobLocator), &ora.LobLocator{C: true}
+	if _, err := testSes.PrepAndExe(
+		"INSERT INTO "+tbl+" (id, b, c) VALUES (1, EMPTY_BLOB(), EMPTY_CLOB()) RETURNING b, c INTO :1, :2",
+		b, c,
+	); err != nil {
+		t.Fatal(err)
+	}
+	defer b.Free()
+	defer c.Free()
+
+	want := bytes.Repeat([]byte("árvíztűrő tükörfúrógép\n"), 100000)
+	for _, loc := range []*ora.LobLocator{b, c} {
+		w, err := loc.Writer()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// odd sized writes split the characters
+		for p := want; len(p) > 0; {
+			n := 4093
+			if n > len(p) {
+				n = len(p)
+			}
+			if _, err = w.Write(p[:n]); err != nil {
+				t.Fatal(err)
+			}
+			p = p[n:]
+		}
+		if err = w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var gotB, gotC string
+	rset, err := testSes.PrepAndQry("SELECT b, c FROM "+tbl+" WHERE id = 1", ora.Bin, ora.S)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rset.Next() {
+		gotB, gotC = string(rset.Row[0].([]byte)), rset.Row[1].(string)
+	}
+	if err = rset.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if gotB != string(want) {
+		t.Errorf("BLOB: got %d bytes, wanted %d", len(gotB), len(want))
+	}
+	if gotC != string(want) {
+		t.Errorf("CLOB: got %d bytes, wanted %d", len(gotC), len(want))
+	}
+}
+
 func TestLobIssue156(t *testing.T) {
 	tbl := tableName()
 	qry := `CREATE TABLE ` + tbl + `
@@ -6,6 +6,7 @@ package ora_test
 
 import (
 	"fmt"
+	"math/big"
 	"reflect"
 	"strconv"
 	"strings"
@@ -265,3 +266,53 @@ func TestIssue144(t *testing.T) {
 		t.Logf("%d. %T: %v", tN, dest, reflect.ValueOf(dest).Elem().Interface())
 	}
 }
+
+func TestBindDefine_big(t *testing.T) {
+	testSes := getSes(t)
+	defer testSes.Close()
+
+	tableName := tableName()
+	if _, err := testSes.PrepAndExe("CREATE TABLE " + tableName + " (id NUMBER(38,0), amount NUMBER(30,10))"); err != nil {
+		t.Fatal(err)
+	}
+	defer testSes.PrepAndExe("DROP TABLE " + tableName)
+
+	id, _ := new(big.Int).SetString("12345678901234567890123456789012345678", 10)
+	amount, _ := new(big.Rat).SetString("12345678901234567890.0123456789")
+	if _, err := testSes.PrepAndExe("INSERT INTO "+tableName+" (id, amount) VALUES (:1, :2)", id, amount); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testSes.PrepAndExe("INSERT INTO "+tableName+" (id, amount) VALUES (:1, :2)", (*big.Int)(nil), (*big.Rat)(nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, err := testSes.Prep("SELECT id, amount, amount FROM "+tableName+" ORDER BY id", ora.BigI, ora.BigR, ora.BigF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+	rset, err := stmt.Qry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rset.Next() {
+		t.Fatal(rset.Err())
+	}
+	if got, ok := rset.Row[0].(*big.Int); !ok || got.Cmp(id) != 0 {
+		t.Errorf("id: got %v, wanted %v", rset.Row[0], id)
+	}
+	if got, ok := rset.Row[1].(*big.Rat); !ok || got.Cmp(amount) != 0 {
+		t.Errorf("amount: got %v, wanted %v", rset.Row[1], amount.FloatString(10))
+	}
+	if got, ok := rset.Row[2].(*big.Float); !ok || got.Text('f', 10) != amount.FloatString(10) {
+		t.Errorf("amount: got %v, wanted %v", rset.Row[2], amount.FloatString(10))
+	}
+	if !rset.Next() {
+		t.Fatal(rset.Err())
+	}
+	for i, v := range rset.Row {
+		if v != nil {
+			t.Errorf("%d. got %v, wanted nil", i, v)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora_test
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/rana/ora.v4"
+)
+
+func Test_object_session(t *testing.T) {
+	testSes := getSes(t)
+	defer testSes.Close()
+
+	for _, qry := range []string{
+		`CREATE OR REPLACE TYPE TST_ora_num_varr AS VARRAY(10) OF NUMBER(9)`,
+		`CREATE OR REPLACE TYPE TST_ora_obj_typ AS OBJECT (
+  id NUMBER(9), name VARCHAR2(30), nums TST_ora_num_varr)`,
+		`CREATE OR REPLACE TYPE TST_ora_obj_tab AS TABLE OF TST_ora_obj_typ`,
+	} {
+		if _, err := testSes.PrepAndExe(qry); err != nil {
+			t.Fatal(err)
+		}
+		checkCompile(t, testSes)
+	}
+
+	type elem struct {
+		ID   int64 `db:"id"`
+		Name string
+		Nums []int64
+	}
+
+	// define
+	rset, err := testSes.PrepAndQry(`SELECT TST_ora_obj_typ(1, 'one', TST_ora_num_varr(1, 2, 3)) FROM DUAL`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rset.Next() {
+		t.Fatal(rset.Err())
+	}
+	obj, ok := rset.Row[0].(*ora.Object)
+	if !ok {
+		t.Fatalf("got %T, wanted *ora.Object", rset.Row[0])
+	}
+	var got elem
+	if err = obj.Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if want := (elem{ID: 1, Name: "one", Nums: []int64{1, 2, 3}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, wanted %#v", got, want)
+	}
+	rset.Exhaust()
+
+	// bind IN OUT collection of objects
+	typ, err := testSes.DescribeType("TST_ora_obj_tab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if typ.Kind != ora.ObjectKindTable || typ.Elem == nil || typ.Elem.Type == nil {
+		t.Fatalf("bad type description: %#v", typ)
+	}
+	tab, err := typ.NewObject([]elem{{ID: 1, Name: "one"}, {ID: 2, Name: "two", Nums: []int64{2}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = testSes.PrepAndExe(`DECLARE
+  v_tab TST_ora_obj_tab := :1;
+BEGIN
+  v_tab.EXTEND;
+  v_tab(v_tab.LAST) := TST_ora_obj_typ(v_tab.COUNT, 'added', NULL);
+  :1 := v_tab;
+END;`, tab); err != nil {
+		t.Fatal(err)
+	}
+	var elems []elem
+	if err = tab.Decode(&elems); err != nil {
+		t.Fatal(err)
+	}
+	want := []elem{{ID: 1, Name: "one"}, {ID: 2, Name: "two", Nums: []int64{2}}, {ID: 3, Name: "added"}}
+	if !reflect.DeepEqual(elems, want) {
+		t.Errorf("got %#v, wanted %#v", elems, want)
+	}
+}
+
+func Test_plsRecordBool_session(t *testing.T) {
+	testSes := getSes(t)
+	defer testSes.Close()
+
+	for _, qry := range []string{
+		`CREATE OR REPLACE PACKAGE TST_ora_rec_pkg AS
+  TYPE rec_typ IS RECORD (id PLS_INTEGER, name VARCHAR2(30), valid BOOLEAN);
+  PROCEDURE bump(p_rec IN OUT rec_typ, p_valid OUT BOOLEAN);
+END TST_ora_rec_pkg;`,
+		`CREATE OR REPLACE PACKAGE BODY TST_ora_rec_pkg AS
+  PROCEDURE bump(p_rec IN OUT rec_typ, p_valid OUT BOOLEAN) IS
+  BEGIN
+    p_rec.id := p_rec.id + 1;
+    p_rec.valid := NOT NVL(p_rec.valid, FALSE);
+    p_valid := p_rec.valid;
+  END bump;
+END TST_ora_rec_pkg;`,
+	} {
+		if _, err := testSes.PrepAndExe(qry); err != nil {
+			t.Fatal(err)
+		}
+		checkCompile(t, testSes)
+	}
+
+	typ, err := testSes.DescribeType("TST_ora_rec_pkg.rec_typ")
+	if err != nil {
+		t.Skipf("describe record (needs Oracle 12.1+): %v", err)
+	}
+	if typ.Kind != ora.ObjectKindRecord {
+		t.Fatalf("got kind %s, wanted %s", typ.Kind, ora.ObjectKindRecord)
+	}
+
+	type rec struct {
+		ID    int64
+		Name  string
+		Valid bool
+	}
+	r := rec{ID: 1, Name: "one"}
+	var valid bool
+	stmt, err := testSes.Prep(`BEGIN TST_ora_rec_pkg.bump(:1, :2); END;`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+	stmt.SetCfg(stmt.Cfg().SetPlsBool(true))
+	if _, err = stmt.Exe(ora.Record{Type: typ, Value: &r}, &valid); err != nil {
+		t.Fatal(err)
+	}
+	if want := (rec{ID: 2, Name: "one", Valid: true}); r != want {
+		t.Errorf("got %#v, wanted %#v", r, want)
+	}
+	if !valid {
+		t.Errorf("got valid=%t, wanted true", valid)
+	}
+}
@@ -241,3 +241,70 @@ func TestIssue188(t *testing.T) {
 		t.Errorf("Want \"test\", got %#v", sret)
 	}
 }
+
+func Test_plsarr_map_session(t *testing.T) {
+	testSes := getSes(t)
+	defer testSes.Close()
+
+	t.Parallel()
+	for _, qry := range []string{
+		`CREATE OR REPLACE PACKAGE TST_ora_plsarr_map AS
+  TYPE int_tab_typ IS TABLE OF NUMBER INDEX BY PLS_INTEGER;
+  TYPE vc_tab_typ IS TABLE OF VARCHAR2(100) INDEX BY VARCHAR2(30);
+  PROCEDURE double_it(p_nums IN OUT int_tab_typ);
+  PROCEDURE upper_it(p_strs IN OUT vc_tab_typ, p_count OUT PLS_INTEGER);
+END TST_ora_plsarr_map;`,
+		`CREATE OR REPLACE PACKAGE BODY TST_ora_plsarr_map AS
+  PROCEDURE double_it(p_nums IN OUT int_tab_typ) IS
+    i PLS_INTEGER;
+  BEGIN
+    i := p_nums.FIRST;
+    WHILE i IS NOT NULL LOOP
+      p_nums(i) := 2 * p_nums(i);
+      i := p_nums.NEXT(i);
+    END LOOP;
+    p_nums(1000) := 1000;
+  END double_it;
+
+  PROCEDURE upper_it(p_strs IN OUT vc_tab_typ, p_count OUT PLS_INTEGER) IS
+    k VARCHAR2(30);
+  BEGIN
+    k := p_strs.FIRST;
+    WHILE k IS NOT NULL LOOP
+      p_strs(k) := UPPER(p_strs(k));
+      k := p_strs.NEXT(k);
+    END LOOP;
+    p_count := p_strs.COUNT;
+  END upper_it;
+END TST_ora_plsarr_map;`,
+	} {
+		if _, err := testSes.PrepAndExe(qry); err != nil {
+			t.Fatal(err)
+		}
+		checkCompile(t, testSes)
+	}
+
+	nums := map[int32]int64{-5: 1, 7: 2, 100: 3}
+	if _, err := testSes.PrepAndExeP(`BEGIN TST_ora_plsarr_map.double_it(:1); END;`,
+		ora.PlsMap{Type: "TST_ora_plsarr_map.int_tab_typ", Value: &nums, Cap: 10},
+	); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[int32]int64{-5: 2, 7: 4, 100: 6, 1000: 1000}; !reflect.DeepEqual(nums, want) {
+		t.Errorf("got %v, wanted %v", nums, want)
+	}
+
+	strs := map[string]string{"a": "alpha", "b": "beta"}
+	var count int32
+	if _, err := testSes.PrepAndExeP(`BEGIN TST_ora_plsarr_map.upper_it(:strs, :cnt); END;`,
+		ora.PlsMap{Type: "TST_ora_plsarr_map.vc_tab_typ", Value: &strs}, &count,
+	); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"a": "ALPHA", "b": "BETA"}; !reflect.DeepEqual(strs, want) {
+		t.Errorf("got %v, wanted %v", strs, want)
+	}
+	if count != 2 {
+		t.Errorf("got count=%d, wanted 2", count)
+	}
+}
@@ -5,10 +5,15 @@
 package ora_test
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"strings"
 	"testing"
+	"time"
 
 	"gopkg.in/rana/ora.v4"
+	"gopkg.in/rana/ora.v4/export"
 )
 
 func Test_cursor1_session(t *testing.T) {
@@ -107,3 +112,479 @@ END;`)
 	for rset.Next() {
 	}
 }
+
+func Test_nestedCursor_session(t *testing.T) {
+	t.Parallel()
+	testSes := getSes(t)
+	defer testSes.Close()
+
+	qry := `SELECT LEVEL id, CURSOR(SELECT LEVEL n FROM DUAL CONNECT BY LEVEL <= 2) cur
+		FROM DUAL CONNECT BY LEVEL <= 3`
+	stmt, err := testSes.Prep(qry)
+	if err != nil {
+		t.Fatal(qry, err)
+	}
+	defer stmt.Close()
+	rset, err := stmt.Qry()
+	if err != nil {
+		t.Fatal(qry, err)
+	}
+	var rows int
+	for rset.Next() {
+		rows++
+		cur, ok := rset.Row[1].(*ora.Rset)
+		if !ok {
+			t.Fatalf("%d. got %T, wanted *ora.Rset", rows, rset.Row[1])
+		}
+		var n int
+		for cur.Next() {
+			n++
+		}
+		if err = cur.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if n != 2 {
+			t.Errorf("%d. got %d nested rows, wanted 2", rows, n)
+		}
+	}
+	// the exhausted nested cursors leave the parent's Stmt open
+	if err = rset.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if rows != 3 {
+		t.Errorf("got %d rows, wanted 3", rows)
+	}
+	if !stmt.IsOpen() {
+		t.Error("the Stmt is closed")
+	}
+}
+
+const implicitResultsQry = `DECLARE
+  c1 SYS_REFCURSOR;
+  c2 SYS_REFCURSOR;
+BEGIN
+  OPEN c1 FOR SELECT 1 FROM DUAL UNION ALL SELECT 2 FROM DUAL;
+  DBMS_SQL.RETURN_RESULT(c1);
+  OPEN c2 FOR SELECT 'a', 'b' FROM DUAL;
+  DBMS_SQL.RETURN_RESULT(c2);
+END;`
+
+func Test_implicitResults_session(t *testing.T) {
+	testSes := getSes(t)
+	defer testSes.Close()
+
+	stmt, err := testSes.Prep(implicitResultsQry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+	if _, err = stmt.Exe(); err != nil {
+		t.Skipf("implicit results are not supported: %v", err)
+	}
+	var got []int
+	for {
+		rset, err := stmt.NextResultSet()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rset == nil {
+			break
+		}
+		var n int
+		for rset.Next() {
+			n++
+		}
+		if err = rset.Err(); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, n*10+len(rset.Columns))
+	}
+	if fmt.Sprintf("%v", got) != "[21 12]" {
+		t.Errorf("got %v (rows*10+columns), wanted [21 12]", got)
+	}
+}
+
+func Test_scrollable_session(t *testing.T) {
+	testSes := getSes(t)
+	defer testSes.Close()
+
+	stmt, err := testSes.Prep(`SELECT LEVEL FROM DUAL CONNECT BY LEVEL <= 10`, ora.I64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+	stmt.SetCfg(stmt.Cfg().SetScrollable(true).SetFetchLen(3))
+	rset, err := stmt.Qry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	current := func() int64 {
+		if len(rset.Row) == 0 {
+			return -1
+		}
+		return rset.Row[0].(int64)
+	}
+	for i, step := range []struct {
+		name string
+		move func() bool
+		ok   bool
+		want int64
+		pos  int
+	}{
+		{"Next", rset.Next, true, 1, 1},
+		{"Next", rset.Next, true, 2, 2},
+		{"Last", rset.Last, true, 10, 10},
+		{"Prev", rset.Prev, true, 9, 9},
+		{"Absolute(5)", func() bool { return rset.Absolute(5) }, true, 5, 5},
+		{"Relative(-3)", func() bool { return rset.Relative(-3) }, true, 2, 2},
+		{"Relative(4)", func() bool { return rset.Relative(4) }, true, 6, 6},
+		{"Next", rset.Next, true, 7, 7},
+		{"First", rset.First, true, 1, 1},
+		{"Prev", rset.Prev, false, -1, 0},
+		{"Next", rset.Next, true, 1, 1},
+		{"Absolute(11)", func() bool { return rset.Absolute(11) }, false, -1, 11},
+		{"Prev", rset.Prev, true, 10, 10},
+		{"Next", rset.Next, false, -1, 11},
+		{"Prev", rset.Prev, true, 10, 10},
+	} {
+		if ok := step.move(); ok != step.ok {
+			t.Fatalf("%d. %s: got %t, wanted %t (%v)", i, step.name, ok, step.ok, rset.Err())
+		}
+		if got := current(); got != step.want {
+			t.Errorf("%d. %s: got row %d, wanted %d", i, step.name, got, step.want)
+		}
+		if got := rset.Position(); got != step.pos {
+			t.Errorf("%d. %s: got position %d, wanted %d", i, step.name, got, step.pos)
+		}
+	}
+	if err = rset.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_scan_session(t *testing.T) {
+	t.Parallel()
+	testSes := getSes(t)
+	defer testSes.Close()
+
+	qry := `SELECT 42 num, 'árvíztűrő' str, TO_DATE('2017-03-04', 'YYYY-MM-DD') dt,
+		CAST(NULL AS VARCHAR2(10)) nul, 2.5 flt FROM DUAL`
+	// the same Scan must work whatever the GoColumnTypes are
+	for _, gcts := range [][]ora.GoColumnType{
+		nil,
+		{ora.I64, ora.S, ora.T, ora.S, ora.F64},
+		{ora.OraN, ora.OraS, ora.OraT, ora.OraS, ora.OraF64},
+	} {
+		stmt, err := testSes.Prep(qry, gcts...)
+		if err != nil {
+			t.Fatal(qry, err)
+		}
+		rset, err := stmt.Qry()
+		if err != nil {
+			stmt.Close()
+			t.Fatal(qry, err)
+		}
+		if !rset.Next() {
+			t.Fatal("no rows", rset.Err())
+		}
+		var (
+			num  int
+			str  string
+			dt   time.Time
+			nul  ora.String
+			flt  float32
+			nulP *string
+		)
+		if err = rset.Scan(&num, &str, &dt, &nul, &flt); err != nil {
+			t.Fatalf("%v: %v", gcts, err)
+		}
+		if num != 42 || str != "árvíztűrő" || dt.Format("2006-01-02") != "2017-03-04" || flt != 2.5 {
+			t.Errorf("%v: got %d, %q, %v, %v", gcts, num, str, dt, flt)
+		}
+		if !nul.IsNull && nul.Value != "" {
+			t.Errorf("%v: got %#v, wanted null", gcts, nul)
+		}
+		var dummy interface{}
+		if err = rset.Scan(&dummy, &dummy, &dummy, &nulP, &dummy); err != nil {
+			t.Errorf("%v: %v", gcts, err)
+		}
+		// conversion errors are returned, not panics
+		if err = rset.Scan(&num, &num, &dummy, &dummy, &dummy); err == nil {
+			t.Errorf("%v: wanted error for scanning %q into int", gcts, str)
+		}
+		if err = rset.Scan(&num); err == nil {
+			t.Errorf("%v: wanted error for too few destinations", gcts)
+		}
+		rset.Exhaust()
+		stmt.Close()
+	}
+}
+
+func Test_scanStruct_session(t *testing.T) {
+	t.Parallel()
+	testSes := getSes(t)
+	defer testSes.Close()
+
+	type item struct {
+		Num  int64 `db:"n"`
+		Text string
+	}
+	type row struct {
+		ID    int
+		Name  ora.String
+		Items []item `db:"items"`
+	}
+	qry := `SELECT 1 id, 'one' name,
+			CURSOR(SELECT LEVEL n, 'x'||LEVEL text FROM DUAL CONNECT BY LEVEL <= 2) items
+		FROM DUAL
+		UNION ALL
+		SELECT 2, NULL, CURSOR(SELECT 3 n, 'y' text FROM DUAL) FROM DUAL`
+	var rows []row
+	if err := testSes.QryStructs(&rows, qry); err != nil {
+		t.Fatal(qry, err)
+	}
+	want := []row{
+		{ID: 1, Name: ora.String{Value: "one"}, Items: []item{{1, "x1"}, {2, "x2"}}},
+		{ID: 2, Items: []item{{3, "y"}}},
+	}
+	if fmt.Sprintf("%v", rows) != fmt.Sprintf("%v", want) {
+		t.Errorf("got %v, wanted %v", rows, want)
+	}
+
+	// a ref cursor, and strictness
+	stmt, err := testSes.Prep("BEGIN OPEN :1 FOR SELECT 1 id, 'a' name, 'b' extra FROM DUAL; END;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+	stmt.SetCfg(stmt.Cfg().SetStructMode(ora.StructColumns))
+	var rset ora.Rset
+	if _, err = stmt.Exe(&rset); err != nil {
+		t.Fatal(err)
+	}
+	if !rset.Next() {
+		t.Fatal("no rows", rset.Err())
+	}
+	var r row
+	if err = rset.ScanStruct(&r); err == nil {
+		t.Error("wanted error for the EXTRA column")
+	}
+	rset.Exhaust()
+}
+
+func Test_nextBatch_session(t *testing.T) {
+	t.Parallel()
+	testSes := getSes(t)
+	defer testSes.Close()
+
+	const rows = 1000
+	qry := `SELECT LEVEL id, LEVEL/4 flt, 'r'||LEVEL str,
+			DECODE(MOD(LEVEL, 3), 0, NULL, TO_DATE('2017-03-04', 'YYYY-MM-DD')+LEVEL) dt,
+			UTL_RAW.CAST_TO_RAW('x') bin, TO_CLOB('c') clb
+		FROM DUAL CONNECT BY LEVEL <= ` + fmt.Sprintf("%d", rows)
+	stmt, err := testSes.Prep(qry, ora.I64, ora.F64, ora.S, ora.OraT, ora.Bin, ora.S)
+	if err != nil {
+		t.Fatal(qry, err)
+	}
+	defer stmt.Close()
+	stmt.SetCfg(stmt.Cfg().SetPrefetchRowCount(0).SetFetchLen(128))
+	rset, err := stmt.Qry()
+	if err != nil {
+		t.Fatal(qry, err)
+	}
+	wantKinds := []ora.VectorKind{ora.VectorInt64, ora.VectorFloat64, ora.VectorString, ora.VectorTime, ora.VectorBytes, ora.VectorAny}
+	tz, err := testSes.Timezone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := time.Date(2017, 3, 4, 0, 0, 0, 0, tz)
+	var n int
+	for {
+		b, err := rset.NextBatch(300)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.Len == 0 || b.Len > 300 {
+			t.Fatalf("got batch of %d rows", b.Len)
+		}
+		for j, v := range b.Columns {
+			if v.Kind != wantKinds[j] {
+				t.Fatalf("%d. %s: got kind %s, wanted %s", j, v.Name, v.Kind, wantKinds[j])
+			}
+		}
+		id, flt, str, dt, bin, clb := &b.Columns[0], &b.Columns[1], &b.Columns[2], &b.Columns[3], &b.Columns[4], &b.Columns[5]
+		for i := 0; i < b.Len; i++ {
+			n++
+			if id.Int64s[i] != int64(n) || flt.Float64s[i] != float64(n)/4 || str.String(i) != fmt.Sprintf("r%d", n) {
+				t.Fatalf("%d. got %d, %v, %q", n, id.Int64s[i], flt.Float64s[i], str.String(i))
+			}
+			if n%3 == 0 {
+				if !dt.IsNull(i) {
+					t.Errorf("%d. got %v, wanted NULL", n, dt.Time(i))
+				}
+			} else if got, want := dt.Time(i), base.AddDate(0, 0, n); !got.Equal(want) {
+				t.Errorf("%d. got %v, wanted %v", n, got, want)
+			}
+			if string(bin.Bytes(i)) != "x" || clb.Values[i] != "c" {
+				t.Errorf("%d. got %q, %#v", n, bin.Bytes(i), clb.Values[i])
+			}
+		}
+	}
+	if n != rows {
+		t.Errorf("got %d rows, wanted %d", n, rows)
+	}
+	if rset.IsOpen() {
+		t.Error("Rset is open after io.EOF")
+	}
+}
+
+func Test_nextNoRow_session(t *testing.T) {
+	t.Parallel()
+	testSes := getSes(t)
+	defer testSes.Close()
+
+	const rows = 300
+	qry := `SELECT LEVEL id, LEVEL/4 flt, 'r'||LEVEL str,
+			DECODE(MOD(LEVEL, 3), 0, NULL, TO_DATE('2017-03-04', 'YYYY-MM-DD')+LEVEL) dt,
+			TO_CLOB('c') clb
+		FROM DUAL CONNECT BY LEVEL <= ` + fmt.Sprintf("%d", rows)
+	stmt, err := testSes.Prep(qry, ora.I64, ora.F64, ora.S, ora.OraT, ora.S)
+	if err != nil {
+		t.Fatal(qry, err)
+	}
+	defer stmt.Close()
+	stmt.SetCfg(stmt.Cfg().SetPrefetchRowCount(0).SetFetchLen(128))
+	rset, err := stmt.Qry()
+	if err != nil {
+		t.Fatal(qry, err)
+	}
+	tz, err := testSes.Timezone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := time.Date(2017, 3, 4, 0, 0, 0, 0, tz)
+	var n int
+	for rset.NextNoRow() {
+		n++
+		if rset.Row != nil {
+			t.Fatalf("%d. got Row %v, wanted nil", n, rset.Row)
+		}
+		id, err := rset.Int64(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		flt, err := rset.Float64(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		str, err := rset.String(2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != int64(n) || flt != float64(n)/4 || str != fmt.Sprintf("r%d", n) {
+			t.Fatalf("%d. got %d, %v, %q", n, id, flt, str)
+		}
+		dt, err := rset.Time(3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n%3 == 0 {
+			if !rset.IsNull(3) || !dt.IsZero() {
+				t.Errorf("%d. got %v, wanted NULL", n, dt)
+			}
+		} else if want := base.AddDate(0, 0, n); rset.IsNull(3) || !dt.Equal(want) {
+			t.Errorf("%d. got %v, wanted %v", n, dt, want)
+		}
+		if clb, err := rset.String(4); err != nil || clb != "c" {
+			t.Errorf("%d. got %q (%v), wanted %q", n, clb, err, "c")
+		}
+		var sid string
+		var sflt float64
+		var sdt *time.Time
+		var sclb []byte
+		if err = rset.Scan(&sid, &sflt, &str, &sdt, &sclb); err != nil {
+			t.Fatal(err)
+		}
+		if sid != fmt.Sprintf("%d", n) || sflt != flt || (sdt == nil) != (n%3 == 0) || string(sclb) != "c" {
+			t.Errorf("%d. Scan got %q, %v, %v, %q", n, sid, sflt, sdt, sclb)
+		}
+	}
+	if err = rset.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n != rows {
+		t.Errorf("got %d rows, wanted %d", n, rows)
+	}
+	if _, err = rset.Int64(0); err == nil {
+		t.Error("wanted error without a current row")
+	}
+}
+
+func Test_export_session(t *testing.T) {
+	t.Parallel()
+	testSes := getSes(t)
+	defer testSes.Close()
+
+	qry := `SELECT LEVEL id, 'r'||LEVEL str, DECODE(MOD(LEVEL, 2), 0, NULL, LEVEL/4) flt
+		FROM DUAL CONNECT BY LEVEL <= 10`
+	rset, err := testSes.PrepAndQry(qry)
+	if err != nil {
+		t.Fatal(qry, err)
+	}
+	var buf bytes.Buffer
+	n, err := export.Write(&buf, export.FromRset(rset), export.Options{Format: export.CSV, Header: true, Null: "NULL"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if n != 10 || len(lines) != 11 {
+		t.Fatalf("got %d rows, %d lines:\n%s", n, len(lines), buf.String())
+	}
+	if lines[0] != "ID,STR,FLT" || lines[1] != "1,r1,0.25" || lines[2] != "2,r2,NULL" {
+		t.Errorf("got\n%s", buf.String())
+	}
+}
+
+func Test_adaptiveFetch_session(t *testing.T) {
+	t.Parallel()
+	testSes := getSes(t)
+	defer testSes.Close()
+
+	for _, tc := range []struct {
+		qry  string
+		gcts []ora.GoColumnType
+		rows int64
+	}{
+		{`SELECT LEVEL id FROM DUAL CONNECT BY LEVEL <= 3000`, []ora.GoColumnType{ora.I64}, 3000},
+		{`SELECT LEVEL id, TO_CLOB(LPAD('x', 100, 'x')) clb FROM DUAL CONNECT BY LEVEL <= 50`,
+			[]ora.GoColumnType{ora.I64, ora.S}, 50},
+	} {
+		stmt, err := testSes.Prep(tc.qry, tc.gcts...)
+		if err != nil {
+			t.Fatal(tc.qry, err)
+		}
+		stmt.SetCfg(stmt.Cfg().SetAdaptiveFetch(true).SetFetchGrowth(true).SetFetchMemory(1 << 20))
+		rset, err := stmt.Qry()
+		if err != nil {
+			stmt.Close()
+			t.Fatal(tc.qry, err)
+		}
+		var n int64
+		for rset.Next() {
+			n++
+			if rset.Row[0] != n {
+				t.Fatalf("%s: %d. got %v", tc.qry, n, rset.Row[0])
+			}
+		}
+		if err = rset.Err(); err != nil {
+			t.Error(tc.qry, err)
+		}
+		stmt.Close()
+		if n != tc.rows {
+			t.Errorf("%s: got %d rows, wanted %d", tc.qry, n, tc.rows)
+		}
+	}
+}
@@ -6,7 +6,10 @@ package ora_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
+
+	"gopkg.in/rana/ora.v4"
 )
 
 // test on heap table to retreive ROWID
@@ -103,3 +106,90 @@ func testRowid(isUrowid bool, t *testing.T) {
 		}
 	}
 }
+
+func TestRowid_session(t *testing.T) {
+	t.Parallel()
+	testSes := getSes(t)
+	defer testSes.Close()
+
+	for _, iot := range []bool{false, true} {
+		tbl := tableName()
+		qry := "CREATE TABLE " + tbl + " (id NUMBER(3) PRIMARY KEY, c1 VARCHAR2(48))"
+		if iot {
+			qry += " ORGANIZATION INDEX"
+		}
+		if _, err := testSes.PrepAndExe(qry); err != nil {
+			t.Fatal(qry, err)
+		}
+		defer testSes.PrepAndExe("DROP TABLE " + tbl)
+		qry = "INSERT INTO " + tbl + " (id, c1) VALUES (1, 'go')"
+		if _, err := testSes.PrepAndExe(qry); err != nil {
+			t.Fatal(qry, err)
+		}
+
+		qry = "SELECT ROWID, id FROM " + tbl
+		stmt, err := testSes.Prep(qry, ora.Rid)
+		if err != nil {
+			t.Fatal(qry, err)
+		}
+		defer stmt.Close()
+		rset, err := stmt.Qry()
+		if err != nil {
+			t.Fatal(qry, err)
+		}
+		if !rset.Next() {
+			t.Fatal(qry, "no rows", rset.Err())
+		}
+		rowid, ok := rset.Row[0].(ora.Rowid)
+		if !ok {
+			t.Fatalf("%s: got %T, wanted ora.Rowid", qry, rset.Row[0])
+		}
+		rset.Exhaust()
+		t.Logf("iot=%t rowid=%q", iot, rowid.Value)
+		if iot {
+			if !strings.HasPrefix(rowid.Value, "*") || rowid.IsExtended() {
+				t.Errorf("wanted an UROWID, got %q", rowid.Value)
+			}
+		} else {
+			x, err := rowid.Extended()
+			if err != nil {
+				t.Fatal(err)
+			}
+			qry = `SELECT DBMS_ROWID.ROWID_OBJECT(:1), DBMS_ROWID.ROWID_RELATIVE_FNO(:1),
+					DBMS_ROWID.ROWID_BLOCK_NUMBER(:1), DBMS_ROWID.ROWID_ROW_NUMBER(:1) FROM DUAL`
+			stmt, err := testSes.Prep(qry, ora.I64, ora.I64, ora.I64, ora.I64)
+			if err != nil {
+				t.Fatal(qry, err)
+			}
+			defer stmt.Close()
+			rset, err := stmt.Qry(rowid.Value, rowid.Value, rowid.Value, rowid.Value)
+			if err != nil {
+				t.Fatal(qry, err)
+			}
+			if !rset.Next() {
+				t.Fatal(qry, "no rows", rset.Err())
+			}
+			want := ora.ExtendedRowid{
+				Object: uint32(rset.Row[0].(int64)), File: uint16(rset.Row[1].(int64)),
+				Block: uint32(rset.Row[2].(int64)), Row: uint16(rset.Row[3].(int64)),
+			}
+			rset.Exhaust()
+			if x != want {
+				t.Errorf("got %+v, wanted %+v", x, want)
+			}
+			if back := x.Rowid(); back != rowid {
+				t.Errorf("got %q, wanted %q", back.Value, rowid.Value)
+			}
+		}
+
+		// bind back
+		qry = "UPDATE " + tbl + " SET c1 = 'go go' WHERE ROWID = :1"
+		n, err := testSes.PrepAndExe(qry, rowid)
+		if err != nil {
+			t.Fatal(qry, err)
+		}
+		if n != 1 {
+			t.Errorf("%s: updated %d rows, wanted 1", qry, n)
+		}
+	}
+}
@@ -5,8 +5,10 @@
 package ora_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	ora "gopkg.in/rana/ora.v4"
 
@@ -214,6 +216,113 @@ func Benchmark_SimpleInsert(b *testing.B) {
 	}
 }
 
+func TestStmt_numberPlaceholders(t *testing.T) {
+	testSes := getSes(t)
+	defer testSes.Close()
+
+	cfg := testSes.Cfg()
+	testSes.SetCfg(cfg.SetNumberPlaceholders(true))
+	defer testSes.SetCfg(cfg)
+
+	for _, tc := range []struct {
+		qry    string
+		params []interface{}
+		want   string
+	}{
+		{"SELECT ?||'?'||q'[?]'||? FROM DUAL", []interface{}{"a", "b"}, "a??b"},
+		{"SELECT $2||'$1'||$1||$2 FROM DUAL", []interface{}{"a", "b"}, "b$1ab"},
+	} {
+		rset, err := testSes.PrepAndQry(tc.qry, tc.params...)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.qry, err)
+		}
+		if !rset.Next() {
+			t.Fatalf("%q: %v", tc.qry, rset.Err())
+		}
+		if got := rset.Row[0].(string); got != tc.want {
+			t.Errorf("%q: got %q, wanted %q", tc.qry, got, tc.want)
+		}
+		rset.Exhaust()
+	}
+}
+
+func TestStmt_callTimeout(t *testing.T) {
+	testSes := getSes(t)
+	defer testSes.Close()
+
+	cfg := testSes.Cfg()
+	testSes.SetCfg(cfg.SetCallTimeout(500 * time.Millisecond))
+	defer testSes.SetCfg(cfg)
+
+	const qry = "SELECT COUNT(0) FROM all_objects A, all_objects B, all_objects C"
+	start := time.Now()
+	rset, err := testSes.PrepAndQry(qry)
+	if err == nil {
+		for rset.Next() {
+		}
+		err = rset.Err()
+	}
+	if errors.Cause(err) != context.DeadlineExceeded {
+		t.Fatalf("got %v, wanted %v", err, context.DeadlineExceeded)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("the call took %s", d)
+	}
+	// the session is still usable
+	if _, err = testSes.PrepAndQry("SELECT 1 FROM DUAL"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStmt_context(t *testing.T) {
+	testSes := getSes(t)
+	defer testSes.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := testSes.PrepAndExeContext(ctx, "BEGIN NULL; END;"); errors.Cause(err) != context.Canceled {
+		t.Errorf("PrepAndExeContext: got %v, wanted %v", err, context.Canceled)
+	}
+	if _, err := testSes.StartTxContext(ctx); errors.Cause(err) != context.Canceled {
+		t.Errorf("StartTxContext: got %v, wanted %v", err, context.Canceled)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	const qry = "SELECT COUNT(0) FROM all_objects A, all_objects B, all_objects C"
+	time.AfterFunc(500*time.Millisecond, cancel)
+	start := time.Now()
+	rset, err := testSes.PrepAndQryContext(ctx, qry)
+	if err == nil {
+		for rset.Next() {
+		}
+		err = rset.Err()
+	}
+	if errors.Cause(err) != context.Canceled {
+		t.Fatalf("got %v, wanted %v", err, context.Canceled)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("the call took %s", d)
+	}
+
+	// a live context doesn't disturb the native API
+	ctx = context.Background()
+	tx, err := testSes.StartTxContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rset, err = testSes.PrepAndQryContext(ctx, "SELECT 1 FROM DUAL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rset.NextContext(ctx) {
+		t.Errorf("no row: %v", rset.Err())
+	}
+	if err = tx.CommitContext(ctx); err != nil {
+		t.Error(err)
+	}
+}
+
 func Benchmark_MultiInsert(b *testing.B) {
 	testSes := getSes(b)
 	defer testSes.Close()
@@ -6,6 +6,7 @@ package ora_test
 
 import (
 	"fmt"
+	"io"
 	"strings"
 	"testing"
 
@@ -251,3 +252,119 @@ func TestStringSlice(t *testing.T) {
 	t.Log(rset.Row[0])
 
 }
+
+func TestLong_piecewise(t *testing.T) {
+	t.Parallel()
+	testSes := getSes(t)
+	defer testSes.Close()
+	for _, tc := range []struct {
+		typ string
+		gct ora.GoColumnType
+	}{
+		{"LONG", ora.OraS},
+		{"LONG RAW", ora.OraBin},
+	} {
+		tbl := tableName()
+		qry := "CREATE TABLE " + tbl + " (id NUMBER(3), data " + tc.typ + ")"
+		if _, err := testSes.PrepAndExe(qry); err != nil {
+			t.Fatal(qry, err)
+		}
+		defer testSes.PrepAndExe("DROP TABLE " + tbl)
+
+		want := []string{"", "short", strings.Repeat("0123456789", 300), strings.Repeat("árvíztűrő ", 2000)}
+		qry = "INSERT INTO " + tbl + " (id, data) VALUES (:1, :2)"
+		for i, s := range want {
+			var data interface{} = s
+			if tc.gct == ora.OraBin {
+				data = []byte(s)
+			}
+			if _, err := testSes.PrepAndExe(qry, i, data); err != nil {
+				t.Fatal(qry, i, err)
+			}
+		}
+
+		qry = "SELECT data FROM " + tbl + " ORDER BY id"
+		stmt, err := testSes.Prep(qry, tc.gct)
+		if err != nil {
+			t.Fatal(qry, err)
+		}
+		defer stmt.Close()
+		// much smaller than the values, to fetch them in many pieces
+		stmt.SetCfg(stmt.Cfg().SetLongBufferSize(1000).SetLongRawBufferSize(1000))
+		rset, err := stmt.Qry()
+		if err != nil {
+			t.Fatal(qry, err)
+		}
+		var i int
+		for ; rset.Next(); i++ {
+			var got string
+			var isNull bool
+			switch x := rset.Row[0].(type) {
+			case ora.String:
+				got, isNull = x.Value, x.IsNull
+			case ora.Raw:
+				got, isNull = string(x.Value), x.IsNull
+			default:
+				t.Fatalf("%s: %d. got %T", tc.typ, i, rset.Row[0])
+			}
+			if want[i] == "" {
+				if !isNull {
+					t.Errorf("%s: %d. wanted NULL, got %q", tc.typ, i, got)
+				}
+				continue
+			}
+			if got != want[i] {
+				t.Errorf("%s: %d. got %d bytes, wanted %d", tc.typ, i, len(got), len(want[i]))
+			}
+		}
+		if err := rset.Err(); err != nil {
+			t.Fatal(tc.typ, err)
+		}
+		if i != len(want) {
+			t.Errorf("%s: got %d rows, wanted %d", tc.typ, i, len(want))
+		}
+	}
+}
+
+func TestNString_session(t *testing.T) {
+	t.Parallel()
+	testSes := getSes(t)
+	defer testSes.Close()
+	tbl := tableName()
+	qry := "CREATE TABLE " + tbl + " (nv NVARCHAR2(100), nc NCHAR(30), ncl NCLOB)"
+	if _, err := testSes.PrepAndExe(qry); err != nil {
+		t.Fatal(qry, err)
+	}
+	defer testSes.PrepAndExe("DROP TABLE " + tbl)
+
+	// not representable in most database character sets
+	want := "árvíztűrő ≠ 日本"
+	qry = "INSERT INTO " + tbl + " (nv, nc, ncl) VALUES (:1, :2, :3)"
+	if _, err := testSes.PrepAndExe(qry, ora.NString(want), ora.NString(want), ora.NString(want)); err != nil {
+		t.Fatal(qry, err)
+	}
+
+	qry = "SELECT nv, nc, ncl, ncl FROM " + tbl
+	rset, err := testSes.PrepAndQry(qry, ora.S, ora.S, ora.S, ora.Loc)
+	if err != nil {
+		t.Fatal(qry, err)
+	}
+	if !rset.Next() {
+		t.Fatal(qry, "no rows", rset.Err())
+	}
+	for i, name := range []string{"NVARCHAR2", "NCHAR", "NCLOB"} {
+		if got := strings.TrimRight(rset.Row[i].(string), " "); got != want {
+			t.Errorf("%s: got %q, wanted %q", name, got, want)
+		}
+	}
+	loc := rset.Row[3].(*ora.LobLocator)
+	p := make([]byte, 4*len(want))
+	n, _, err := loc.ReadCharsAt(p, 0)
+	if err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	if got := string(p[:n]); got != want {
+		t.Errorf("NCLOB locator: got %q, wanted %q", got, want)
+	}
+	rset.Exhaust()
+}